@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var abortCmd = &cobra.Command{
+	Use:               "abort [job_url]",
+	Short:             "Abort a running Jenkins build",
+	Long:              `Abort a running Jenkins build and stop monitoring it.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := config.GetCredentials()
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+
+		aborted, err := abortBuild(os.Stdout, config.NewDiskStore(), args[0], token)
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error aborting build: %v", err)))
+			os.Exit(1)
+		}
+
+		if aborted && signalDaemonIfRunning() {
+			fmt.Println("Daemon signaled to stop monitoring the job.")
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(abortCmd)
+}
+
+// abortBuild stops the build at jobURL and removes it from monitoring. It
+// reports whether a stop request was sent; builds that already finished are
+// left alone with a warning.
+func abortBuild(w io.Writer, store config.ConfigStore, jobURL, token string) (bool, error) {
+	info, err := jenkins.GetBuildInfo(jobURL, token)
+	if err != nil {
+		return false, err
+	}
+
+	if !info.Building && info.Result != "" {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Build is not running (result: %s); nothing to abort.", info.Result)))
+		return false, nil
+	}
+
+	if err := jenkins.StopBuild(info.URL, token); err != nil {
+		return false, err
+	}
+
+	if err := store.Update(func(cfg *config.Config) error {
+		cfg.RemoveJob(jobURL)
+		cfg.RemoveJob(info.URL)
+		return nil
+	}); err != nil {
+		return true, fmt.Errorf("build aborted but config could not be saved: %w", err)
+	}
+
+	fmt.Fprintln(w, ui.GreenText("Build aborted: "+info.URL))
+	return true, nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAbortServer fakes a Jenkins build whose API reports the given state and
+// records every request made against the stop endpoint.
+func newAbortServer(t *testing.T, building bool, result string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var stops []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/crumbIssuer/api/xml":
+			fmt.Fprint(w, "Jenkins-Crumb:abc123")
+		case "/job/test/5/api/json":
+			assert.Equal(t, "url,building,result", r.URL.Query().Get("tree"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"url":%q,"building":%t,"result":%q}`, server.URL+"/job/test/5/", building, result)
+		case "/job/test/5/stop":
+			assert.Equal(t, "abc123", r.Header.Get("Jenkins-Crumb"))
+			stops = append(stops, r.Method)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &stops
+}
+
+func TestAbortBuild(t *testing.T) {
+	server, stops := newAbortServer(t, true, "")
+	jobURL := server.URL + "/job/test/5"
+	store := newMemStore(config.Job{URL: jobURL})
+
+	var out bytes.Buffer
+	aborted, err := abortBuild(&out, store, jobURL, "token")
+	require.NoError(t, err)
+	assert.True(t, aborted)
+	assert.Equal(t, []string{http.MethodPost}, *stops)
+	assert.Contains(t, out.String(), "Build aborted")
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.HasJob(jobURL))
+}
+
+func TestAbortBuild_NotBuilding(t *testing.T) {
+	server, stops := newAbortServer(t, false, "SUCCESS")
+	jobURL := server.URL + "/job/test/5"
+	store := newMemStore(config.Job{URL: jobURL})
+
+	var out bytes.Buffer
+	aborted, err := abortBuild(&out, store, jobURL, "token")
+	require.NoError(t, err)
+	assert.False(t, aborted)
+	assert.Empty(t, *stops)
+	assert.Contains(t, out.String(), "not running")
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(jobURL))
+}
+
+func TestAbortBuild_NotFound(t *testing.T) {
+	server, _ := newAbortServer(t, true, "")
+
+	_, err := abortBuild(&bytes.Buffer{}, config.NewMemoryStore(), server.URL+"/job/missing/1", "token")
+	assert.ErrorContains(t, err, "404")
+}
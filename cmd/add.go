@@ -1,59 +1,542 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/notify"
 	"jenkins-monitor/pkg/ui"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	addInterval time.Duration
+	addFile     string
+	addProfile  string
+	addMaxDur   time.Duration
+	addTTLHours float64
+	addDryRun   bool
+	addJSON     bool
+	addValidate bool
+	addRecur    bool
+	addNote     string
+	addRetries  int
+	addLabels   []string
+	addWait     bool
+	addTimeout  time.Duration
+	addYes      bool
+)
+
 var addCmd = &cobra.Command{
-	Use:   "add [job_url]",
-	Short: "Add a Jenkins job to monitor",
-	Args:  cobra.ExactArgs(1),
+	Use:   "add [job_url...]",
+	Short: "Add one or more Jenkins jobs to monitor",
+	Long: `Add one or more Jenkins jobs to monitor. URLs can be given as arguments and/or read one per line from --file (use - for stdin).
+
+jw asks before adding a job on another Jenkins server than the configured one,
+unless --yes is given. With --file -, it asks on the terminal.
+
+With --wait, a single job is watched in the foreground instead, like jw watch,
+and jw exits once the build finishes: 0 for SUCCESS or UNSTABLE, 1 otherwise and
+2 if --timeout passes first, e.g. jw add --wait $url && ./deploy.sh`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if addFile == "" {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		if _, err := config.GetCredentials(); err != nil {
+		token, err := config.GetProfileCredentials(addProfile)
+		if err != nil {
 			fmt.Println(ui.RedText("Error: " + err.Error()))
 			os.Exit(1)
 		}
 
-		jobURL := args[0]
-		if !strings.HasPrefix(jobURL, "http://") && !strings.HasPrefix(jobURL, "https://") {
-			fmt.Println(ui.RedText("Error: Job URL must start with http:// or https://"))
+		jobURLs := append([]string{}, args...)
+		if addFile != "" {
+			fileURLs, err := readURLsFromFile(addFile, os.Stdin)
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error reading %s: %v", addFile, err)))
+				os.Exit(1)
+			}
+			jobURLs = append(jobURLs, fileURLs...)
+		}
+
+		var baseURL string
+		if creds, err := config.LoadProfile(addProfile); err == nil {
+			baseURL = creds.BaseURL
+		}
+
+		for i, jobURL := range jobURLs {
+			canonical, err := jenkins.NormalizeJobURL(normalizeJobURL(jobURL, baseURL))
+			if err != nil {
+				fmt.Println(ui.RedText("Error: " + err.Error()))
+				os.Exit(1)
+			}
+			if canonical != jobURL {
+				fmt.Println(ui.MutedText("Using canonical URL: " + canonical))
+			}
+			jobURLs[i] = canonical
+		}
+
+		// Flags are checked before any prompt or network call so a bad one
+		// always fails straight away.
+		if addInterval < 0 {
+			fmt.Println(ui.RedText("Error: --interval must not be negative"))
+			os.Exit(1)
+		}
+		if addInterval != 0 && addInterval < monitor.MinPollInterval {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error: --interval must be at least %s", monitor.MinPollInterval)))
+			os.Exit(1)
+		}
+		if addMaxDur != 0 && addMaxDur < time.Minute {
+			fmt.Println(ui.RedText("Error: --max-duration must be at least 1m"))
 			os.Exit(1)
 		}
 
-		store := config.NewDiskStore()
-		cfg, err := store.Load()
-		if err != nil {
-			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+		if addRetries < 0 {
+			fmt.Println(ui.RedText("Error: --max-retries must not be negative"))
 			os.Exit(1)
 		}
 
-		if cfg.HasJob(jobURL) {
-			fmt.Println(ui.YellowText("Job is already being monitored: " + jobURL))
+		if addTTLHours < 0 {
+			fmt.Println(ui.RedText("Error: --ttl-hours must not be negative"))
+			os.Exit(1)
+		}
+
+		if addWait && (len(jobURLs) != 1 || addDryRun || addRecur) {
+			fmt.Println(ui.RedText("Error: --wait takes exactly one job URL and cannot be combined with --dry-run or --recurring"))
+			os.Exit(1)
+		}
+		if addWait {
+			// --wait does not save the job, so these would be silently lost.
+			for _, name := range []string{"note", "label", "ttl-hours", "max-duration", "max-retries"} {
+				if cmd.Flags().Changed(name) {
+					fmt.Println(ui.RedText(fmt.Sprintf("Error: --%s cannot be combined with --wait", name)))
+					os.Exit(1)
+				}
+			}
+		}
+		if addTimeout < 0 {
+			fmt.Println(ui.RedText("Error: --timeout must not be negative"))
+			os.Exit(1)
+		}
+		if addTimeout > 0 && !addWait {
+			fmt.Println(ui.RedText("Error: --timeout only applies with --wait"))
+			os.Exit(1)
+		}
+
+		askForeign := !addDryRun && !addYes && len(foreignHosts(jobURLs, baseURL)) > 0
+		answers := bufio.NewReader(os.Stdin)
+		if addFile == "-" && (askForeign || addValidate) {
+			// Stdin held the URLs, so answers come from the terminal.
+			tty, err := os.Open("/dev/tty")
+			if err != nil {
+				fmt.Println(ui.RedText("Error: --file - leaves no stdin to answer prompts on and there is no terminal; pass --yes to add jobs on other Jenkins servers without asking"))
+				os.Exit(1)
+			}
+			defer tty.Close()
+			answers = bufio.NewReader(tty)
+		}
+		if askForeign {
+			jobURLs = confirmForeignHosts(answers, os.Stdout, jobURLs, baseURL)
+		}
+		if addValidate {
+			var valid []string
+			for _, jobURL := range jobURLs {
+				keep, err := validateJobURL(answers, os.Stdout, jobURL, token)
+				if err != nil {
+					fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+					os.Exit(1)
+				}
+				if keep {
+					valid = append(valid, jobURL)
+				}
+			}
+			jobURLs = valid
+		}
+		if len(jobURLs) == 0 {
 			return
 		}
 
-		cfg.AddJob(jobURL)
+		opts := jobOptions{
+			interval:    addInterval,
+			profile:     addProfile,
+			maxDuration: addMaxDur,
+			ttlHours:    addTTLHours,
+			recurring:   addRecur,
+			note:        addNote,
+			maxRetries:  addRetries,
+			labels:      addLabels,
+		}
+		if addDryRun {
+			report, err := previewAdd(config.NewDiskStore(), jobURLs, opts)
+			if err == nil {
+				err = writeDryRunReport(os.Stdout, report, addJSON)
+			}
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			return
+		}
 
-		if err := store.Save(cfg); err != nil {
+		if addWait {
+			store := config.NewDiskStore()
+			cfg, err := store.Load()
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			interval := watchPollInterval
+			if addInterval > 0 {
+				interval = addInterval
+			}
+
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+
+			ctx, cancel := watchContext(addTimeout)
+			defer cancel()
+			os.Exit(waitForJob(ctx, os.Stdout, store, buildNotifier(cfg), jobURLs[0], token, addProfile, interval, addTimeout, interrupt))
+		}
+
+		opts.triggerCause = buildCauseFetcher(token)
+		added, err := addJobs(os.Stdout, config.NewDiskStore(), jobURLs, opts)
+		if err != nil {
 			fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
 			os.Exit(1)
 		}
 
-		fmt.Println(ui.GreenText("Added job to config: " + jobURL))
-
-		if signalDaemonReload() {
-			fmt.Println("Daemon signaled to monitor the new job.")
+		if added > 0 && signalDaemonReload() {
+			fmt.Println("Daemon signaled to monitor the new job(s).")
 		}
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(addCmd)
+	addCmd.Flags().DurationVar(&addInterval, "interval", 0, "Poll interval for this job (e.g. 1m); defaults to the daemon interval")
+	addCmd.Flags().StringVarP(&addFile, "file", "f", "", "Read job URLs from a file, one per line (- for stdin)")
+	addCmd.Flags().DurationVar(&addMaxDur, "max-duration", 0, "Alert once if a build is still running this long after being added (e.g. 45m)")
+	addCmd.Flags().Float64Var(&addTTLHours, "ttl-hours", 0, "Stop monitoring the job(s) after this many hours (0 = never)")
+	addCmd.Flags().StringVar(&addProfile, "profile", config.DefaultProfile, "Credential profile used to poll the job(s)")
+	addCmd.Flags().StringVar(&addNote, "note", "", "Note on why the job(s) are watched, shown in status and notifications")
+	addCmd.Flags().BoolVar(&addRecur, "recurring", false, "Keep monitoring after a build finishes and report the next build too (the job is watched through its lastBuild URL)")
+	addCmd.Flags().IntVar(&addRetries, "max-retries", 0, fmt.Sprintf("Failed status checks in a row before the job is marked failed and an alert is sent (default %d)", config.DefaultMaxRetries))
+	addCmd.Flags().StringArrayVar(&addLabels, "label", nil, "Label the job(s), e.g. by project or team; repeat for several")
+	addCmd.Flags().BoolVar(&addValidate, "validate", false, "Check each URL against Jenkins before adding it")
+	addCmd.Flags().BoolVar(&addWait, "wait", false, "Watch the job until the build finishes and exit with its outcome (0 for SUCCESS/UNSTABLE)")
+	addCmd.Flags().DurationVar(&addTimeout, "timeout", 0, "With --wait, give up and exit 2 if the build has not finished after this long (e.g. 30m)")
+	addCmd.Flags().BoolVarP(&addDryRun, "dry-run", "n", false, "Show what would be added without changing the config")
+	addCmd.Flags().BoolVar(&addJSON, "json", false, "With --dry-run, print the preview as JSON")
+	addCmd.Flags().BoolVarP(&addYes, "yes", "y", false, "Add jobs on other Jenkins servers than the configured one without asking")
+}
+
+// jobOptions holds the per-job settings applied by addJobs.
+type jobOptions struct {
+	interval    time.Duration
+	profile     string
+	maxDuration time.Duration
+	ttlHours    float64
+	recurring   bool
+	note        string
+	maxRetries  int
+	labels      []string
+	// triggerCause, if set, looks up who or what started a build.
+	triggerCause func(jobURL string) string
+}
+
+// waitForJob watches jobURL until the build finishes and sends the
+// finished-build notification, which is skipped during quiet hours. It
+// returns the exit code for jw add --wait: 0 if the build succeeded or was
+// unstable, 1 otherwise and exitTimeout if ctx expires after timeout.
+// Interrupting it is handled like jw watch, with profile used if the job is
+// handed to the daemon; interrupt stops receiving signals first, so a second
+// Ctrl-C aborts the question.
+func waitForJob(ctx context.Context, w io.Writer, store config.ConfigStore, notifier notify.Notifier, jobURL, token, profile string, interval, timeout time.Duration, interrupt chan os.Signal) int {
+	result, err := watchJob(ctx, jobURL, token, interval, interrupt)
+	signal.Stop(interrupt)
+	if errors.Is(err, errWatchTimeout) {
+		return watchTimedOut(w, store, notifier, jobURL, timeout)
+	}
+	if err != nil {
+		return finishWatch(jobURL, profile, result, err)
+	}
+
+	if !quietForWatch(w, store) {
+		_, notificationTitle := finishedNotification("", result)
+		title, message := renderFinishedNotification(store, logging.TextLogger(w), config.NotificationData{
+			Title:   notificationTitle,
+			JobName: jobDisplayName(jobURL),
+			JobURL:  jobURL,
+			Result:  result,
+		})
+		if err := notifier.Send(notify.Notification{Title: title, Message: message, URL: jobURL, JobName: jobDisplayName(jobURL), Result: result}); err != nil {
+			fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Failed to send notification: %v", err)))
+		}
+	}
+
+	line := fmt.Sprintf("%s finished: %s", jobDisplayName(jobURL), result)
+	if result == "SUCCESS" || result == "UNSTABLE" {
+		fmt.Fprintln(w, ui.GreenText(line))
+		return 0
+	}
+	fmt.Fprintln(w, ui.RedText(line))
+	return 1
+}
+
+// buildCauseFetcher returns a jobOptions.triggerCause that asks Jenkins. A
+// cause that cannot be fetched is left empty rather than failing the add.
+func buildCauseFetcher(token string) func(string) string {
+	return func(jobURL string) string {
+		cause, err := jenkins.GetBuildCause(jobURL, token)
+		if err != nil {
+			return ""
+		}
+		return cause
+	}
+}
+
+// maxCauseFetches bounds how many trigger causes addJobs asks Jenkins for at
+// once.
+const maxCauseFetches = 4
+
+// addJobs adds every URL not already monitored in a single config update and
+// reports each outcome to w. It returns how many jobs were newly added.
+func addJobs(w io.Writer, store config.ConfigStore, jobURLs []string, opts jobOptions) (int, error) {
+	causes := make(map[string]string)
+	if opts.triggerCause != nil {
+		cfg, err := store.Load()
+		if err != nil {
+			return 0, err
+		}
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, maxCauseFetches)
+		)
+		for _, jobURL := range jobURLs {
+			if cfg.HasJob(jobURL) {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				cause := opts.triggerCause(jobURL)
+				<-sem
+				mu.Lock()
+				causes[jobURL] = cause
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+	}
+
+	var added, duplicates []string
+	err := store.Update(func(cfg *config.Config) error {
+		added, duplicates = applyAdd(cfg, jobURLs, opts, causes)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, jobURL := range duplicates {
+		fmt.Fprintln(w, ui.YellowText("Job is already being monitored: "+jobURL))
+	}
+	for _, jobURL := range added {
+		fmt.Fprintln(w, ui.GreenText("Added job to config: "+jobURL))
+	}
+	return len(added), nil
+}
+
+// applyAdd adds every URL in jobURLs not already in cfg, with the settings
+// from opts and the trigger causes looked up in causes. Recurring jobs are
+// added as the job's lastBuild URL, the only one that moves on to the next
+// build.
+func applyAdd(cfg *config.Config, jobURLs []string, opts jobOptions, causes map[string]string) (added, duplicates []string) {
+	for _, jobURL := range jobURLs {
+		cause := causes[jobURL]
+		if opts.recurring {
+			jobURL = jenkins.LastBuildURL(jobURL)
+		}
+		if cfg.HasJob(jobURL) {
+			duplicates = append(duplicates, jobURL)
+			continue
+		}
+		cfg.AddJob(jobURL)
+		job := cfg.Jobs[jobURL]
+		if opts.interval > 0 {
+			job.PollInterval = opts.interval
+		}
+		if opts.profile != config.DefaultProfile {
+			job.Profile = opts.profile
+		}
+		if opts.maxDuration > 0 {
+			job.MaxDurationMinutes = int(math.Ceil(opts.maxDuration.Minutes()))
+		}
+		job.MaxMonitorHours = opts.ttlHours
+		job.TriggerCause = cause
+		job.Recurring = opts.recurring
+		job.Notes = opts.note
+		job.MaxRetries = opts.maxRetries
+		job.Labels = uniqueLabels(opts.labels)
+		cfg.Jobs[jobURL] = job
+		added = append(added, jobURL)
+	}
+	return added, duplicates
+}
+
+// uniqueLabels drops empty and repeated labels, keeping the first occurrence
+// of each.
+func uniqueLabels(labels []string) []string {
+	var out []string
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label != "" && !slices.Contains(out, label) {
+			out = append(out, label)
+		}
+	}
+	return out
+}
+
+// previewAdd reports what addJobs would do without saving anything.
+func previewAdd(store config.ConfigStore, jobURLs []string, opts jobOptions) (dryRunReport, error) {
+	cfg, err := store.Load()
+	if err != nil {
+		return dryRunReport{}, err
+	}
+	added, duplicates := applyAdd(cfg, jobURLs, opts, nil)
+	report := newDryRunReport(cfg)
+	report.Add, report.Unchanged = added, duplicates
+	return report, nil
+}
+
+// validateJobURL checks that jobURL is a Jenkins job token can read. A job
+// that is not building is only kept if the user confirms on r.
+func validateJobURL(r io.Reader, w io.Writer, jobURL, token string) (bool, error) {
+	status, code, err := jenkins.GetJobStatus(jobURL, token)
+	switch {
+	case code == http.StatusNotFound:
+		return false, fmt.Errorf("job not found (404), check the URL: %s", jobURL)
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return false, fmt.Errorf("not authorized (%d), check your credentials: %s", code, jobURL)
+	case err != nil:
+		return false, fmt.Errorf("cannot reach %s: %w", jobURL, err)
+	}
+	if status.Building {
+		return true, nil
+	}
+	fmt.Fprintln(w, ui.YellowText("Job exists but is not building: "+jobURL))
+	return confirm(r, w, "Watch anyway?"), nil
+}
+
+// normalizeJobURL fills in what is missing from jobURL using the configured
+// Jenkins base URL: a bare path like "job/foo/1" is joined onto baseURL, and a
+// host without a scheme gets baseURL's scheme.
+func normalizeJobURL(jobURL, baseURL string) string {
+	if baseURL == "" || strings.Contains(jobURL, "://") {
+		return jobURL
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme == "" {
+		return jobURL
+	}
+
+	if strings.HasPrefix(jobURL, "/") || strings.HasPrefix(jobURL, "job/") {
+		return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(jobURL, "/")
+	}
+	return base.Scheme + "://" + jobURL
+}
+
+// confirmForeignHosts asks before keeping URLs whose host differs from the
+// configured Jenkins base URL and returns the URLs to add.
+func confirmForeignHosts(in io.Reader, out io.Writer, jobURLs []string, baseURL string) []string {
+	foreign := foreignHosts(jobURLs, baseURL)
+	if len(foreign) == 0 {
+		return jobURLs
+	}
+
+	reader := bufio.NewReader(in)
+	kept := make([]string, 0, len(jobURLs))
+	for _, jobURL := range jobURLs {
+		if !slices.Contains(foreign, jobURL) {
+			kept = append(kept, jobURL)
+			continue
+		}
+
+		fmt.Fprintln(out, ui.YellowText(fmt.Sprintf("Warning: %s is not on the configured Jenkins server (%s).", jobURL, baseURL)))
+		fmt.Fprint(out, "Add it anyway? [y/N]: ")
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer == "y" || answer == "yes" {
+			kept = append(kept, jobURL)
+		} else {
+			fmt.Fprintln(out, "Skipped: "+jobURL)
+		}
+	}
+	return kept
+}
+
+// foreignHosts returns the URLs among jobURLs whose host differs from the
+// configured Jenkins server at baseURL, if one is configured.
+func foreignHosts(jobURLs []string, baseURL string) []string {
+	base, err := url.Parse(baseURL)
+	if baseURL == "" || err != nil || base.Host == "" {
+		return nil
+	}
+	var foreign []string
+	for _, jobURL := range jobURLs {
+		u, err := url.Parse(jobURL)
+		if err != nil || !strings.EqualFold(u.Host, base.Host) {
+			foreign = append(foreign, jobURL)
+		}
+	}
+	return foreign
+}
+
+// readURLsFromFile reads one URL per line from path, or from stdin when path
+// is "-". Blank lines and lines starting with # are skipped.
+func readURLsFromFile(path string, stdin io.Reader) ([]string, error) {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	return urls, scanner.Err()
 }
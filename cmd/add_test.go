@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddJobs_MultipleURLs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+
+	urls := []string{
+		"https://jenkins.example.com/job/a/1",
+		"https://jenkins.example.com/job/b/2",
+		"https://jenkins.example.com/job/c/3",
+	}
+
+	var buf bytes.Buffer
+	added, err := addJobs(&buf, store, urls, jobOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, added)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.Jobs, 3)
+	for _, u := range urls {
+		assert.True(t, cfg.HasJob(u), "expected %s to be monitored", u)
+		assert.Contains(t, buf.String(), "Added job to config: "+u)
+	}
+}
+
+func TestAddJobs_DuplicatesReportedPerURL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+
+	existing := "https://jenkins.example.com/job/a/1"
+	_, err := addJobs(&bytes.Buffer{}, store, []string{existing}, jobOptions{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	added, err := addJobs(&buf, store, []string{existing, "https://jenkins.example.com/job/b/2"}, jobOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Contains(t, buf.String(), "already being monitored: "+existing)
+	assert.Contains(t, buf.String(), "Added job to config: https://jenkins.example.com/job/b/2")
+}
+
+func TestAddJobs_Recurring(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+
+	jobURL := "https://jenkins.example.com/job/nightly/lastBuild"
+	_, err := addJobs(&bytes.Buffer{}, store, []string{jobURL}, jobOptions{recurring: true})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Jobs[jobURL].Recurring)
+}
+
+func TestAddJobs_RecurringFollowsLastBuild(t *testing.T) {
+	for name, jobURL := range map[string]string{
+		"job URL":   "https://jenkins.example.com/job/nightly",
+		"build URL": "https://jenkins.example.com/job/nightly/42",
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := config.NewMemoryStore()
+			var buf bytes.Buffer
+			_, err := addJobs(&buf, store, []string{jobURL}, jobOptions{recurring: true})
+			require.NoError(t, err)
+
+			cfg, err := store.Load()
+			require.NoError(t, err)
+			want := "https://jenkins.example.com/job/nightly/lastBuild"
+			require.Contains(t, cfg.Jobs, want)
+			assert.True(t, cfg.Jobs[want].Recurring)
+			assert.Len(t, cfg.Jobs, 1)
+			assert.Contains(t, buf.String(), "Added job to config: "+want)
+		})
+	}
+}
+
+func TestReadURLsFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	content := "https://jenkins/job/a/1\n\n# comment\n  https://jenkins/job/b/2  \n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	urls, err := readURLsFromFile(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://jenkins/job/a/1", "https://jenkins/job/b/2"}, urls)
+
+	urls, err = readURLsFromFile("-", strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://jenkins/job/a/1", "https://jenkins/job/b/2"}, urls)
+}
+
+func TestNormalizeJobURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobURL  string
+		baseURL string
+		want    string
+	}{
+		{"already absolute", "http://jenkins/job/a/1", "https://jenkins", "http://jenkins/job/a/1"},
+		{"missing scheme", "jenkins.example.com/job/a/1", "https://jenkins.example.com", "https://jenkins.example.com/job/a/1"},
+		{"bare job path", "job/a/1", "https://jenkins.example.com/", "https://jenkins.example.com/job/a/1"},
+		{"absolute path", "/job/a/1", "http://jenkins:8080", "http://jenkins:8080/job/a/1"},
+		{"no base URL", "jenkins/job/a/1", "", "jenkins/job/a/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeJobURL(tt.jobURL, tt.baseURL))
+		})
+	}
+}
+
+func TestConfirmForeignHosts(t *testing.T) {
+	urls := []string{
+		"https://jenkins.example.com/job/a/1",
+		"https://other.example.com/job/b/2",
+		"https://third.example.com/job/c/3",
+	}
+
+	var out bytes.Buffer
+	kept := confirmForeignHosts(strings.NewReader("y\nn\n"), &out, urls, "https://jenkins.example.com")
+
+	assert.Equal(t, []string{"https://jenkins.example.com/job/a/1", "https://other.example.com/job/b/2"}, kept)
+	assert.Contains(t, out.String(), "Warning: https://other.example.com/job/b/2 is not on the configured Jenkins server")
+	assert.Contains(t, out.String(), "Skipped: https://third.example.com/job/c/3")
+	assert.NotContains(t, out.String(), "Warning: https://jenkins.example.com")
+}
+
+func TestForeignHosts(t *testing.T) {
+	urls := []string{"https://JENKINS.example.com/job/a/1", "https://other.example.com/job/b/2"}
+	assert.Equal(t, []string{"https://other.example.com/job/b/2"}, foreignHosts(urls, "https://jenkins.example.com"))
+	assert.Empty(t, foreignHosts(urls, ""))
+}
+
+func TestConfirmForeignHosts_NoBaseURL(t *testing.T) {
+	urls := []string{"https://anywhere/job/a/1"}
+	var out bytes.Buffer
+	assert.Equal(t, urls, confirmForeignHosts(strings.NewReader(""), &out, urls, ""))
+	assert.Empty(t, out.String())
+}
+
+func TestAddJobs_Options(t *testing.T) {
+	store := config.NewMemoryStore()
+
+	_, err := addJobs(&bytes.Buffer{}, store, []string{"https://j/job/a/1"}, jobOptions{interval: time.Minute, profile: "staging"})
+	require.NoError(t, err)
+	_, err = addJobs(&bytes.Buffer{}, store, []string{"https://j/job/b/1"}, jobOptions{profile: config.DefaultProfile})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.Jobs["https://j/job/a/1"].PollInterval)
+	assert.Equal(t, "staging", cfg.Jobs["https://j/job/a/1"].Profile)
+	assert.Empty(t, cfg.Jobs["https://j/job/b/1"].Profile)
+}
+
+func TestAddJobs_MaxDuration(t *testing.T) {
+	store := config.NewMemoryStore()
+
+	_, err := addJobs(&bytes.Buffer{}, store, []string{"https://j/job/a/1"}, jobOptions{maxDuration: 90 * time.Second})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Jobs["https://j/job/a/1"].MaxDurationMinutes)
+}
+
+func TestAddJobs_TTLHours(t *testing.T) {
+	store := config.NewMemoryStore()
+
+	_, err := addJobs(&bytes.Buffer{}, store, []string{"https://j/job/a/1"}, jobOptions{ttlHours: 1.5})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, cfg.Jobs["https://j/job/a/1"].TTL())
+}
+
+func TestAddJobs_TriggerCause(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"actions":[{"causes":[{"shortDescription":"Started by user John Doe"}]}]}`)
+	}))
+	defer server.Close()
+
+	store := newMemStore(config.Job{URL: server.URL + "/job/old/1"})
+	jobURLs := []string{server.URL + "/job/a/1", server.URL + "/job/old/1"}
+	_, err := addJobs(&bytes.Buffer{}, store, jobURLs, jobOptions{triggerCause: buildCauseFetcher("token")})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", cfg.Jobs[server.URL+"/job/a/1"].TriggerCause)
+	assert.Empty(t, cfg.Jobs[server.URL+"/job/old/1"].TriggerCause, "existing jobs are left alone")
+}
+
+func TestAddJobs_TriggerCauseConcurrencyBound(t *testing.T) {
+	var running, peak atomic.Int32
+	fetch := func(jobURL string) string {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return "cause of " + jobURL
+	}
+
+	var jobURLs []string
+	for i := range 3 * maxCauseFetches {
+		jobURLs = append(jobURLs, fmt.Sprintf("https://j/job/a/%d", i+1))
+	}
+	store := config.NewMemoryStore()
+	added, err := addJobs(&bytes.Buffer{}, store, jobURLs, jobOptions{triggerCause: fetch})
+	require.NoError(t, err)
+	assert.Equal(t, len(jobURLs), added)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	for _, jobURL := range jobURLs {
+		assert.Equal(t, "cause of "+jobURL, cfg.Jobs[jobURL].TriggerCause)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(maxCauseFetches))
+	assert.Greater(t, peak.Load(), int32(1), "causes are fetched concurrently")
+}
+
+func TestValidateJobURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/job/running/1/api/json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"building":true}`)
+		case "/job/done/1/api/json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"building":false,"result":"SUCCESS"}`)
+		case "/job/secret/1/api/json":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		job     string
+		answer  string
+		keep    bool
+		wantErr string
+		prompt  bool
+	}{
+		{name: "building", job: "running/1", keep: true},
+		{name: "not building, confirmed", job: "done/1", answer: "y\n", keep: true, prompt: true},
+		{name: "not building, declined", job: "done/1", answer: "\n", prompt: true},
+		{name: "unauthorized", job: "secret/1", wantErr: "not authorized (401)"},
+		{name: "typo", job: "tpyo/1", wantErr: "job not found (404)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			keep, err := validateJobURL(strings.NewReader(tt.answer), &out, server.URL+"/job/"+tt.job, "token")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.keep, keep)
+			if tt.prompt {
+				assert.Contains(t, out.String(), "Job exists but is not building")
+				assert.Contains(t, out.String(), "Watch anyway? [y/N]")
+			} else {
+				assert.Empty(t, out.String())
+			}
+		})
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	tests := []struct {
+		result   string
+		wantCode int
+		wantText string
+	}{
+		{"SUCCESS", 0, "Jenkins Job Completed"},
+		{"UNSTABLE", 0, "Jenkins Job Completed"},
+		{"FAILURE", 1, "Jenkins Job Failed"},
+		{"ABORTED", 1, "Jenkins Job Completed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			var polls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := jenkins.JobStatus{Building: true}
+				if polls.Add(1) >= 2 {
+					status = jenkins.JobStatus{Result: tt.result}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(status)
+			}))
+			defer server.Close()
+
+			jobURL := server.URL + "/job/app/1"
+			notifier := &recordingNotifier{}
+			var out bytes.Buffer
+			code := waitForJob(context.Background(), &out, newMemStore(), notifier, jobURL, "token", config.DefaultProfile, 10*time.Millisecond, 0, make(chan os.Signal))
+
+			assert.Equal(t, tt.wantCode, code)
+			assert.EqualValues(t, 2, polls.Load())
+			assert.Contains(t, out.String(), "app/1 finished: "+tt.result)
+			calls := notifier.getCalls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, tt.wantText, calls[0].Title)
+			assert.Contains(t, calls[0].Message, tt.result)
+			assert.Equal(t, jobURL, calls[0].URL)
+		})
+	}
+}
+
+func TestWaitForJob_QuietHours(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Result: "SUCCESS"})
+	}))
+	defer server.Close()
+
+	store := newMemStore()
+	now := time.Now().UTC()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.QuietHours = config.QuietHours{
+			Start:    now.Add(-time.Hour).Format("15:04"),
+			End:      now.Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		}
+		return nil
+	}))
+	notifier := &recordingNotifier{}
+	var out bytes.Buffer
+	code := waitForJob(context.Background(), &out, store, notifier, server.URL+"/job/app/1", "token", config.DefaultProfile, 10*time.Millisecond, 0, make(chan os.Signal))
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, notifier.getCalls())
+	assert.Contains(t, out.String(), "Quiet hours")
+}
+
+func TestWaitForJob_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	jobURL := server.URL + "/job/app/1"
+	timeout := 50 * time.Millisecond
+	ctx, cancel := watchContext(timeout)
+	defer cancel()
+	notifier := &recordingNotifier{}
+	var out bytes.Buffer
+	start := time.Now()
+	code := waitForJob(ctx, &out, newMemStore(), notifier, jobURL, "token", config.DefaultProfile, 10*time.Millisecond, timeout, make(chan os.Signal))
+
+	assert.Equal(t, exitTimeout, code)
+	assert.GreaterOrEqual(t, time.Since(start), timeout)
+	assert.Contains(t, out.String(), "app/1 still building after 50ms")
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Build timed out", calls[0].Title)
+	assert.Equal(t, jobURL, calls[0].URL)
+}
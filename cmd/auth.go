@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"syscall"
@@ -16,21 +18,63 @@ import (
 )
 
 var authCmd = &cobra.Command{
-	Use:   "auth",
+	Use:   "auth [jenkins_url]",
 	Short: "Authenticate with Jenkins",
-	Long:  `Authenticate with Jenkins by providing your username and password. This will generate an API token and save it locally.`,
-	Run:   runAuth,
+	Long: `Authenticate with Jenkins by providing your username and password. This will generate an API token and save it locally.
+
+The Jenkins URL, username and password are prompted for unless given by the URL
+argument or the JENKINS_URL, JENKINS_USER and JENKINS_PASSWORD environment
+variables, so jw auth can run non-interactively.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAuth,
+}
+
+var authRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Delete the stored Jenkins credentials",
+	Long: `Delete the stored Jenkins credentials from the config directory. With --revoke the
+API token is also revoked on the Jenkins server so it can no longer be used.`,
+	Args: cobra.NoArgs,
+	Run:  runAuthRemove,
+}
+
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored credential profiles",
+	Args:  cobra.NoArgs,
+	Run:   runAuthList,
 }
 
+var (
+	authProfile      string
+	authTest         bool
+	authKeychain     bool
+	authRemoveRevoke bool
+	authRemoveYes    bool
+)
+
 func init() {
 	RootCmd.AddCommand(authCmd)
+	authCmd.PersistentFlags().StringVar(&authProfile, "profile", config.DefaultProfile, "Credential profile to use")
+	authCmd.Flags().BoolVar(&authTest, "test", false, "Verify the saved credentials against Jenkins instead of authenticating")
+	authCmd.Flags().BoolVar(&authKeychain, "keychain", false, "Store the credentials in the system keychain instead of a file")
+
+	authCmd.AddCommand(authListCmd)
+	authCmd.AddCommand(authRemoveCmd)
+	authRemoveCmd.Flags().BoolVar(&authRemoveRevoke, "revoke", false, "Also revoke the API token on the Jenkins server")
+	authRemoveCmd.Flags().BoolVarP(&authRemoveYes, "yes", "y", false, "Skip the confirmation prompt")
 }
 
 func runAuth(cmd *cobra.Command, args []string) {
+	if authTest {
+		runAuthTest()
+		return
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	// Check if credentials already exist
-	if existing, err := config.LoadCredentials(); err == nil && existing != nil {
+	if existing, err := config.LoadProfile(authProfile); err == nil && existing != nil {
 		fmt.Printf("Credentials already exist for user %s. Refresh? [y/N]: ", ui.YellowText(existing.Username))
 		answer, _ := reader.ReadString('\n')
 		answer = strings.TrimSpace(strings.ToLower(answer))
@@ -40,56 +84,201 @@ func runAuth(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// 1. Get Jenkins URL
-	fmt.Print("Enter Jenkins URL (e.g. https://jenkins.example.com): ")
-	jenkinsURL, _ := reader.ReadString('\n')
-	jenkinsURL = strings.TrimSpace(jenkinsURL)
-	if jenkinsURL == "" {
-		fmt.Println(ui.RedText("Error: Jenkins URL is required"))
+	if err := authenticate(reader, os.Stdout, args, authProfile, authKeychain, readTerminalPassword); err != nil {
+		fmt.Println(ui.RedText("Error: " + err.Error()))
 		os.Exit(1)
 	}
-	jenkinsURL = strings.TrimRight(jenkinsURL, "/")
+}
 
-	// 2. Get Username
-	fmt.Print("Enter Jenkins Username: ")
-	username, _ := reader.ReadString('\n')
-	username = strings.TrimSpace(username)
-	if username == "" {
-		fmt.Println(ui.RedText("Error: Username is required"))
-		os.Exit(1)
-	}
+func readTerminalPassword() ([]byte, error) {
+	return term.ReadPassword(int(syscall.Stdin))
+}
 
-	// 3. Get Password
-	fmt.Print("Enter Jenkins Password (hidden): ")
-	bytePassword, err := term.ReadPassword(int(syscall.Stdin))
+// authenticate runs the jw auth flow for profile: it collects the inputs with
+// readAuthInputs, generates an API token and saves it, to the keychain if
+// keychain is set.
+func authenticate(r *bufio.Reader, w io.Writer, args []string, profile string, keychain bool, readPassword func() ([]byte, error)) error {
+	inputs, err := readAuthInputs(args, r, w, readPassword)
 	if err != nil {
-		fmt.Println(ui.RedText("\nError reading password: " + err.Error()))
-		os.Exit(1)
+		return err
 	}
-	password := string(bytePassword)
-	fmt.Println()
+	jenkinsURL, username, password := inputs.URL, inputs.Username, inputs.Password
 
 	// 4. Authenticate and Generate Token
 	spinner := ui.NewSpinner("Fetching token")
 	spinner.Start()
 	newToken, err := jenkins.AuthenticateAndGenerateToken(jenkinsURL, username, password)
 	spinner.Stop()
-
 	if err != nil {
-		fmt.Println(ui.RedText(err.Error()))
-		os.Exit(1)
+		return err
 	}
 
 	// 5. Save Credentials
 	creds := &config.Credentials{
-		Username: username,
-		Token:    newToken,
+		Username:  username,
+		Token:     newToken.Value,
+		TokenUUID: newToken.UUID,
+		BaseURL:   jenkinsURL,
+	}
+
+	if keychain {
+		if err := config.SaveProfileToKeychain(profile, creds); err != nil {
+			return fmt.Errorf("saving credentials to keychain: %w", err)
+		}
+		fmt.Fprintln(w, ui.GreenText("Success! Credentials saved to the system keychain"))
+		return nil
+	}
+
+	if err := config.SaveProfile(profile, creds); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+
+	path, err := config.GetCredentialsPath()
+	if err != nil {
+		path = "the config directory"
+	}
+	fmt.Fprintln(w, ui.GreenText("Success! Credentials saved to "+path))
+	return nil
+}
+
+// authInputs is what jw auth needs to generate an API token.
+type authInputs struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// readAuthInputs collects the Jenkins URL, username and password. Each comes
+// from, in order: the URL argument, the JENKINS_URL, JENKINS_USER and
+// JENKINS_PASSWORD environment variables, or a prompt on w read from r (the
+// password via readPassword, without echo).
+func readAuthInputs(args []string, r *bufio.Reader, w io.Writer, readPassword func() ([]byte, error)) (authInputs, error) {
+	var in authInputs
+
+	// 1. Get Jenkins URL
+	switch {
+	case len(args) == 1:
+		in.URL = strings.TrimSpace(args[0])
+	case os.Getenv("JENKINS_URL") != "":
+		in.URL = strings.TrimSpace(os.Getenv("JENKINS_URL"))
+		fmt.Fprintf(w, "Using Jenkins URL from JENKINS_URL: %s\n", in.URL)
+	default:
+		fmt.Fprint(w, "Enter Jenkins URL (e.g. https://jenkins.example.com): ")
+		in.URL, _ = r.ReadString('\n')
+		in.URL = strings.TrimSpace(in.URL)
+	}
+	if in.URL == "" {
+		return in, errors.New("Jenkins URL is required")
+	}
+	in.URL = strings.TrimRight(in.URL, "/")
+
+	// 2. Get Username
+	if user := os.Getenv("JENKINS_USER"); user != "" {
+		in.Username = strings.TrimSpace(user)
+		fmt.Fprintf(w, "Using Jenkins username from JENKINS_USER: %s\n", in.Username)
+	} else {
+		fmt.Fprint(w, "Enter Jenkins Username: ")
+		in.Username, _ = r.ReadString('\n')
+		in.Username = strings.TrimSpace(in.Username)
 	}
+	if in.Username == "" {
+		return in, errors.New("username is required")
+	}
+
+	// 3. Get Password
+	if password := os.Getenv("JENKINS_PASSWORD"); password != "" {
+		in.Password = password
+		return in, nil
+	}
+	fmt.Fprint(w, "Enter Jenkins Password (hidden): ")
+	password, err := readPassword()
+	fmt.Fprintln(w)
+	if err != nil {
+		return in, fmt.Errorf("reading password: %w", err)
+	}
+	in.Password = string(password)
+	return in, nil
+}
+
+func runAuthTest() {
+	creds, err := config.LoadProfile(authProfile)
+	if err != nil {
+		fmt.Println(ui.RedText("Error loading credentials: " + err.Error()))
+		fmt.Println("Run 'jw auth' first.")
+		os.Exit(1)
+	}
+	if creds.BaseURL == "" {
+		fmt.Println(ui.RedText("Error: saved credentials have no Jenkins URL. Run 'jw auth' again to store it."))
+		os.Exit(1)
+	}
+
+	if err := jenkins.VerifyCredentials(creds.BaseURL, creds.EncodedToken()); err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Credentials for %s at %s are not valid: %v", creds.Username, creds.BaseURL, err)))
+		os.Exit(1)
+	}
+	fmt.Println(ui.GreenText(fmt.Sprintf("Credentials for %s at %s are valid.", creds.Username, creds.BaseURL)))
+}
 
-	if err := config.SaveCredentials(creds); err != nil {
-		fmt.Println(ui.RedText("Error saving credentials: " + err.Error()))
+func runAuthRemove(cmd *cobra.Command, args []string) {
+	creds, err := config.LoadProfile(authProfile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println(ui.YellowText("No stored credentials to remove."))
+			return
+		}
+		fmt.Println(ui.RedText("Error loading credentials: " + err.Error()))
 		os.Exit(1)
 	}
 
-	fmt.Println(ui.GreenText("Success! Credentials saved to ~/.jw/.credentials"))
+	prompt := fmt.Sprintf("Delete stored credentials for %s?", creds.Username)
+	if authRemoveRevoke {
+		prompt = fmt.Sprintf("Revoke the API token on %s and delete stored credentials for %s?", creds.BaseURL, creds.Username)
+	}
+	if !authRemoveYes && !confirm(os.Stdin, os.Stdout, prompt) {
+		fmt.Println("Aborted.")
+		return
+	}
+
+	revoked := false
+	if authRemoveRevoke {
+		if creds.BaseURL == "" {
+			fmt.Println(ui.YellowText("Cannot revoke: saved credentials have no Jenkins URL."))
+		} else if err := jenkins.RevokeToken(creds.BaseURL, creds.EncodedToken(), creds.TokenUUID); err != nil {
+			fmt.Println(ui.YellowText("Could not revoke token: " + err.Error()))
+		} else {
+			revoked = true
+		}
+	}
+
+	if err := config.RemoveProfile(authProfile); err != nil {
+		fmt.Println(ui.RedText("Error removing credentials: " + err.Error()))
+		os.Exit(1)
+	}
+
+	if revoked {
+		fmt.Println(ui.GreenText("API token revoked on Jenkins and local credentials deleted."))
+	} else {
+		fmt.Println(ui.GreenText("Local credentials deleted. The API token is still valid on Jenkins."))
+	}
+}
+
+func runAuthList(cmd *cobra.Command, args []string) {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		fmt.Println(ui.RedText("Error loading credentials: " + err.Error()))
+		os.Exit(1)
+	}
+	if len(profiles) == 0 {
+		fmt.Println(ui.YellowText("No credential profiles stored. Run 'jw auth' to add one."))
+		return
+	}
+
+	for _, name := range profiles {
+		creds, err := config.LoadProfile(name)
+		if err != nil {
+			fmt.Printf("%s\t%s\n", name, ui.RedText("error: "+err.Error()))
+			continue
+		}
+		fmt.Printf("%s\t%s\t%s\n", name, creds.Username, creds.BaseURL)
+	}
 }
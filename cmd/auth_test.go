@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader fails the test if anything tries to read from it.
+type failingReader struct{ t *testing.T }
+
+func (f failingReader) Read(p []byte) (int, error) {
+	f.t.Fatal("unexpected read from stdin")
+	return 0, nil
+}
+
+func TestReadAuthInputs_FromEnv(t *testing.T) {
+	t.Setenv("JENKINS_URL", "https://jenkins.example.com/")
+	t.Setenv("JENKINS_USER", "ci-bot")
+	t.Setenv("JENKINS_PASSWORD", "hunter2")
+
+	var out bytes.Buffer
+	in, err := readAuthInputs(nil, bufio.NewReader(failingReader{t}), &out, func() ([]byte, error) {
+		t.Fatal("unexpected password prompt")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, authInputs{URL: "https://jenkins.example.com", Username: "ci-bot", Password: "hunter2"}, in)
+	assert.NotContains(t, out.String(), "Enter")
+}
+
+func TestReadAuthInputs_ArgumentBeatsEnv(t *testing.T) {
+	t.Setenv("JENKINS_URL", "https://env.example.com")
+	t.Setenv("JENKINS_USER", "ci-bot")
+	t.Setenv("JENKINS_PASSWORD", "hunter2")
+
+	in, err := readAuthInputs([]string{"https://arg.example.com"}, bufio.NewReader(failingReader{t}), &bytes.Buffer{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://arg.example.com", in.URL)
+}
+
+func TestReadAuthInputs_Prompts(t *testing.T) {
+	t.Setenv("JENKINS_URL", "")
+	t.Setenv("JENKINS_USER", "")
+	t.Setenv("JENKINS_PASSWORD", "")
+
+	var out bytes.Buffer
+	in, err := readAuthInputs(nil, bufio.NewReader(strings.NewReader("https://jenkins.example.com\nalice\n")), &out, func() ([]byte, error) {
+		return []byte("s3cret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, authInputs{URL: "https://jenkins.example.com", Username: "alice", Password: "s3cret"}, in)
+	assert.Contains(t, out.String(), "Enter Jenkins URL")
+	assert.Contains(t, out.String(), "Enter Jenkins Username")
+	assert.Contains(t, out.String(), "Enter Jenkins Password")
+}
+
+func TestReadAuthInputs_EnvURLPromptsForRest(t *testing.T) {
+	t.Setenv("JENKINS_URL", "https://jenkins.example.com")
+	t.Setenv("JENKINS_USER", "")
+	t.Setenv("JENKINS_PASSWORD", "")
+
+	var out bytes.Buffer
+	in, err := readAuthInputs(nil, bufio.NewReader(strings.NewReader("alice\n")), &out, func() ([]byte, error) {
+		return []byte("s3cret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", in.Username)
+	assert.NotContains(t, out.String(), "Enter Jenkins URL")
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/pidfile"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanTTL         time.Duration
+	cleanLogMaxBytes int64
+	cleanDryRun      bool
+	cleanRepair      bool
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove stale jobs, a stale PID file and an oversized log",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		prefix := ""
+		if cleanDryRun {
+			prefix = "[dry-run] would "
+		}
+		cleaned := 0
+
+		stale, err := pidfile.IsStale()
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error checking PID file: %v", err)))
+		} else if stale {
+			if !cleanDryRun {
+				if err := pidfile.Remove(); err != nil {
+					fmt.Println(ui.RedText(fmt.Sprintf("Error removing PID file: %v", err)))
+				}
+			}
+			fmt.Println(prefix + "remove stale PID file")
+			cleaned++
+		}
+
+		if cleanRepair {
+			repaired, err := repairConfigChecksum(cleanDryRun)
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error repairing config checksum: %v", err)))
+				os.Exit(1)
+			}
+			if repaired {
+				fmt.Println(prefix + "regenerate the config checksum")
+				cleaned++
+			}
+		}
+
+		store := &config.DiskStore{IgnoreChecksum: cleanRepair}
+		removed, err := cleanStaleJobs(store, cleanTTL, time.Now(), cleanDryRun)
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error cleaning jobs: %v", err)))
+			os.Exit(1)
+		}
+		for _, jobURL := range removed {
+			fmt.Println(prefix + "remove stale job: " + jobURL)
+		}
+		cleaned += len(removed)
+
+		logPath, err := logging.GetLogFilePath()
+		if err == nil {
+			size, truncated, err := truncateLogIfLarge(logPath, cleanLogMaxBytes, cleanDryRun)
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error truncating log file: %v", err)))
+			} else if truncated {
+				fmt.Printf("%struncate log file (%d bytes)\n", prefix, size)
+				cleaned++
+			}
+		}
+
+		if cleaned == 0 {
+			fmt.Println(ui.GreenText("Nothing to clean."))
+			return
+		}
+		if !cleanDryRun {
+			fmt.Println(ui.GreenText(fmt.Sprintf("Cleaned %d item(s).", cleaned)))
+			if len(removed) > 0 && signalDaemonIfRunning() {
+				fmt.Println("Daemon signaled to stop monitoring the removed jobs.")
+			}
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(cleanCmd)
+	cleanCmd.Flags().DurationVar(&cleanTTL, "ttl", 7*24*time.Hour, "Remove jobs monitored for longer than this, except recurring and paused ones; jobs added with --ttl-hours also expire after their own TTL")
+	cleanCmd.Flags().Int64Var(&cleanLogMaxBytes, "log-max-bytes", 10*1024*1024, "Truncate the log file when larger than this")
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Print what would be removed without removing anything")
+	cleanCmd.Flags().BoolVar(&cleanRepair, "repair", false, "Accept the config as it is and regenerate its checksum if it does not match")
+}
+
+// cleanStaleJobs removes jobs whose StartTime is older than ttl, or that have
+// outlived their own TTL, and returns their URLs. Recurring and paused jobs
+// are meant to stay around, so only their own TTL applies to them. With
+// dryRun the config is left untouched.
+func cleanStaleJobs(store config.ConfigStore, ttl time.Duration, now time.Time, dryRun bool) ([]string, error) {
+	var removed []string
+	collect := func(cfg *config.Config) {
+		removed = nil
+		for jobURL, job := range cfg.Jobs {
+			stale := !job.Recurring && !job.Paused && now.Sub(job.StartTime) > ttl
+			if stale || job.Expired(now) {
+				removed = append(removed, jobURL)
+			}
+		}
+	}
+
+	if dryRun {
+		cfg, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		collect(cfg)
+		return removed, nil
+	}
+
+	err := store.Update(func(cfg *config.Config) error {
+		collect(cfg)
+		for _, jobURL := range removed {
+			cfg.RemoveJob(jobURL)
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// repairConfigChecksum rewrites the config file with a fresh checksum if the
+// stored one does not match, and reports whether it did (or, with dryRun,
+// would).
+func repairConfigChecksum(dryRun bool) (bool, error) {
+	_, err := config.NewDiskStore().Load()
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, config.ErrChecksumMismatch) {
+		return false, err
+	}
+	if dryRun {
+		return true, nil
+	}
+	store := &config.DiskStore{IgnoreChecksum: true}
+	cfg, err := store.Load()
+	if err != nil {
+		return true, err
+	}
+	return true, store.Save(cfg)
+}
+
+// truncateLogIfLarge empties the file at path when it exceeds maxBytes and
+// reports its size beforehand and whether it was (or would be) truncated.
+func truncateLogIfLarge(path string, maxBytes int64, dryRun bool) (int64, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	if maxBytes <= 0 || info.Size() <= maxBytes {
+		return info.Size(), false, nil
+	}
+	if dryRun {
+		return info.Size(), true, nil
+	}
+	return info.Size(), true, os.Truncate(path, 0)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanStaleJobs(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	store := newMemStore(
+		config.Job{URL: "https://jenkins/job/old/1", StartTime: now.Add(-8 * 24 * time.Hour)},
+		config.Job{URL: "https://jenkins/job/older/2", StartTime: now.Add(-30 * 24 * time.Hour)},
+		config.Job{URL: "https://jenkins/job/new/3", StartTime: now.Add(-time.Hour)},
+	)
+
+	removed, err := cleanStaleJobs(store, 7*24*time.Hour, now, true)
+	require.NoError(t, err)
+	sort.Strings(removed)
+	assert.Equal(t, []string{"https://jenkins/job/old/1", "https://jenkins/job/older/2"}, removed)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 3, "dry run must not modify config")
+
+	removed, err = cleanStaleJobs(store, 7*24*time.Hour, now, false)
+	require.NoError(t, err)
+	assert.Len(t, removed, 2)
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 1)
+	assert.True(t, cfg.HasJob("https://jenkins/job/new/3"))
+}
+
+func TestCleanStaleJobs_PerJobTTL(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	store := newMemStore(
+		config.Job{URL: "https://jenkins/job/expired/1", StartTime: now.Add(-5 * time.Hour), MaxMonitorHours: 4},
+		config.Job{URL: "https://jenkins/job/within/2", StartTime: now.Add(-3 * time.Hour), MaxMonitorHours: 4},
+		config.Job{URL: "https://jenkins/job/forever/3", StartTime: now.Add(-5 * time.Hour)},
+	)
+
+	removed, err := cleanStaleJobs(store, 7*24*time.Hour, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://jenkins/job/expired/1"}, removed)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 2)
+}
+
+func TestCleanStaleJobs_KeepsRecurringAndPaused(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	store := newMemStore(
+		config.Job{URL: "https://jenkins/job/nightly", StartTime: now.Add(-30 * 24 * time.Hour), Recurring: true},
+		config.Job{URL: "https://jenkins/job/paused/1", StartTime: now.Add(-30 * 24 * time.Hour), Paused: true},
+		config.Job{URL: "https://jenkins/job/expired", StartTime: now.Add(-5 * time.Hour), Recurring: true, MaxMonitorHours: 4},
+	)
+
+	removed, err := cleanStaleJobs(store, 7*24*time.Hour, now, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://jenkins/job/expired"}, removed)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob("https://jenkins/job/nightly"))
+	assert.True(t, cfg.HasJob("https://jenkins/job/paused/1"))
+}
+
+func TestTruncateLogIfLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jw.log")
+	require.NoError(t, os.WriteFile(path, make([]byte, 100), 0o644))
+
+	size, truncated, err := truncateLogIfLarge(path, 200, false)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.EqualValues(t, 100, size)
+
+	_, truncated, err = truncateLogIfLarge(path, 50, true)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	info, _ := os.Stat(path)
+	assert.EqualValues(t, 100, info.Size(), "dry run must not truncate")
+
+	_, truncated, err = truncateLogIfLarge(path, 50, false)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	info, _ = os.Stat(path)
+	assert.Zero(t, info.Size())
+
+	_, truncated, err = truncateLogIfLarge(filepath.Join(t.TempDir(), "missing.log"), 50, false)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+}
+
+func TestRepairConfigChecksum(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.NewDiskStore().Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins/job/a/1")
+		return nil
+	}))
+
+	repaired, err := repairConfigChecksum(false)
+	require.NoError(t, err)
+	assert.False(t, repaired, "a matching checksum needs no repair")
+
+	path, err := config.GetConfigPath()
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), "job/a/1", "job/b/2", 2)), 0o644))
+	_, err = config.NewDiskStore().Load()
+	require.ErrorIs(t, err, config.ErrChecksumMismatch)
+
+	repaired, err = repairConfigChecksum(true)
+	require.NoError(t, err)
+	assert.True(t, repaired)
+	_, err = config.NewDiskStore().Load()
+	require.ErrorIs(t, err, config.ErrChecksumMismatch, "dry-run leaves the file alone")
+
+	repaired, err = repairConfigChecksum(false)
+	require.NoError(t, err)
+	assert.True(t, repaired)
+	cfg, err := config.NewDiskStore().Load()
+	require.NoError(t, err)
+	assert.Contains(t, cfg.Jobs, "https://jenkins/job/b/2")
+}
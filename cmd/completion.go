@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Generate shell completion scripts",
+	Long: `Generate shell completion scripts for jw.
+
+  bash:       source <(jw completion bash)
+  zsh:        jw completion zsh > "${fpath[1]}/_jw"
+  fish:       jw completion fish > ~/.config/fish/completions/jw.fish
+  powershell: jw completion powershell | Out-String | Invoke-Expression
+
+URL prefixes offered when completing 'jw add' and 'jw auth' are read from
+url_hints in the config directory ('jw config path' shows where), one per
+line, e.g. https://jenkins.example.com/job/. 'jw add' also completes a job
+name typed after a hint, and the URLs of jobs already monitored.`,
+	// Override RootCmd's upgrade check so nothing else is written into the script.
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var completionBashCmd = &cobra.Command{
+	Use:   "bash",
+	Short: "Generate the bash completion script",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return RootCmd.GenBashCompletionV2(cmd.OutOrStdout(), true)
+	},
+}
+
+var completionZshCmd = &cobra.Command{
+	Use:   "zsh",
+	Short: "Generate the zsh completion script",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return RootCmd.GenZshCompletion(cmd.OutOrStdout())
+	},
+}
+
+var completionFishCmd = &cobra.Command{
+	Use:   "fish",
+	Short: "Generate the fish completion script",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return RootCmd.GenFishCompletion(cmd.OutOrStdout(), true)
+	},
+}
+
+var completionPowerShellCmd = &cobra.Command{
+	Use:   "powershell",
+	Short: "Generate the PowerShell completion script",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return RootCmd.GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	completionCmd.AddCommand(completionBashCmd, completionZshCmd, completionFishCmd, completionPowerShellCmd)
+	RootCmd.AddCommand(completionCmd)
+
+	removeCmd.ValidArgsFunction = completeMonitoredJobs
+	addCmd.ValidArgsFunction = completeAddURL
+	authCmd.ValidArgsFunction = completeURLHints
+}
+
+// completeMonitoredJobs offers the URLs of currently monitored jobs.
+func completeMonitoredJobs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := config.NewDiskStore().Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var urls []string
+	for jobURL := range cfg.Jobs {
+		if strings.HasPrefix(jobURL, toComplete) {
+			urls = append(urls, jobURL)
+		}
+	}
+	sort.Strings(urls)
+	return urls, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAddURL offers URLs for jw add from the hints file and the
+// monitored jobs.
+func completeAddURL(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	hints, err := config.LoadCompletionHints()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	urls, err := addURLCompletions(config.NewDiskStore(), hints, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return urls, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// addURLCompletions returns the candidates for a partially typed jw add URL:
+// hints and monitored job URLs starting with toComplete and, when toComplete
+// is not itself a URL, each hint followed by it, so "my-job" completes to
+// "https://jenkins.example.com/job/my-job".
+func addURLCompletions(store config.ConfigStore, hints []string, toComplete string) ([]string, error) {
+	cfg, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var urls []string
+	add := func(url string) {
+		if !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	for _, hint := range hints {
+		if strings.HasPrefix(hint, toComplete) {
+			add(hint)
+		} else if toComplete != "" && !strings.Contains(toComplete, "://") {
+			add(hint + toComplete)
+		}
+	}
+	jobs := make([]string, 0, len(cfg.Jobs))
+	for jobURL := range cfg.Jobs {
+		if strings.HasPrefix(jobURL, toComplete) {
+			jobs = append(jobs, jobURL)
+		}
+	}
+	sort.Strings(jobs)
+	for _, jobURL := range jobs {
+		add(jobURL)
+	}
+	return urls, nil
+}
+
+// completeURLHints offers Jenkins URL prefixes from the completion hints file.
+func completeURLHints(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	hints, err := config.LoadCompletionHints()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var matches []string
+	for _, hint := range hints {
+		if strings.HasPrefix(hint, toComplete) {
+			matches = append(matches, hint)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
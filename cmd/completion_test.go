@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionScripts_NonEmpty(t *testing.T) {
+	for _, c := range []*cobra.Command{completionBashCmd, completionZshCmd, completionFishCmd, completionPowerShellCmd} {
+		t.Run(c.Name(), func(t *testing.T) {
+			var buf bytes.Buffer
+			c.SetOut(&buf)
+			t.Cleanup(func() { c.SetOut(nil) })
+
+			require.NoError(t, c.RunE(c, nil))
+			assert.NotEmpty(t, buf.String())
+			assert.Contains(t, buf.String(), "jw")
+		})
+	}
+}
+
+func TestCompleteMonitoredJobs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins/job/b/2")
+		cfg.AddJob("https://jenkins/job/a/1")
+		cfg.AddJob("https://other/job/c/3")
+		return nil
+	}))
+
+	urls, directive := completeMonitoredJobs(removeCmd, nil, "https://jenkins/")
+	assert.Equal(t, []string{"https://jenkins/job/a/1", "https://jenkins/job/b/2"}, urls)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func TestCompleteURLHints(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".jw"), 0o755))
+	hints := "https://jenkins.example.com/job/\n# comment\nhttps://ci.example.org/\n"
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".jw", "completion_hints"), []byte(hints), 0o644))
+
+	matches, _ := completeURLHints(addCmd, nil, "https://jen")
+	assert.Equal(t, []string{"https://jenkins.example.com/job/"}, matches)
+
+	matches, _ = completeURLHints(addCmd, nil, "")
+	assert.Len(t, matches, 2)
+}
+
+func TestAddURLCompletions(t *testing.T) {
+	store := newMemStore(
+		config.Job{URL: "https://jenkins.example.com/job/api/12/"},
+		config.Job{URL: "https://other.example.com/job/web/3/"},
+	)
+	hints := []string{"https://jenkins.example.com/job/", "https://ci.example.org/job/"}
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"https://jenkins.example.com/job/", "https://ci.example.org/job/", "https://jenkins.example.com/job/api/12/", "https://other.example.com/job/web/3/"}},
+		{"https://jenkins", []string{"https://jenkins.example.com/job/", "https://jenkins.example.com/job/api/12/"}},
+		{"https://jenkins.example.com/job/api", []string{"https://jenkins.example.com/job/api/12/"}},
+		{"deploy", []string{"https://jenkins.example.com/job/deploy", "https://ci.example.org/job/deploy"}},
+		{"https://nowhere", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.toComplete, func(t *testing.T) {
+			got, err := addURLCompletions(store, hints, tt.toComplete)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteAddURL_HintsFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".jw")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "url_hints"), []byte("https://jenkins.example.com/job/\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "completion_hints"), []byte("https://legacy.example.com/\n"), 0o644))
+	require.NoError(t, config.NewDiskStore().Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins.example.com/job/nightly/5/")
+		return nil
+	}))
+
+	urls, directive := completeAddURL(addCmd, nil, "nightly")
+	assert.Equal(t, []string{"https://jenkins.example.com/job/nightly"}, urls, "url_hints takes precedence over completion_hints")
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace, directive)
+
+	urls, _ = completeAddURL(addCmd, nil, "https://jenkins.example.com/job/n")
+	assert.Equal(t, []string{"https://jenkins.example.com/job/nightly/5/"}, urls)
+}
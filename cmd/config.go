@@ -0,0 +1,519 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+	"jenkins-monitor/pkg/upgrade"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change jw configuration",
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file path",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runConfigPath(os.Stdout); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print a config value",
+	Long:              "Print a config value. Keys: " + strings.Join(configKeyNames(), ", "),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		states, err := upgrade.NewFileStateStore()
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		value, err := getConfigValue(config.NewDiskStore(), states, args[0])
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "Change a config value",
+	Long:              "Change a config value. Keys: " + strings.Join(configKeyNames(), ", "),
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		states, err := upgrade.NewFileStateStore()
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		if err := setConfigValue(config.NewDiskStore(), states, args[0], args[1]); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		fmt.Println(ui.GreenText(fmt.Sprintf("Set %s.", args[0])))
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for corruption",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := config.GetConfigPath()
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		if err := validateConfigFile(path); err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Config %s is invalid: %v", path, err)))
+			os.Exit(1)
+		}
+		fmt.Println(ui.GreenText("Config OK"))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPathCmd, configGetCmd, configSetCmd, configValidateCmd)
+}
+
+// configKey reads and, if set is non-nil, writes one scalar config field.
+// Keys for the upgrade check's state, which lives in its own file, use
+// getState and setState instead.
+type configKey struct {
+	get      func(*config.Config) string
+	set      func(*config.Config, string) error
+	getState func(upgrade.State) string
+	setState func(*upgrade.State, string) error
+}
+
+// configKeys maps the dot-path of each supported config field, as spelled in
+// the JSON file, to its accessors.
+var configKeys = map[string]configKey{
+	"upgrade_check.last_checked": {
+		getState: func(s upgrade.State) string { return s.LastChecked.Format(time.RFC3339) },
+		setState: func(s *upgrade.State, v string) error {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fmt.Errorf("want an RFC 3339 time such as 2006-01-02T15:04:05Z: %w", err)
+			}
+			s.LastChecked = t
+			return nil
+		},
+	},
+	"upgrade_check.latest_version": {
+		getState: func(s upgrade.State) string { return s.LatestVersion },
+		setState: func(s *upgrade.State, v string) error {
+			s.LatestVersion = v
+			return nil
+		},
+	},
+	"notifications.slack_webhook_url": {
+		get: func(c *config.Config) string { return c.Notifications.SlackWebhookURL },
+		set: func(c *config.Config, v string) error {
+			c.Notifications.SlackWebhookURL = v
+			return nil
+		},
+	},
+	"notifications.discord_webhook_url": {
+		get: func(c *config.Config) string { return c.Notifications.DiscordWebhookURL },
+		set: func(c *config.Config, v string) error {
+			if v != "" && !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "http://") {
+				return fmt.Errorf("must start with http:// or https://")
+			}
+			c.Notifications.DiscordWebhookURL = v
+			return nil
+		},
+	},
+	"notifications.telegram_bot_token": {
+		get: func(c *config.Config) string { return c.Notifications.TelegramBotToken },
+		set: func(c *config.Config, v string) error {
+			c.Notifications.TelegramBotToken = v
+			return nil
+		},
+	},
+	"notifications.telegram_chat_id": {
+		get: func(c *config.Config) string { return c.Notifications.TelegramChatID },
+		set: func(c *config.Config, v string) error {
+			c.Notifications.TelegramChatID = v
+			return nil
+		},
+	},
+	"webhook.url": {
+		get: func(c *config.Config) string { return c.Webhook.URL },
+		set: func(c *config.Config, v string) error {
+			if v != "" && !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "http://") {
+				return fmt.Errorf("must start with http:// or https://")
+			}
+			c.Webhook.URL = v
+			return nil
+		},
+	},
+	"max_completion_history": {
+		get: func(c *config.Config) string { return strconv.Itoa(c.MaxCompletionHistory) },
+		set: func(c *config.Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("want a non-negative integer, got %q", v)
+			}
+			c.MaxCompletionHistory = n
+			return nil
+		},
+	},
+	"settings.backup_count": {
+		get: func(c *config.Config) string { return strconv.Itoa(c.Settings.BackupCount) },
+		set: func(c *config.Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("want an integer, got %q", v)
+			}
+			c.Settings.BackupCount = n
+			return nil
+		},
+	},
+	"quiet_hours.start": {
+		get: func(c *config.Config) string { return c.QuietHours.Start },
+		set: func(c *config.Config, v string) error {
+			if v != "" {
+				if _, err := config.ParseClock(v); err != nil {
+					return err
+				}
+			}
+			c.QuietHours.Start = v
+			return nil
+		},
+	},
+	"quiet_hours.end": {
+		get: func(c *config.Config) string { return c.QuietHours.End },
+		set: func(c *config.Config, v string) error {
+			if v != "" {
+				if _, err := config.ParseClock(v); err != nil {
+					return err
+				}
+			}
+			c.QuietHours.End = v
+			return nil
+		},
+	},
+	"quiet_hours.timezone": {
+		get: func(c *config.Config) string { return c.QuietHours.Timezone },
+		set: func(c *config.Config, v string) error {
+			if _, err := time.LoadLocation(v); err != nil {
+				return err
+			}
+			c.QuietHours.Timezone = v
+			return nil
+		},
+	},
+	"notify_title_template": {
+		get: func(c *config.Config) string { return c.NotifyTitleTemplate },
+		set: func(c *config.Config, v string) error {
+			c.NotifyTitleTemplate = v
+			_, _, err := c.NotificationTemplates()
+			return err
+		},
+	},
+	"notify_body_template": {
+		get: func(c *config.Config) string { return c.NotifyBodyTemplate },
+		set: func(c *config.Config, v string) error {
+			c.NotifyBodyTemplate = v
+			_, _, err := c.NotificationTemplates()
+			return err
+		},
+	},
+	"jobs": {
+		get: func(c *config.Config) string { return strconv.Itoa(len(c.Jobs)) },
+	},
+}
+
+// webhookHeaderPrefix addresses one webhook header, e.g.
+// webhook.headers.Authorization. Setting a header to "" removes it.
+const webhookHeaderPrefix = "webhook.headers."
+
+func webhookHeaderKey(name string) configKey {
+	return configKey{
+		get: func(c *config.Config) string { return c.Webhook.Headers[name] },
+		set: func(c *config.Config, v string) error {
+			if v == "" {
+				delete(c.Webhook.Headers, name)
+				return nil
+			}
+			if c.Webhook.Headers == nil {
+				c.Webhook.Headers = make(map[string]string)
+			}
+			c.Webhook.Headers[name] = v
+			return nil
+		},
+	}
+}
+
+func configKeyNames() []string {
+	names := make([]string, 0, len(configKeys))
+	for name := range configKeys {
+		names = append(names, name)
+	}
+	names = append(names, webhookHeaderPrefix+"<name>")
+	sort.Strings(names)
+	return names
+}
+
+func lookupConfigKey(key string) (configKey, error) {
+	if name, ok := strings.CutPrefix(key, webhookHeaderPrefix); ok && name != "" {
+		return webhookHeaderKey(name), nil
+	}
+	k, ok := configKeys[key]
+	if !ok {
+		return configKey{}, fmt.Errorf("unknown key %q (valid keys: %s)", key, strings.Join(configKeyNames(), ", "))
+	}
+	return k, nil
+}
+
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return configKeyNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func runConfigPath(w io.Writer) error {
+	path, err := config.GetConfigPath()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, path)
+	return nil
+}
+
+func getConfigValue(store config.ConfigStore, states upgrade.StateStore, key string) (string, error) {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	if k.getState != nil {
+		state, err := states.Load()
+		if err != nil {
+			return "", fmt.Errorf("loading upgrade check state: %w", err)
+		}
+		return k.getState(state), nil
+	}
+	cfg, err := store.Load()
+	if err != nil {
+		return "", fmt.Errorf("loading config: %w", err)
+	}
+	return k.get(cfg), nil
+}
+
+func setConfigValue(store config.ConfigStore, states upgrade.StateStore, key, value string) error {
+	k, err := lookupConfigKey(key)
+	if err != nil {
+		return err
+	}
+	if k.setState != nil {
+		if err := k.setState(&upgrade.State{}, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return states.Update(func(s *upgrade.State) { _ = k.setState(s, value) })
+	}
+	if k.set == nil {
+		return fmt.Errorf("%s is read-only", key)
+	}
+	return store.Update(func(cfg *config.Config) error {
+		if err := k.set(cfg, value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return nil
+	})
+}
+
+// validateConfigFile checks that the config file at path decodes strictly,
+// survives a marshal round trip unchanged, that each job is stored under its
+// own URL and that it passes the checks made when it is loaded. Fields jw
+// would add with their zero value do not count as changes. A missing file is
+// valid.
+func validateConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data = withoutLegacyUpgradeCheck(data)
+
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	remarshaled, err := json.Marshal(&cfg)
+	if err != nil {
+		return fmt.Errorf("re-encoding config: %w", err)
+	}
+	diff, err := roundTripDiff(data, remarshaled)
+	if err != nil {
+		return err
+	}
+	diff = strings.TrimSuffix(diff, "\n")
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&config.Config{}); err != nil {
+		if diff != "" {
+			return fmt.Errorf("%w\n%s", err, diff)
+		}
+		return err
+	}
+	if diff != "" {
+		return fmt.Errorf("config changes when re-encoded:\n%s", diff)
+	}
+
+	for key, job := range cfg.Jobs {
+		if job.URL != key {
+			return fmt.Errorf("job %q has mismatched url %q", key, job.URL)
+		}
+	}
+	return cfg.Check()
+}
+
+// withoutLegacyUpgradeCheck drops the "upgrade_check" object older versions
+// kept in the config. The upgrade check carries it over to its own state file
+// and jw leaves it out the next time it writes the config, so it is not an
+// unknown field worth reporting.
+func withoutLegacyUpgradeCheck(data []byte) []byte {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return data
+	}
+	if _, ok := fields["upgrade_check"]; !ok {
+		return data
+	}
+	delete(fields, "upgrade_check")
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return stripped
+}
+
+// roundTripDiff returns a line diff between the original and re-encoded
+// config, both indented with sorted keys, or "" if they match.
+func roundTripDiff(original, remarshaled []byte) (string, error) {
+	var before, after any
+	if err := json.Unmarshal(original, &before); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(remarshaled, &after); err != nil {
+		return "", fmt.Errorf("re-encoded config does not parse: %w", err)
+	}
+	after = dropAddedZeros(before, after)
+
+	a, err := json.MarshalIndent(before, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	b, err := json.MarshalIndent(after, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(a, b) {
+		return "", nil
+	}
+	return lineDiff(strings.Split(string(a), "\n"), strings.Split(string(b), "\n")), nil
+}
+
+// dropAddedZeros removes object keys from after that are missing from before
+// and hold a zero value, as those are fields the file simply left out.
+func dropAddedZeros(before, after any) any {
+	b, ok1 := before.(map[string]any)
+	a, ok2 := after.(map[string]any)
+	if !ok1 || !ok2 {
+		return after
+	}
+	for k, v := range a {
+		if old, ok := b[k]; ok {
+			a[k] = dropAddedZeros(old, v)
+		} else if isZeroJSON(v) {
+			delete(a, k)
+		}
+	}
+	return a
+}
+
+func isZeroJSON(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == "" || v == "0001-01-01T00:00:00Z"
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		for _, field := range v {
+			if !isZeroJSON(field) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// lineDiff renders the lines removed from a with "-" and added in b with "+",
+// using their longest common subsequence.
+func lineDiff(a, b []string) string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&sb, "+ %s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(&sb, "- %s\n", a[i])
+			i++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/upgrade"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.Mkdir(filepath.Join(home, ".jw"), 0o755))
+
+	var out bytes.Buffer
+	require.NoError(t, runConfigPath(&out))
+	assert.Equal(t, filepath.Join(home, ".jw", "monitored_jobs.json")+"\n", out.String())
+}
+
+func TestConfigSetGetRoundTrip(t *testing.T) {
+	store := config.NewMemoryStore()
+	states := &upgrade.MemoryStateStore{}
+
+	tests := []struct {
+		key, value string
+	}{
+		{"upgrade_check.last_checked", "2026-03-04T05:06:07Z"},
+		{"upgrade_check.latest_version", "v1.2.3"},
+		{"notifications.slack_webhook_url", "https://hooks.slack.com/services/x"},
+		{"max_completion_history", "20"},
+		{"notifications.discord_webhook_url", "https://discord.com/api/webhooks/1/x"},
+		{"notifications.telegram_bot_token", "42:ABC"},
+		{"notifications.telegram_chat_id", "-1001234567890"},
+		{"quiet_hours.start", "22:00"},
+		{"quiet_hours.end", "07:00"},
+		{"quiet_hours.timezone", "Europe/Berlin"},
+		{"notify_title_template", "[{{.Result}}] {{.JobName}}"},
+		{"webhook.url", "https://hooks.example.com/jw"},
+		{"webhook.headers.Authorization", "Bearer s3cret"},
+	}
+	for _, tt := range tests {
+		require.NoError(t, setConfigValue(store, states, tt.key, tt.value), tt.key)
+		got, err := getConfigValue(store, states, tt.key)
+		require.NoError(t, err, tt.key)
+		assert.Equal(t, tt.value, got, tt.key)
+	}
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.MaxCompletionHistory)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer s3cret"}, cfg.Webhook.Headers)
+
+	require.NoError(t, setConfigValue(store, states, "webhook.headers.Authorization", ""))
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Webhook.Headers)
+	state, err := states.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", state.LatestVersion)
+}
+
+func TestConfigSetErrors(t *testing.T) {
+	store := config.NewMemoryStore()
+	states := &upgrade.MemoryStateStore{}
+
+	assert.ErrorContains(t, setConfigValue(store, states, "nope", "1"), "unknown key")
+	assert.ErrorContains(t, setConfigValue(store, states, "jobs", "1"), "read-only")
+	assert.ErrorContains(t, setConfigValue(store, states, "max_completion_history", "-1"), "invalid value")
+	assert.ErrorContains(t, setConfigValue(store, states, "upgrade_check.last_checked", "yesterday"), "RFC 3339")
+	assert.ErrorContains(t, setConfigValue(store, states, "quiet_hours.start", "10pm"), "HH:MM")
+	assert.Error(t, setConfigValue(store, states, "quiet_hours.timezone", "Mars/Olympus"))
+	assert.ErrorContains(t, setConfigValue(store, states, "notify_body_template", "{{.Result"), "notify_body_template")
+
+	_, err := getConfigValue(store, states, "nope")
+	assert.ErrorContains(t, err, "unknown key")
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "monitored_jobs.json")
+
+	assert.NoError(t, validateConfigFile(path), "a missing file is valid")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{"https://j/job/a/1":{"url":"https://j/job/a/1","start_time":"2026-01-01T00:00:00Z"}}}`), 0o644))
+	assert.NoError(t, validateConfigFile(path))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{"https://j/job/a/1":{"url":"https://j/job/b/2","start_time":"2026-01-01T00:00:00Z"}}}`), 0o644))
+	assert.ErrorContains(t, validateConfigFile(path), "mismatched url")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs": {`), 0o644))
+	assert.Error(t, validateConfigFile(path))
+}
+
+func TestValidateConfigFile_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitored_jobs.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"notify_on_succes":true,"jobs":{}}`), 0o644))
+
+	err := validateConfigFile(path)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `unknown field "notify_on_succes"`)
+	assert.ErrorContains(t, err, `-   "notify_on_succes": true`)
+}
+
+func TestValidateConfigFile_LegacyUpgradeCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitored_jobs.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{},"upgrade_check":{"last_checked":"2026-01-01T00:00:00Z","latest_version":"v1.2.0"}}`), 0o644))
+	assert.NoError(t, validateConfigFile(path))
+}
+
+func TestValidateConfigFile_SavedConfigRoundTrips(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, config.NewDiskStore().Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://j/job/a/1")
+		cfg.SetJobParameters("https://j/job/a/1", map[string]string{"BRANCH": "main"})
+		cfg.FinishJob("https://j/job/a/1", "SUCCESS")
+		return nil
+	}))
+	path, err := config.GetConfigPath()
+	require.NoError(t, err)
+	assert.NoError(t, validateConfigFile(path))
+}
+
+func TestValidateConfigFile_LoadChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "monitored_jobs.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{},"checksum":"0000"}`), 0o644))
+	assert.ErrorIs(t, validateConfigFile(path), config.ErrChecksumMismatch)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{},"notify_body_template":"{{.Result"}`), 0o644))
+	assert.ErrorContains(t, validateConfigFile(path), "notify_body_template")
+}
+
+func TestLineDiff(t *testing.T) {
+	a := []string{"{", `  "a": 1,`, `  "b": 2`, "}"}
+	b := []string{"{", `  "b": 2`, "}"}
+	assert.Equal(t, "-   \"a\": 1,\n", lineDiff(a, b))
+	assert.Equal(t, "+   \"a\": 1,\n", lineDiff(b, a))
+}
@@ -1,19 +1,33 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/daemon"
+	"jenkins-monitor/pkg/eventlog"
+	"jenkins-monitor/pkg/jenkins"
 	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/metrics"
 	"jenkins-monitor/pkg/monitor"
 	"jenkins-monitor/pkg/notify"
 	"jenkins-monitor/pkg/pidfile"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 )
 
@@ -24,155 +38,663 @@ var startDaemonCmd = &cobra.Command{
 	Run:    startDaemon,
 }
 
+var daemonLogFormat string
+
 func init() {
 	RootCmd.AddCommand(startDaemonCmd)
+	startDaemonCmd.Flags().StringVar(&daemonLogFormat, "log-format", logging.FormatText, "Daemon log format: text or json")
 }
 
-func handleJobEvent(event monitor.JobEvent, logger *log.Logger, store config.ConfigStore, activeJobs map[string]chan struct{}, notifier notify.Notifier) {
+func handleJobEvent(event monitor.JobEvent, logger *slog.Logger, store config.ConfigStore, activeJobs map[string]activeJob, notifier notify.Notifier, m *metrics.Metrics) {
+	if event.Error != nil {
+		m.PollError()
+	}
+
+	// send delivers a notification and counts it under kind. During quiet
+	// hours it only logs the notification and returns errNotificationSuppressed.
+	send := func(kind, title, message string) error {
+		if inQuietHours(store, logger) {
+			logger.Info(fmt.Sprintf("Quiet hours, not sending notification: %s", title), "job", event.JobURL)
+			return errNotificationSuppressed
+		}
+		err := notifier.Send(notify.Notification{Title: title, Message: message, URL: event.JobURL, JobName: event.JobName, Result: event.Result})
+		if err == nil {
+			m.NotificationSent(kind)
+		}
+		return err
+	}
+
 	switch event.Kind {
 	case monitor.EventStatusChecked, monitor.EventError:
-		updateJobCheckStatus(event.JobURL, event.Failed, logger, store)
+		job, failures := updateJobCheckStatus(event.JobURL, event.Failed, event.BuildStarted, event.BuildNumber, logger, store)
+		if failures > 0 {
+			reason := "The running build reports FAILURE."
+			if event.Error != nil {
+				reason = fmt.Sprintf("Last error: %v", event.Error)
+			}
+			if err := send(
+				"check_failed",
+				"Jenkins Check Failing",
+				fmt.Sprintf("Job: %s\n%d checks in a row failed. %s", event.JobName, failures, reason),
+			); err != nil && !errors.Is(err, errNotificationSuppressed) {
+				logger.Error(fmt.Sprintf("Failed to send notification: %v", err), "job", event.JobURL)
+			}
+		}
+		if event.Kind == monitor.EventStatusChecked && job.Expired(time.Now()) {
+			expiredEvent := monitor.JobEvent{JobURL: event.JobURL, JobName: event.JobName, Kind: monitor.EventTTLExpired, Duration: job.TTL()}
+			handleJobEvent(expiredEvent, logger, store, activeJobs, notifier, m)
+		}
+
+	case monitor.EventParameters:
+		recordJobParameters(event.JobURL, event.Parameters, logger, store)
+
+	case monitor.EventTTLExpired:
+		logger.Info(fmt.Sprintf("Monitoring of %s expired after %s", event.JobURL, event.Duration), "job", event.JobURL)
+		_ = send(
+			"ttl_expired",
+			"Monitoring expired",
+			fmt.Sprintf("Job: %s\nStill not finished after %s. Removing from monitor.", event.JobName, event.Duration),
+		)
+		removeJob(event.JobURL, logger, store, activeJobs)
 
 	case monitor.EventFinished:
-		notificationTitle := "Jenkins Job Completed"
-		if event.Result == "FAILURE" {
-			notificationTitle = "Jenkins Job Failed"
+		m.BuildCompleted(event.Result)
+		previous, job := finishJob(event, logger, store, activeJobs)
+		kind, notificationTitle := finishedNotification(previous, event.Result)
+		buildNumber := event.BuildNumber
+		if buildNumber == 0 {
+			buildNumber = job.BuildNumber
 		}
-		if err := notifier.Send(notificationTitle, fmt.Sprintf("Job: %s\nStatus: %s", event.JobName, event.Result), event.JobURL); err != nil {
-			logger.Printf("Failed to send notification: %v", err)
+		if job.Recurring {
+			notificationTitle += " (recurring)"
+		}
+		title, message := renderFinishedNotification(store, logger, config.NotificationData{
+			Title:        notificationTitle,
+			JobName:      event.JobName,
+			JobURL:       event.JobURL,
+			Result:       event.Result,
+			Duration:     formatBuildDuration(finishedBuildDuration(job, event.Duration, time.Now())),
+			Parameters:   config.FormatParameters(job.Parameters),
+			TriggerCause: job.TriggerCause,
+			Tests:        formatTestSummary(event.Tests),
+			Changes:      formatChanges(event.Changes),
+			Note:         config.TruncateNote(job.Notes),
+			BuildNumber:  buildNumber,
+			FailedStages: strings.Join(event.FailedStages, ", "),
+		})
+		if err := send(kind, title, message); errors.Is(err, errNotificationSuppressed) {
+			// Already logged by send.
+		} else if err != nil {
+			logger.Error(fmt.Sprintf("Failed to send notification: %v", err), "job", event.JobURL)
 		} else {
-			logger.Printf("Sent notification for %s", event.JobURL)
+			logger.Info(fmt.Sprintf("Sent notification for %s", event.JobURL), "job", event.JobURL)
+		}
+
+	case monitor.EventDurationExceeded:
+		if err := send(
+			"duration_exceeded",
+			"Build taking too long",
+			fmt.Sprintf("Job: %s\nStill running after %s.", event.JobName, event.Duration.Round(time.Minute)),
+		); err != nil && !errors.Is(err, errNotificationSuppressed) {
+			logger.Error(fmt.Sprintf("Failed to send notification: %v", err), "job", event.JobURL)
+		}
+
+	case monitor.EventCircuitOpen:
+		if err := send(
+			"circuit_open",
+			"Jenkins Unreachable",
+			fmt.Sprintf("%s keeps failing.\nPausing checks of its jobs for %s.", jenkins.RootURL(event.JobURL), event.Duration),
+		); err != nil && !errors.Is(err, errNotificationSuppressed) {
+			logger.Error(fmt.Sprintf("Failed to send notification: %v", err), "job", event.JobURL)
 		}
-		finishJob(event.JobURL, event.Result, logger, store, activeJobs)
 
 	case monitor.EventNotFound:
-		_ = notifier.Send(
+		_ = send(
+			"not_found",
 			"Jenkins Job Not Found",
 			fmt.Sprintf("Job: %s\nURL returned 404. Removing from monitor.", event.JobName),
-			event.JobURL,
 		)
 		removeJob(event.JobURL, logger, store, activeJobs)
 
 	case monitor.EventUnauthorized:
-		_ = notifier.Send(
+		_ = send(
+			"unauthorized",
 			"Jenkins Auth Failed",
 			fmt.Sprintf("Job: %s\nUnauthorized (401/403). Check credentials. Removing from monitor.", event.JobName),
-			event.JobURL,
 		)
 		removeJob(event.JobURL, logger, store, activeJobs)
 
 	case monitor.EventClientError:
-		_ = notifier.Send(
+		_ = send(
+			"client_error",
 			"Jenkins Request Error",
 			fmt.Sprintf("Job: %s\n%v. Removing from monitor.", event.JobName, event.Error),
-			event.JobURL,
 		)
 		removeJob(event.JobURL, logger, store, activeJobs)
 
 	case monitor.EventDNSError:
-		_ = notifier.Send(
+		_ = send(
+			"dns_error",
 			"Jenkins Job Unreachable",
 			fmt.Sprintf("Job: %s\nDNS lookup failed — host not found. Removing from monitor.", event.JobName),
-			event.JobURL,
 		)
 		removeJob(event.JobURL, logger, store, activeJobs)
 	}
 }
 
-func updateJobCheckStatus(jobURL string, failed bool, logger *log.Logger, store config.ConfigStore) {
+// recordEvent appends event to log, if there is one. Successful routine polls
+// are left out; they would soon push everything else out of the log.
+func recordEvent(log *eventlog.Log, event monitor.JobEvent, logger *slog.Logger) {
+	if log == nil || event.Kind == monitor.EventStatusChecked && !event.Failed {
+		return
+	}
+	if err := log.Append(event); err != nil {
+		logger.Error(fmt.Sprintf("Failed to record event: %v", err), "job", event.JobURL)
+	}
+}
+
+// updateJobCheckStatus records the outcome and time of a poll, the build
+// number if Jenkins reported one and, the first time it is known, when
+// Jenkins started the build. A failure only marks the job failed once the
+// job's retry limit is reached; the failure count is returned then so the
+// caller can alert, and 0 otherwise. Any success resets the count. The job is
+// returned as updated, or zero if it is no longer monitored.
+func updateJobCheckStatus(jobURL string, failed bool, buildStarted time.Time, buildNumber int, logger *slog.Logger, store config.ConfigStore) (job config.Job, alertFailures int) {
 	err := store.Update(func(cfg *config.Config) error {
-		if job, exists := cfg.Jobs[jobURL]; exists {
-			if job.LastCheckFailed != failed {
-				job.LastCheckFailed = failed
-				cfg.Jobs[jobURL] = job
+		var exists bool
+		job, exists = cfg.Jobs[jobURL]
+		if !exists {
+			return nil
+		}
+		if failed {
+			job.CheckFailureCount++
+			job.ConsecutiveFailures++
+			if job.ConsecutiveFailures == job.RetryLimit() {
+				alertFailures = job.ConsecutiveFailures
 			}
+		} else {
+			job.ConsecutiveFailures = 0
+		}
+		job.LastCheckFailed = job.ConsecutiveFailures >= job.RetryLimit()
+		if job.BuildStartTimestamp.IsZero() && !buildStarted.IsZero() {
+			job.BuildStartTimestamp = buildStarted
 		}
+		if buildNumber > 0 {
+			job.BuildNumber = buildNumber
+		}
+		job.LastPollTime = time.Now()
+		cfg.Jobs[jobURL] = job
+		return nil
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error updating job check status in config: %v", err), "job", jobURL)
+		return config.Job{}, 0
+	}
+	return job, alertFailures
+}
+
+func recordJobParameters(jobURL string, params map[string]string, logger *slog.Logger, store config.ConfigStore) {
+	err := store.Update(func(cfg *config.Config) error {
+		cfg.SetJobParameters(jobURL, params)
 		return nil
 	})
 	if err != nil {
-		logger.Printf("Error updating job check status in config: %v", err)
+		logger.Error(fmt.Sprintf("Error saving job parameters in config: %v", err), "job", jobURL)
+	}
+}
+
+// errNotificationSuppressed is returned when a notification is withheld
+// during quiet hours.
+var errNotificationSuppressed = errors.New("notification suppressed during quiet hours")
+
+// inQuietHours reports whether the configured quiet hours are in effect. A
+// broken quiet hours setting is logged and ignored.
+func inQuietHours(store config.ConfigStore, logger *slog.Logger) bool {
+	cfg, err := store.Load()
+	if err != nil {
+		return false
+	}
+	active, err := cfg.QuietHours.Active(time.Now())
+	if err != nil {
+		logger.Error(fmt.Sprintf("Ignoring quiet hours: %v", err))
+		return false
+	}
+	return active
+}
+
+// renderFinishedNotification executes the configured notification templates
+// with data, falling back to the defaults if they fail.
+func renderFinishedNotification(store config.ConfigStore, logger *slog.Logger, data config.NotificationData) (title, message string) {
+	defaults := &config.Config{}
+	cfg, err := store.Load()
+	if err != nil {
+		cfg = defaults
+	}
+	title, message, err = executeNotificationTemplates(cfg, data)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Notification template failed, using the default: %v", err), "job", data.JobURL)
+		title, message, _ = executeNotificationTemplates(defaults, data)
+	}
+	return title, message
+}
+
+func executeNotificationTemplates(cfg *config.Config, data config.NotificationData) (title, message string, err error) {
+	titleTmpl, bodyTmpl, err := cfg.NotificationTemplates()
+	if err != nil {
+		return "", "", err
 	}
+	var t, b strings.Builder
+	if err := titleTmpl.Execute(&t, data); err != nil {
+		return "", "", err
+	}
+	if err := bodyTmpl.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	return t.String(), b.String(), nil
 }
 
-func finishJob(jobURL string, result string, logger *log.Logger, store config.ConfigStore, activeJobs map[string]chan struct{}) {
+// finishedBuildDuration is how long a finished build ran: the duration
+// Jenkins reported or, if it reported none, the time since Jenkins started
+// the build, if that was recorded.
+func finishedBuildDuration(job config.Job, reported time.Duration, now time.Time) time.Duration {
+	if reported > 0 || job.BuildStartTimestamp.IsZero() {
+		return reported
+	}
+	return now.Sub(job.BuildStartTimestamp)
+}
+
+// formatBuildDuration renders d like "1h 2m 3s" or "4m 32s", or "" if d is
+// not positive.
+func formatBuildDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return ""
+	}
+	hrs := d / time.Hour
+	d -= hrs * time.Hour
+	mins := d / time.Minute
+	d -= mins * time.Minute
+	secs := d / time.Second
+	switch {
+	case hrs > 0:
+		return fmt.Sprintf("%dh %dm %ds", hrs, mins, secs)
+	case mins > 0:
+		return fmt.Sprintf("%dm %ds", mins, secs)
+	default:
+		return fmt.Sprintf("%ds", secs)
+	}
+}
+
+func formatTestSummary(s *jenkins.TestSummary) string {
+	if s == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d passed, %d failed, %d skipped", s.Passed(), s.Failed, s.Skipped)
+}
+
+// maxNotificationChanges is how many commits formatChanges lists.
+const maxNotificationChanges = 3
+
+// formatChanges lists the first line of each commit message and its author,
+// one per line, summarising commits beyond maxNotificationChanges.
+func formatChanges(changes []jenkins.ChangeEntry) string {
+	var lines []string
+	for _, c := range changes[:min(len(changes), maxNotificationChanges)] {
+		msg, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		if c.Author != "" {
+			msg += " (" + c.Author + ")"
+		}
+		lines = append(lines, "- "+msg)
+	}
+	if rest := len(changes) - maxNotificationChanges; rest > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more…", rest))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// finishedNotification picks the notification kind and title for a finished
+// build given the result of the job's previous build, if any.
+func finishedNotification(previous, result string) (kind, title string) {
+	switch {
+	case previous == "SUCCESS" && result == "FAILURE":
+		return "regression", "Build Regression"
+	case previous == "FAILURE" && result == "SUCCESS":
+		return "recovery", "Build Recovered"
+	case previous == "FAILURE" && result == "FAILURE":
+		return "finished", "Jenkins Job Still Failing"
+	case result == "FAILURE":
+		return "finished", "Jenkins Job Failed"
+	default:
+		return "finished", "Jenkins Job Completed"
+	}
+}
+
+// finishJob records the finished build and stops its monitor, unless the job
+// is recurring: then it stays in the config and its monitor keeps waiting for
+// the next build. It returns the result of the job's previous recorded build,
+// or "" if there is none, and the job as it was monitored.
+func finishJob(event monitor.JobEvent, logger *slog.Logger, store config.ConfigStore, activeJobs map[string]activeJob) (previous string, job config.Job) {
+	jobURL := event.JobURL
+	key := jenkins.JobURLFromBuildURL(jobURL)
 	err := store.Update(func(cfg *config.Config) error {
-		cfg.FinishJob(jobURL, result)
+		previous = ""
+		job = cfg.Jobs[jobURL]
+		if records := cfg.CompletionHistory[key]; len(records) > 0 {
+			previous = records[len(records)-1].Result
+		}
+		if job.Recurring {
+			cfg.RecurJob(jobURL, event.Result, event.BuildStarted)
+		} else {
+			cfg.FinishJob(jobURL, event.Result)
+		}
+		cfg.RecordCompletion(key, config.BuildRecord{
+			FinishedAt: time.Now(),
+			Result:     event.Result,
+			Duration:   event.Duration,
+		})
 		return nil
 	})
 	if err != nil {
-		logger.Printf("Error finishing job in config: %v", err)
+		logger.Error(fmt.Sprintf("Error finishing job in config: %v", err), "job", jobURL)
 	}
 
-	if stopChan, exists := activeJobs[jobURL]; exists {
+	if job.Recurring {
+		return previous, job
+	}
+	if active, exists := activeJobs[jobURL]; exists {
 		delete(activeJobs, jobURL)
-		close(stopChan)
+		close(active.stop)
 	}
+	return previous, job
 }
 
-func removeJob(jobURL string, logger *log.Logger, store config.ConfigStore, activeJobs map[string]chan struct{}) {
+func removeJob(jobURL string, logger *slog.Logger, store config.ConfigStore, activeJobs map[string]activeJob) {
 	err := store.Update(func(cfg *config.Config) error {
 		delete(cfg.Jobs, jobURL)
 		return nil
 	})
 	if err != nil {
-		logger.Printf("Error removing finished job from config: %v", err)
+		logger.Error(fmt.Sprintf("Error removing finished job from config: %v", err), "job", jobURL)
 	}
 
-	if stopChan, exists := activeJobs[jobURL]; exists {
+	if active, exists := activeJobs[jobURL]; exists {
 		delete(activeJobs, jobURL)
-		close(stopChan)
+		close(active.stop)
 	}
 }
 
+// activeJob tracks a running monitor goroutine and the interval it was started with.
+type activeJob struct {
+	stop         chan struct{}
+	pollInterval time.Duration
+}
+
 type DaemonDeps struct {
 	Store          config.ConfigStore
 	Notifier       notify.Notifier
 	Token          string
+	ProfileToken   func(profile string) (string, error)
 	SigChan        <-chan os.Signal
 	Stop           <-chan struct{}
 	PollInterval   time.Duration
 	TickerInterval time.Duration
 	OnTick         func()
+	// MetricsAddr, if set, is the address to serve Prometheus metrics on.
+	MetricsAddr string
+	// ConfigPath, if set, is watched so config changes are picked up without
+	// a SIGHUP.
+	ConfigPath string
+	// OnConfigReload, if set, is called after a change to ConfigPath made by
+	// another process has been reloaded.
+	OnConfigReload func()
+	// RebuildNotifier, if set, is called with every reloaded config so
+	// notification destinations added since the daemon started are used.
+	RebuildNotifier func(cfg *config.Config)
+	// UsePollingFallback reloads the config on every tick instead of watching
+	// ConfigPath. It is also used when the file watcher cannot be set up.
+	UsePollingFallback bool
+	// SocketPath, if set, is where the daemon listens for control requests.
+	SocketPath string
+	// Limiters rate-limits requests to each Jenkins host. Nil means no limit.
+	Limiters *monitor.Limiters
+	// EventLog, if set, records the job events the daemon handles.
+	EventLog *eventlog.Log
+}
+
+// swappableNotifier sends through a notifier that can be replaced while the
+// daemon runs, so a config reload can change where notifications go.
+type swappableNotifier struct {
+	mu       sync.Mutex
+	notifier notify.Notifier
+}
+
+func (s *swappableNotifier) Send(n notify.Notification) error {
+	s.mu.Lock()
+	notifier := s.notifier
+	s.mu.Unlock()
+	return notifier.Send(n)
+}
+
+func (s *swappableNotifier) set(notifier notify.Notifier) {
+	s.mu.Lock()
+	s.notifier = notifier
+	s.mu.Unlock()
+}
+
+// controlRequest carries a socket request to the daemon loop, which owns the
+// active jobs, and its response back.
+type controlRequest struct {
+	req   daemon.Request
+	reply chan<- daemon.Response
+}
+
+// listenControlSocket serves the control socket at path, forwarding requests to
+// the returned channel. Closing done makes pending requests fail instead of
+// waiting for a loop that has exited.
+func listenControlSocket(path string, done <-chan struct{}) (*daemon.Server, <-chan controlRequest, error) {
+	requests := make(chan controlRequest)
+	srv, err := daemon.Listen(path, func(req daemon.Request) daemon.Response {
+		reply := make(chan daemon.Response, 1)
+		select {
+		case requests <- controlRequest{req: req, reply: reply}:
+			return <-reply
+		case <-done:
+			return daemon.Response{Error: "daemon is shutting down"}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return srv, requests, nil
 }
 
-func reloadConfigAndJobs(deps DaemonDeps, logger *log.Logger, activeJobs map[string]chan struct{}, events chan<- monitor.JobEvent) {
+// handleControlRequest answers a socket request. It reports whether the daemon
+// should stop.
+func handleControlRequest(req daemon.Request, deps DaemonDeps, logger *slog.Logger, activeJobs map[string]activeJob, events chan<- monitor.JobEvent) (daemon.Response, bool) {
+	switch req.Command {
+	case daemon.CommandReload:
+		logger.Info("Reload requested, reloading config...")
+		reloadConfigAndJobs(deps, logger, activeJobs, events)
+		return daemon.Response{OK: true}, false
+
+	case daemon.CommandStatus:
+		jobs := make([]string, 0, len(activeJobs))
+		for jobURL := range activeJobs {
+			jobs = append(jobs, jobURL)
+		}
+		sort.Strings(jobs)
+		return daemon.Response{OK: true, PID: os.Getpid(), Jobs: jobs}, false
+
+	case daemon.CommandAdd:
+		if req.URL == "" {
+			return daemon.Response{Error: "add requires a url"}, false
+		}
+		jobURL, err := jenkins.NormalizeJobURL(req.URL)
+		if err != nil {
+			return daemon.Response{Error: err.Error()}, false
+		}
+		err = deps.Store.Update(func(cfg *config.Config) error {
+			cfg.AddJob(jobURL)
+			return nil
+		})
+		if err != nil {
+			return daemon.Response{Error: fmt.Sprintf("failed to add job: %v", err)}, false
+		}
+		reloadConfigAndJobs(deps, logger, activeJobs, events)
+		return daemon.Response{OK: true}, false
+
+	case daemon.CommandStop:
+		return daemon.Response{OK: true}, true
+	}
+	return daemon.Response{Error: fmt.Sprintf("unknown command %q", req.Command)}, false
+}
+
+// configReloadDebounce coalesces the bursts of write events a single config
+// save produces into one reload.
+const configReloadDebounce = 100 * time.Millisecond
+
+// watchConfigFile returns a watcher on the directory holding path. The
+// directory is watched so the file being replaced is noticed too.
+func watchConfigFile(path string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+func reloadConfigAndJobs(deps DaemonDeps, logger *slog.Logger, activeJobs map[string]activeJob, events chan<- monitor.JobEvent) {
 	reloadedCfg, err := deps.Store.Load()
 	if err != nil {
-		logger.Printf("Error reloading config: %v", err)
+		logger.Error(fmt.Sprintf("Error reloading config: %v", err))
 		return
 	}
 
+	if deps.RebuildNotifier != nil {
+		deps.RebuildNotifier(reloadedCfg)
+	}
+
 	currentConfigJobs := reloadedCfg.GetJobs()
 
-	for jobURL, stopChan := range activeJobs {
-		if _, exists := currentConfigJobs[jobURL]; !exists {
-			logger.Printf("Stopping monitoring for removed job: %s", jobURL)
+	for jobURL, active := range activeJobs {
+		job, exists := currentConfigJobs[jobURL]
+		if !exists {
+			logger.Info(fmt.Sprintf("Stopping monitoring for removed job: %s", jobURL), "job", jobURL)
 			delete(activeJobs, jobURL)
-			close(stopChan)
+			close(active.stop)
+			continue
+		}
+		if job.Paused {
+			logger.Info(fmt.Sprintf("Pausing monitoring for %s", jobURL), "job", jobURL)
+			delete(activeJobs, jobURL)
+			close(active.stop)
+			continue
+		}
+		if interval := monitor.ResolvePollInterval(job.PollInterval, deps.PollInterval); interval != active.pollInterval {
+			logger.Info(fmt.Sprintf("Poll interval changed for %s (%s -> %s), restarting monitor", jobURL, active.pollInterval, interval), "job", jobURL)
+			delete(activeJobs, jobURL)
+			close(active.stop)
 		}
 	}
 
-	for jobURL := range currentConfigJobs {
+	for jobURL, job := range currentConfigJobs {
+		if job.Paused {
+			continue
+		}
 		if _, running := activeJobs[jobURL]; !running {
-			logger.Printf("Starting to monitor new job: %s", jobURL)
+			token, err := jobToken(deps, job)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Cannot monitor %s: %v", jobURL, err), "job", jobURL)
+				continue
+			}
+			logger.Info(fmt.Sprintf("Starting to monitor new job: %s", jobURL), "job", jobURL)
+			interval := monitor.ResolvePollInterval(job.PollInterval, deps.PollInterval)
 			stopChan := make(chan struct{})
-			activeJobs[jobURL] = stopChan
-			go monitor.MonitorJob(jobURL, deps.Token, logger, events, deps.PollInterval, stopChan)
+			activeJobs[jobURL] = activeJob{stop: stopChan, pollInterval: interval}
+			alert := monitor.DurationAlert{Since: job.StartTime, Max: time.Duration(job.MaxDurationMinutes) * time.Minute}
+			recurrence := monitor.Recurrence{Enabled: job.Recurring, LastReported: job.LastReportedBuild}
+			go monitor.MonitorJob(jobURL, token, logger, events, interval, alert, recurrence, deps.Limiters.For(jobURL), stopChan)
 		}
 	}
 
-	logger.Printf("Configuration reloaded. Monitoring %d jobs.", len(activeJobs))
+	logger.Info(fmt.Sprintf("Configuration reloaded. Monitoring %d jobs.", len(activeJobs)))
 }
 
-func runDaemonLoop(deps DaemonDeps, logger *log.Logger) error {
+// jobToken returns the credentials to poll job with: its profile's token, or
+// the daemon's default token when the job has no profile.
+func jobToken(deps DaemonDeps, job config.Job) (string, error) {
+	if job.Profile == "" || job.Profile == config.DefaultProfile || deps.ProfileToken == nil {
+		if deps.Token == "" {
+			return "", config.ErrNoCredentials
+		}
+		return deps.Token, nil
+	}
+	return deps.ProfileToken(job.Profile)
+}
+
+func runDaemonLoop(deps DaemonDeps, logger *slog.Logger) error {
 	if _, err := deps.Store.Load(); err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	activeJobs := make(map[string]chan struct{})
+	var m *metrics.Metrics
+	if deps.MetricsAddr != "" {
+		m = metrics.New()
+		if srv, err := m.Serve(deps.MetricsAddr); err != nil {
+			logger.Error(fmt.Sprintf("Cannot serve metrics, monitoring without them: %v", err))
+		} else {
+			logger.Info(fmt.Sprintf("Serving metrics on http://%s/metrics", srv.Addr()))
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := srv.Shutdown(ctx); err != nil {
+					logger.Error(fmt.Sprintf("Error shutting down metrics server: %v", err))
+				}
+			}()
+		}
+	}
+
+	activeJobs := make(map[string]activeJob)
 	events := make(chan monitor.JobEvent, 10)
 
 	reloadConfigAndJobs(deps, logger, activeJobs, events)
+	m.SetActiveJobs(len(activeJobs))
+
+	usePolling := deps.UsePollingFallback
+	var configEvents <-chan fsnotify.Event
+	var configErrors <-chan error
+	if deps.ConfigPath != "" && !usePolling {
+		watcher, err := watchConfigFile(deps.ConfigPath)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Cannot watch config file, falling back to polling: %v", err))
+			usePolling = true
+		} else {
+			defer watcher.Close()
+			configEvents, configErrors = watcher.Events, watcher.Errors
+		}
+	}
+
+	var controlRequests <-chan controlRequest
+	if deps.SocketPath != "" {
+		done := make(chan struct{})
+		srv, requests, err := listenControlSocket(deps.SocketPath, done)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Cannot listen on control socket, only signals will work: %v", err))
+		} else {
+			// Deferred calls run in reverse: done is closed before Close waits
+			// for in-flight requests.
+			defer srv.Close()
+			defer close(done)
+			controlRequests = requests
+		}
+	}
+
+	var reloadTimer *time.Timer
+	var reloadC <-chan time.Time
 
 	tickerInterval := deps.TickerInterval
 	if tickerInterval <= 0 {
@@ -184,87 +706,221 @@ func runDaemonLoop(deps DaemonDeps, logger *log.Logger) error {
 	for {
 		select {
 		case <-deps.Stop:
-			logger.Println("Stop received, stopping all monitors.")
-			for jobURL, stopChan := range activeJobs {
-				logger.Printf("Stopping monitor for %s", jobURL)
-				close(stopChan)
+			logger.Info("Stop received, stopping all monitors.")
+			for jobURL, active := range activeJobs {
+				logger.Info(fmt.Sprintf("Stopping monitor for %s", jobURL), "job", jobURL)
+				close(active.stop)
 			}
-			logger.Println("Daemon stopped.")
+			logger.Info("Daemon stopped.")
 			return nil
 
 		case sig := <-deps.SigChan:
 			switch sig {
 			case syscall.SIGHUP:
-				logger.Println("SIGHUP received, reloading config...")
+				logger.Info("SIGHUP received, reloading config...")
 				reloadConfigAndJobs(deps, logger, activeJobs, events)
+				m.SetActiveJobs(len(activeJobs))
 			case syscall.SIGINT, syscall.SIGTERM:
-				logger.Println("Shutdown signal received, stopping all monitors.")
-				for jobURL, stopChan := range activeJobs {
-					logger.Printf("Stopping monitor for %s", jobURL)
-					close(stopChan)
+				logger.Info("Shutdown signal received, stopping all monitors.")
+				for jobURL, active := range activeJobs {
+					logger.Info(fmt.Sprintf("Stopping monitor for %s", jobURL), "job", jobURL)
+					close(active.stop)
 				}
 				time.Sleep(1 * time.Second)
-				logger.Println("Daemon stopped.")
+				logger.Info("Daemon stopped.")
+				return nil
+			}
+
+		case cr := <-controlRequests:
+			resp, stop := handleControlRequest(cr.req, deps, logger, activeJobs, events)
+			m.SetActiveJobs(len(activeJobs))
+			cr.reply <- resp
+			if stop {
+				logger.Info("Stop requested, stopping all monitors.")
+				for jobURL, active := range activeJobs {
+					logger.Info(fmt.Sprintf("Stopping monitor for %s", jobURL), "job", jobURL)
+					close(active.stop)
+				}
+				logger.Info("Daemon stopped.")
 				return nil
 			}
 
 		case event := <-events:
-			handleJobEvent(event, logger, deps.Store, activeJobs, deps.Notifier)
+			handleJobEvent(event, logger, deps.Store, activeJobs, deps.Notifier, m)
+			m.SetActiveJobs(len(activeJobs))
+			recordEvent(deps.EventLog, event, logger)
+
+		case ev := <-configEvents:
+			if filepath.Clean(ev.Name) != filepath.Clean(deps.ConfigPath) || !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) {
+				continue
+			}
+			if reloadTimer == nil {
+				reloadTimer = time.NewTimer(configReloadDebounce)
+				defer reloadTimer.Stop()
+			} else {
+				reloadTimer.Reset(configReloadDebounce)
+			}
+			reloadC = reloadTimer.C
+
+		case err := <-configErrors:
+			logger.Warn(fmt.Sprintf("Config watcher error: %v", err))
+
+		case <-reloadC:
+			reloadC = nil
+			if ownConfigWrite(deps) {
+				continue
+			}
+			logger.Info("Config file changed, reloading...")
+			reloadConfigAndJobs(deps, logger, activeJobs, events)
+			m.SetActiveJobs(len(activeJobs))
+			if deps.OnConfigReload != nil {
+				deps.OnConfigReload()
+			}
 
 		case <-ticker.C:
 			if deps.OnTick != nil {
 				deps.OnTick()
 			}
+			if usePolling {
+				reloadConfigAndJobs(deps, logger, activeJobs, events)
+				m.SetActiveJobs(len(activeJobs))
+			}
 
 			if len(activeJobs) == 0 {
-				logger.Println("No more jobs to monitor. Shutting down daemon.")
+				logger.Info("No more jobs to monitor. Shutting down daemon.")
 				return nil
 			}
 		}
 	}
 }
 
+// ownConfigWrite reports whether ConfigPath still holds what the daemon's own
+// store last wrote, i.e. the change event was caused by the daemon itself.
+func ownConfigWrite(deps DaemonDeps) bool {
+	store, ok := deps.Store.(interface{ WroteLast([]byte) bool })
+	if !ok {
+		return false
+	}
+	data, err := os.ReadFile(deps.ConfigPath)
+	return err == nil && store.WroteLast(data)
+}
+
+// metricsAddrFromEnv returns the address to serve metrics on: port
+// JW_METRICS_PORT of JW_METRICS_HOST, which defaults to 127.0.0.1 so the
+// metrics are not exposed to the network unless asked for. It returns "" if
+// JW_METRICS_PORT is unset.
+func metricsAddrFromEnv() (string, error) {
+	port := os.Getenv("JW_METRICS_PORT")
+	if port == "" {
+		return "", nil
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid JW_METRICS_PORT %q", port)
+	}
+	host := os.Getenv("JW_METRICS_HOST")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
 func startDaemon(cmd *cobra.Command, args []string) {
 	if _, running := pidfile.IsDaemonRunning(); running {
 		log.Println("Daemon already running.")
 		return
 	}
 
+	// Jobs may all use named profiles, so a missing default profile is only
+	// fatal when no profiles exist at all.
 	token, err := config.GetCredentials()
 	if err != nil {
-		log.Fatalln(err)
+		if profiles, _ := config.ListProfiles(); len(profiles) == 0 {
+			log.Fatalln(err)
+		}
 	}
 
-	logger, err := logging.SetupLogger()
+	logger, err := logging.SetupLogger(daemonLogFormat)
 	if err != nil {
 		log.Fatalf("Failed to set up logger: %v", err)
 	}
-	logger.Println("Daemon starting...")
+	logger.Info("Daemon starting...")
+	config.LoadWarning = func(err error) { logger.Warn(err.Error()) }
 
 	if err := pidfile.Write(); err != nil {
-		logger.Fatalf("Failed to write PID file: %v", err)
+		logger.Error(fmt.Sprintf("Failed to write PID file: %v", err))
+		os.Exit(1)
 	}
 	defer pidfile.Remove()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
+	metricsAddr, err := metricsAddrFromEnv()
+	if err != nil {
+		logger.Error(fmt.Sprintf("%v, metrics disabled", err))
+	}
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve config path: %v", err))
+		os.Exit(1)
+	}
+
+	socketPath, err := daemon.SocketPath()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve control socket path: %v", err))
+		os.Exit(1)
+	}
+
+	limiters, err := monitor.LimitersFromEnv()
+	if err != nil {
+		logger.Error(fmt.Sprintf("%v, using the default", err))
+	}
+
+	pollInterval, err := monitor.PollIntervalFromEnv()
+	if err != nil {
+		logger.Error(fmt.Sprintf("%v, using the default", err))
+	}
+
+	store := config.NewDiskStore()
+	cfg, err := store.Load()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load config: %v", err))
+		os.Exit(1)
+	}
+
+	eventLog, err := eventlog.Default()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve event log path, events will not be recorded: %v", err))
+	}
+
+	notifier := &swappableNotifier{notifier: buildNotifier(cfg)}
 	deps := DaemonDeps{
-		Store:          config.NewDiskStore(),
-		Notifier:       &notify.MacNotifier{},
+		Store:          store,
+		Notifier:       notifier,
 		Token:          token,
+		ProfileToken:   config.GetProfileCredentials,
 		SigChan:        sigChan,
 		Stop:           make(chan struct{}),
-		PollInterval:   0,
+		PollInterval:   pollInterval,
 		TickerInterval: 5 * time.Second,
+		MetricsAddr:    metricsAddr,
+		ConfigPath:     configPath,
+		SocketPath:     socketPath,
+		Limiters:       limiters,
+		EventLog:       eventLog,
 		OnTick: func() {
 			if err := pidfile.CheckAndRestore(); err != nil {
-				logger.Printf("Failed to verify/restore PID file: %v", err)
+				logger.Error(fmt.Sprintf("Failed to verify/restore PID file: %v", err))
 			}
 		},
+		RebuildNotifier: func(cfg *config.Config) {
+			notifier.set(buildNotifier(cfg))
+		},
 	}
 
 	if err := runDaemonLoop(deps, logger); err != nil {
-		logger.Fatalf("Daemon loop failed: %v", err)
+		logger.Error(fmt.Sprintf("Daemon loop failed: %v", err))
+		os.Exit(1)
 	}
 }
@@ -7,12 +7,18 @@ import (
 	"syscall"
 	"time"
 
+	"jenkins-monitor/pkg/daemon"
 	"jenkins-monitor/pkg/pidfile"
 	"jenkins-monitor/pkg/ui"
 )
 
+// signalDaemonReload makes the daemon reload its config, starting it first if
+// needed.
 func signalDaemonReload() bool {
 	pid := ensureDaemonRunning()
+	if requestDaemonReload() {
+		return true
+	}
 	if process, err := os.FindProcess(pid); err == nil {
 		process.Signal(syscall.SIGHUP)
 		return true
@@ -20,6 +26,37 @@ func signalDaemonReload() bool {
 	return false
 }
 
+// signalDaemonIfRunning asks a running daemon to reload without starting one.
+func signalDaemonIfRunning() bool {
+	pid, running := pidfile.IsDaemonRunning()
+	if !running {
+		return false
+	}
+	if requestDaemonReload() {
+		return true
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.SIGHUP) == nil
+}
+
+// requestDaemonReload sends a reload request over the control socket. It
+// returns false if the socket is missing or the request fails, in which case
+// callers fall back to SIGHUP.
+func requestDaemonReload() bool {
+	path, err := daemon.SocketPath()
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+	_, err = daemon.Send(path, daemon.Request{Command: daemon.CommandReload})
+	return err == nil
+}
+
 // startDaemonIfNeeded starts the daemon if it's not running and returns an error
 // instead of printing to stdout or exiting. Suitable for contexts where stdout
 // is not available (e.g., native messaging).
@@ -28,7 +65,7 @@ func startDaemonIfNeeded() error {
 		return nil
 	}
 
-	cmd := exec.Command(os.Args[0], "_start_jw_daemon")
+	cmd := exec.Command(os.Args[0], daemonArgs()...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 	cmd.ExtraFiles = nil
@@ -48,7 +85,20 @@ func startDaemonIfNeeded() error {
 	return nil
 }
 
+// daemonArgs returns the arguments a daemon is spawned with, passing on the
+// log format chosen with JW_LOG_FORMAT.
+func daemonArgs() []string {
+	args := []string{"_start_jw_daemon"}
+	if format := os.Getenv("JW_LOG_FORMAT"); format != "" {
+		args = append(args, "--log-format", format)
+	}
+	return args
+}
+
 func ensureDaemonRunning() int {
+	if err := pidfile.MigrateLegacy(); err != nil {
+		fmt.Println(ui.YellowText(fmt.Sprintf("Could not move the old PID file: %v", err)))
+	}
 	if pid, running := pidfile.IsDaemonRunning(); running {
 		return pid
 	}
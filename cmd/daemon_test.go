@@ -0,0 +1,808 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/daemon"
+	"jenkins-monitor/pkg/eventlog"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobToken(t *testing.T) {
+	deps := DaemonDeps{
+		Token: "default-token",
+		ProfileToken: func(profile string) (string, error) {
+			if profile == "staging" {
+				return "staging-token", nil
+			}
+			return "", errors.New("unknown profile")
+		},
+	}
+
+	token, err := jobToken(deps, config.Job{})
+	require.NoError(t, err)
+	assert.Equal(t, "default-token", token)
+
+	token, err = jobToken(deps, config.Job{Profile: "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, "staging-token", token)
+
+	_, err = jobToken(deps, config.Job{Profile: "prod"})
+	assert.Error(t, err)
+
+	_, err = jobToken(DaemonDeps{}, config.Job{})
+	assert.ErrorIs(t, err, config.ErrNoCredentials)
+}
+
+func TestHandleJobEvent_ResultTransitions(t *testing.T) {
+	const jobKey = "https://jenkins/job/app"
+
+	tests := []struct {
+		name      string
+		previous  string
+		result    string
+		wantTitle string
+	}{
+		{"regression", "SUCCESS", "FAILURE", "Build Regression"},
+		{"recovery", "FAILURE", "SUCCESS", "Build Recovered"},
+		{"still failing", "FAILURE", "FAILURE", "Jenkins Job Still Failing"},
+		{"still passing", "SUCCESS", "SUCCESS", "Jenkins Job Completed"},
+		{"first failure", "", "FAILURE", "Jenkins Job Failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildURL := jobKey + "/8/"
+			store := newMemStore(config.Job{URL: buildURL})
+			if tt.previous != "" {
+				require.NoError(t, store.Update(func(cfg *config.Config) error {
+					cfg.RecordCompletion(jobKey, config.BuildRecord{Result: tt.previous})
+					return nil
+				}))
+			}
+			notifier := &recordingNotifier{}
+			stop := make(chan struct{})
+			activeJobs := map[string]activeJob{buildURL: {stop: stop}}
+
+			handleJobEvent(monitor.JobEvent{
+				JobURL:  buildURL,
+				JobName: "app/8/",
+				Kind:    monitor.EventFinished,
+				Result:  tt.result,
+			}, logging.TextLogger(io.Discard), store, activeJobs, notifier, nil)
+
+			calls := notifier.getCalls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, tt.wantTitle, calls[0].Title)
+			assert.Contains(t, calls[0].Message, "Status: "+tt.result)
+			assert.Empty(t, activeJobs)
+
+			cfg, err := store.Load()
+			require.NoError(t, err)
+			records := cfg.CompletionHistory[jobKey]
+			require.NotEmpty(t, records)
+			assert.Equal(t, tt.result, records[len(records)-1].Result, "result becomes the next build's previous result")
+		})
+	}
+}
+
+func TestHandleJobEvent_RecurringJobStaysMonitored(t *testing.T) {
+	jobURL := "https://jenkins/job/nightly/lastBuild"
+	store := newMemStore(config.Job{URL: jobURL, Recurring: true, TriggerCause: "timer"})
+	notifier := &recordingNotifier{}
+	stop := make(chan struct{})
+	activeJobs := map[string]activeJob{jobURL: {stop: stop}}
+	logger := logging.TextLogger(io.Discard)
+
+	handleJobEvent(monitor.JobEvent{JobURL: jobURL, JobName: "nightly/lastBuild", Kind: monitor.EventFinished, Result: "SUCCESS"}, logger, store, activeJobs, notifier, nil)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	require.True(t, cfg.HasJob(jobURL), "recurring job stays in the config")
+	assert.Empty(t, cfg.Jobs[jobURL].TriggerCause, "the next build starts afresh")
+	assert.Contains(t, activeJobs, jobURL, "its monitor keeps running")
+	select {
+	case <-stop:
+		t.Fatal("monitor of a recurring job was stopped")
+	default:
+	}
+
+	handleJobEvent(monitor.JobEvent{JobURL: jobURL, JobName: "nightly/lastBuild", Kind: monitor.EventFinished, Result: "FAILURE"}, logger, store, activeJobs, notifier, nil)
+
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(jobURL))
+	require.Len(t, cfg.History, 2)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "Jenkins Job Completed (recurring)", calls[0].Title)
+	assert.Contains(t, calls[0].Message, "Triggered by: timer")
+	assert.Equal(t, "Build Regression (recurring)", calls[1].Title)
+}
+
+func TestHandleJobEvent_NotificationTemplates(t *testing.T) {
+	buildURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: buildURL})
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.NotifyTitleTemplate = "{{.Result}}: {{.JobName}}"
+		cfg.NotifyBodyTemplate = "{{.Title}} after {{.Duration}} - {{.JobURL}}"
+		return nil
+	}))
+	notifier := &recordingNotifier{}
+	activeJobs := map[string]activeJob{buildURL: {stop: make(chan struct{})}}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:   buildURL,
+		JobName:  "app/8/",
+		Kind:     monitor.EventFinished,
+		Result:   "FAILURE",
+		Duration: 90*time.Second + 400*time.Millisecond,
+	}, logging.TextLogger(io.Discard), store, activeJobs, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "FAILURE: app/8/", calls[0].Title)
+	assert.Equal(t, "Jenkins Job Failed after 1m 30s - https://jenkins/job/app/8/", calls[0].Message)
+}
+
+func TestHandleJobEvent_BrokenTemplateFallsBack(t *testing.T) {
+	buildURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: buildURL})
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.NotifyBodyTemplate = "{{.NoSuchField}}"
+		return nil
+	}))
+	notifier := &recordingNotifier{}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:  buildURL,
+		JobName: "app/8/",
+		Kind:    monitor.EventFinished,
+		Result:  "SUCCESS",
+	}, logging.TextLogger(io.Discard), store, map[string]activeJob{}, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Jenkins Job Completed", calls[0].Title)
+	assert.Equal(t, "Job: app/8/\nStatus: SUCCESS", calls[0].Message)
+}
+
+func TestHandleJobEvent_BuildNumber(t *testing.T) {
+	jobURL := "https://jenkins/job/app/lastBuild/"
+	store := newMemStore(config.Job{URL: jobURL})
+	notifier := &recordingNotifier{}
+	logger := logging.TextLogger(io.Discard)
+
+	handleJobEvent(monitor.JobEvent{JobURL: jobURL, JobName: "app/lastBuild/", Kind: monitor.EventStatusChecked, BuildNumber: 123}, logger, store, map[string]activeJob{}, notifier, nil)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 123, cfg.Jobs[jobURL].BuildNumber)
+
+	handleJobEvent(monitor.JobEvent{JobURL: jobURL, JobName: "app/lastBuild/", Kind: monitor.EventFinished, Result: "SUCCESS"}, logger, store, map[string]activeJob{}, notifier, nil)
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Job: app/lastBuild/\nBuild #123: SUCCESS", calls[0].Message)
+}
+
+func TestHandleJobEvent_BuildDetailsInNotification(t *testing.T) {
+	buildURL := "https://jenkins/job/app/9/"
+	store := newMemStore(config.Job{URL: buildURL, TriggerCause: "John Doe"})
+	notifier := &recordingNotifier{}
+	logger := logging.TextLogger(io.Discard)
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:     buildURL,
+		JobName:    "app/9/",
+		Kind:       monitor.EventParameters,
+		Parameters: map[string]string{"BRANCH": "main", "ENV": "prod", "DRY_RUN": "false", "TAG": "v1"},
+	}, logger, store, map[string]activeJob{}, notifier, nil)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cfg.Jobs[buildURL].Parameters["ENV"])
+	assert.Empty(t, notifier.getCalls())
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:  buildURL,
+		JobName: "app/9/",
+		Kind:    monitor.EventFinished,
+		Result:  "SUCCESS",
+		Tests:   &jenkins.TestSummary{Total: 46, Failed: 3, Skipped: 1},
+		Changes: []jenkins.ChangeEntry{{CommitID: "a1b2c3", Message: "Fix login redirect", Author: "Jane Roe"}},
+	}, logger, store, map[string]activeJob{}, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Job: app/9/\nStatus: SUCCESS\nParameters: BRANCH=main, DRY_RUN=false, ENV=prod (+1 more)\nTriggered by: John Doe\nTests: 42 passed, 3 failed, 1 skipped\nChanges:\n- Fix login redirect (Jane Roe)", calls[0].Message)
+}
+
+func TestFinishedBuildDuration(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := config.Job{
+		StartTime:           now.Add(-10 * time.Minute),
+		BuildStartTimestamp: now.Add(-4*time.Minute - 32*time.Second),
+	}
+
+	assert.Equal(t, 3*time.Minute, finishedBuildDuration(job, 3*time.Minute, now), "Jenkins' own duration wins")
+
+	d := finishedBuildDuration(job, 0, now)
+	assert.Equal(t, 4*time.Minute+32*time.Second, d, "measured from when Jenkins started the build, not when jw started watching")
+	assert.Equal(t, "4m 32s", formatBuildDuration(d))
+
+	assert.Equal(t, 3*time.Minute, finishedBuildDuration(config.Job{StartTime: job.StartTime}, 3*time.Minute, now))
+	assert.Zero(t, finishedBuildDuration(config.Job{StartTime: job.StartTime}, 0, now))
+
+	assert.Equal(t, "45s", formatBuildDuration(45*time.Second))
+	assert.Equal(t, "1h 2m 3s", formatBuildDuration(time.Hour+2*time.Minute+3*time.Second))
+	assert.Empty(t, formatBuildDuration(0))
+}
+
+func TestHandleJobEvent_RecordsBuildStart(t *testing.T) {
+	buildURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: buildURL})
+	notifier := &recordingNotifier{}
+	logger := logging.TextLogger(io.Discard)
+	started := time.Now().Add(-4*time.Minute - 32*time.Second).Truncate(time.Millisecond)
+
+	handleJobEvent(monitor.JobEvent{JobURL: buildURL, Kind: monitor.EventStatusChecked, BuildStarted: started}, logger, store, map[string]activeJob{}, notifier, nil)
+	handleJobEvent(monitor.JobEvent{JobURL: buildURL, Kind: monitor.EventStatusChecked, BuildStarted: started.Add(time.Minute)}, logger, store, map[string]activeJob{}, notifier, nil)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, started.Equal(cfg.Jobs[buildURL].BuildStartTimestamp), "only the first start time is kept")
+
+	handleJobEvent(monitor.JobEvent{JobURL: buildURL, JobName: "app/8/", Kind: monitor.EventFinished, Result: "SUCCESS"}, logger, store, map[string]activeJob{}, notifier, nil)
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Contains(t, calls[0].Message, "\nCompleted in 4m 3")
+}
+
+func TestFormatChanges(t *testing.T) {
+	assert.Empty(t, formatChanges(nil))
+
+	changes := []jenkins.ChangeEntry{
+		{Message: "Fix login redirect\n\nThe session cookie was dropped.", Author: "Jane Roe"},
+		{Message: "Bump deps"},
+		{Message: "Add retries", Author: "John Doe"},
+		{Message: "Update README", Author: "John Doe"},
+		{Message: "Tidy imports", Author: "Jane Roe"},
+	}
+	assert.Equal(t, "- Fix login redirect (Jane Roe)\n- Bump deps\n- Add retries (John Doe)\nand 2 more…", formatChanges(changes))
+}
+
+func TestHandleJobEvent_CircuitOpenKeepsMonitoring(t *testing.T) {
+	buildURL := "https://jenkins.example.com/job/app/8/"
+	store := newMemStore(config.Job{URL: buildURL})
+	notifier := &recordingNotifier{}
+	activeJobs := map[string]activeJob{buildURL: {stop: make(chan struct{})}}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:   buildURL,
+		JobName:  "app/8/",
+		Kind:     monitor.EventCircuitOpen,
+		Duration: time.Minute,
+	}, logging.TextLogger(io.Discard), store, activeJobs, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Jenkins Unreachable", calls[0].Title)
+	assert.Equal(t, "https://jenkins.example.com keeps failing.\nPausing checks of its jobs for 1m0s.", calls[0].Message)
+	assert.Contains(t, activeJobs, buildURL)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Contains(t, cfg.Jobs, buildURL)
+}
+
+func TestHandleJobEvent_QuietHoursSuppressNotifications(t *testing.T) {
+	buildURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: buildURL})
+	now := time.Now().UTC()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.QuietHours = config.QuietHours{
+			Start:    now.Add(-time.Hour).Format("15:04"),
+			End:      now.Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		}
+		return nil
+	}))
+	notifier := &recordingNotifier{}
+	activeJobs := map[string]activeJob{buildURL: {stop: make(chan struct{})}}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:  buildURL,
+		JobName: "app/8/",
+		Kind:    monitor.EventFinished,
+		Result:  "FAILURE",
+	}, logging.TextLogger(io.Discard), store, activeJobs, notifier, nil)
+
+	assert.Empty(t, notifier.getCalls(), "notifications are suppressed during quiet hours")
+	assert.Empty(t, activeJobs, "the job still finishes")
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.HasJob(buildURL))
+}
+
+func TestHandleJobEvent_DurationExceededKeepsMonitoring(t *testing.T) {
+	jobURL := "https://jenkins/job/slow/3"
+	store := newMemStore(config.Job{URL: jobURL})
+	notifier := &recordingNotifier{}
+	activeJobs := map[string]activeJob{jobURL: {stop: make(chan struct{})}}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:   jobURL,
+		JobName:  "slow/3",
+		Kind:     monitor.EventDurationExceeded,
+		Duration: 61 * time.Minute,
+	}, logging.TextLogger(io.Discard), store, activeJobs, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Build taking too long", calls[0].Title)
+	assert.Contains(t, calls[0].Message, "1h1m0s")
+	assert.Contains(t, activeJobs, jobURL)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(jobURL))
+}
+
+func TestHandleJobEvent_TTLExpired(t *testing.T) {
+	expired := "https://jenkins/job/stale/1"
+	fresh := "https://jenkins/job/fresh/2"
+	store := newMemStore(
+		config.Job{URL: expired, StartTime: time.Now().Add(-3 * time.Hour), MaxMonitorHours: 2},
+		config.Job{URL: fresh, StartTime: time.Now().Add(-3 * time.Hour)},
+	)
+	notifier := &recordingNotifier{}
+	expiredStop := make(chan struct{})
+	activeJobs := map[string]activeJob{expired: {stop: expiredStop}, fresh: {stop: make(chan struct{})}}
+	logger := logging.TextLogger(io.Discard)
+
+	handleJobEvent(monitor.JobEvent{JobURL: fresh, JobName: "fresh/2", Kind: monitor.EventStatusChecked}, logger, store, activeJobs, notifier, nil)
+	assert.Empty(t, notifier.getCalls(), "jobs without a TTL never expire")
+	assert.Contains(t, activeJobs, fresh)
+
+	handleJobEvent(monitor.JobEvent{JobURL: expired, JobName: "stale/1", Kind: monitor.EventStatusChecked}, logger, store, activeJobs, notifier, nil)
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Monitoring expired", calls[0].Title)
+	assert.Contains(t, calls[0].Message, "2h0m0s")
+	assert.NotContains(t, activeJobs, expired)
+	select {
+	case <-expiredStop:
+	default:
+		t.Fatal("monitor of the expired job was not stopped")
+	}
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.HasJob(expired))
+	assert.True(t, cfg.HasJob(fresh))
+}
+
+func TestRunDaemonLoop_ReloadsOnConfigWrite(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen[r.URL.Path] = true
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.AddJob(server.URL + "/job/first")
+		return nil
+	}))
+	configPath, err := config.GetConfigPath()
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	reloaded := make(chan struct{}, 10)
+	deps := DaemonDeps{
+		Store:          store,
+		Notifier:       &recordingNotifier{},
+		Token:          "token",
+		SigChan:        make(chan os.Signal),
+		Stop:           stop,
+		PollInterval:   time.Hour,
+		TickerInterval: time.Hour,
+		ConfigPath:     configPath,
+		OnConfigReload: func() { reloaded <- struct{}{} },
+	}
+	go func() { done <- runDaemonLoop(deps, logging.TextLogger(io.Discard)) }()
+	defer func() {
+		close(stop)
+		require.NoError(t, <-done)
+	}()
+
+	hasSeen := func(path string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen[path+"/api/json"]
+	}
+	require.Eventually(t, func() bool { return hasSeen("/job/first") }, 2*time.Second, 5*time.Millisecond)
+
+	other := config.NewDiskStore()
+	require.NoError(t, other.Update(func(cfg *config.Config) error {
+		cfg.AddJob(server.URL + "/job/second")
+		return nil
+	}))
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not reloaded")
+	}
+	assert.Eventually(t, func() bool { return hasSeen("/job/second") }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestOwnConfigWrite(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configPath, err := config.GetConfigPath()
+	require.NoError(t, err)
+	store := config.NewDiskStore()
+	deps := DaemonDeps{Store: store, ConfigPath: configPath}
+
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins.example.com/job/a")
+		return nil
+	}))
+	assert.True(t, ownConfigWrite(deps))
+
+	require.NoError(t, config.NewDiskStore().Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins.example.com/job/b")
+		return nil
+	}))
+	assert.False(t, ownConfigWrite(deps))
+
+	deps.Store = config.NewMemoryStore()
+	assert.False(t, ownConfigWrite(deps))
+}
+
+func TestRunDaemonLoop_PollingFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	store := newMemStore(config.Job{URL: server.URL + "/job/first"})
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	deps := DaemonDeps{
+		Store:              store,
+		Notifier:           &recordingNotifier{},
+		Token:              "token",
+		SigChan:            make(chan os.Signal),
+		Stop:               stop,
+		PollInterval:       time.Hour,
+		TickerInterval:     10 * time.Millisecond,
+		UsePollingFallback: true,
+	}
+	go func() { done <- runDaemonLoop(deps, logging.TextLogger(io.Discard)) }()
+
+	// Removing the only job is noticed on a tick and the daemon exits.
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.RemoveJob(server.URL + "/job/first")
+		return nil
+	}))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		close(stop)
+		t.Fatal("daemon did not pick up the removed job")
+	}
+}
+
+func TestRunDaemonLoop_ControlSocket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "jw")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "d.sock")
+
+	first := server.URL + "/job/first"
+	store := newMemStore(config.Job{URL: first})
+	done := make(chan error, 1)
+	deps := DaemonDeps{
+		Store:          store,
+		Notifier:       &recordingNotifier{},
+		Token:          "token",
+		SigChan:        make(chan os.Signal),
+		Stop:           make(chan struct{}),
+		PollInterval:   time.Hour,
+		TickerInterval: time.Hour,
+		SocketPath:     socketPath,
+	}
+	go func() { done <- runDaemonLoop(deps, logging.TextLogger(io.Discard)) }()
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socketPath)
+		return err == nil
+	}, 2*time.Second, 5*time.Millisecond)
+
+	resp, err := daemon.Send(socketPath, daemon.Request{Command: daemon.CommandStatus})
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), resp.PID)
+	assert.Equal(t, []string{first}, resp.Jobs)
+
+	second := server.URL + "/job/second"
+	_, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandAdd, URL: second + "/"})
+	require.NoError(t, err)
+	_, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandAdd, URL: "not a url"})
+	assert.Error(t, err)
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(second))
+
+	resp, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandStatus})
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, second}, resp.Jobs)
+
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.RemoveJob(first)
+		return nil
+	}))
+	_, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandReload})
+	require.NoError(t, err)
+	resp, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandStatus})
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, resp.Jobs)
+
+	_, err = daemon.Send(socketPath, daemon.Request{Command: "bogus"})
+	assert.ErrorContains(t, err, "unknown command")
+
+	_, err = daemon.Send(socketPath, daemon.Request{Command: daemon.CommandStop})
+	require.NoError(t, err)
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("daemon loop did not stop")
+	}
+	_, err = os.Stat(socketPath)
+	assert.True(t, os.IsNotExist(err), "socket should be removed on exit")
+}
+
+func TestReloadConfigAndJobs_PollIntervalFromEnv(t *testing.T) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Query().Get("tree"), "building") {
+			mu.Lock()
+			polls[r.URL.Path]++
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	// JW_POLL_INTERVAL cannot go this low; the test sets the daemon default
+	// it would have produced directly.
+	interval := 20 * time.Millisecond
+
+	fromEnv, ownInterval := server.URL+"/job/env", server.URL+"/job/own"
+	store := newMemStore(config.Job{URL: fromEnv}, config.Job{URL: ownInterval, PollInterval: time.Hour})
+	deps := DaemonDeps{Store: store, Token: "token", PollInterval: interval}
+	activeJobs := make(map[string]activeJob)
+	defer func() {
+		for _, active := range activeJobs {
+			close(active.stop)
+		}
+	}()
+
+	reloadConfigAndJobs(deps, logging.TextLogger(io.Discard), activeJobs, make(chan monitor.JobEvent, 100))
+	assert.Equal(t, 20*time.Millisecond, activeJobs[fromEnv].pollInterval)
+	assert.Equal(t, time.Hour, activeJobs[ownInterval].pollInterval, "a job's own interval wins over the env var")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls["/job/env/api/json"] >= 3
+	}, 2*time.Second, 10*time.Millisecond, "the env interval drives the monitor's ticker")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, polls["/job/own/api/json"])
+}
+
+func TestHandleJobEvent_CheckFailureAlertsAtRetryLimit(t *testing.T) {
+	jobURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: jobURL})
+	notifier := &recordingNotifier{}
+	logger := logging.TextLogger(io.Discard)
+	fail := monitor.JobEvent{JobURL: jobURL, JobName: "app/8/", Kind: monitor.EventError, Failed: true, Error: errors.New("connection reset")}
+	ok := monitor.JobEvent{JobURL: jobURL, JobName: "app/8/", Kind: monitor.EventStatusChecked}
+	jobAfter := func() config.Job {
+		cfg, err := store.Load()
+		require.NoError(t, err)
+		return cfg.Jobs[jobURL]
+	}
+
+	// Two hiccups and a success stay below the default limit of 3.
+	handleJobEvent(fail, logger, store, map[string]activeJob{}, notifier, nil)
+	handleJobEvent(fail, logger, store, map[string]activeJob{}, notifier, nil)
+	assert.False(t, jobAfter().LastCheckFailed)
+	handleJobEvent(ok, logger, store, map[string]activeJob{}, notifier, nil)
+	assert.Zero(t, jobAfter().ConsecutiveFailures, "a success resets the count")
+	assert.Empty(t, notifier.getCalls())
+
+	for range 4 {
+		handleJobEvent(fail, logger, store, map[string]activeJob{}, notifier, nil)
+	}
+	assert.True(t, jobAfter().LastCheckFailed)
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1, "the alert fires once, on reaching the limit")
+	assert.Equal(t, "Jenkins Check Failing", calls[0].Title)
+	assert.Equal(t, "Job: app/8/\n3 checks in a row failed. Last error: connection reset", calls[0].Message)
+
+	handleJobEvent(ok, logger, store, map[string]activeJob{}, notifier, nil)
+	assert.False(t, jobAfter().LastCheckFailed)
+	assert.Equal(t, 6, jobAfter().CheckFailureCount, "the total is not reset by a success")
+	assert.WithinDuration(t, time.Now(), jobAfter().LastPollTime, time.Minute)
+}
+
+func TestHandleJobEvent_CheckFailureCustomRetryLimit(t *testing.T) {
+	jobURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: jobURL, MaxRetries: 1})
+	notifier := &recordingNotifier{}
+
+	handleJobEvent(monitor.JobEvent{JobURL: jobURL, JobName: "app/8/", Kind: monitor.EventError, Failed: true}, logging.TextLogger(io.Discard), store, map[string]activeJob{}, notifier, nil)
+	assert.Len(t, notifier.getCalls(), 1)
+}
+
+func TestHandleJobEvent_FailedStagesInNotification(t *testing.T) {
+	jobURL := "https://jenkins/job/app/8/"
+	store := newMemStore(config.Job{URL: jobURL})
+	notifier := &recordingNotifier{}
+
+	handleJobEvent(monitor.JobEvent{
+		JobURL:       jobURL,
+		JobName:      "app/8/",
+		Kind:         monitor.EventFinished,
+		Result:       "FAILURE",
+		FailedStages: []string{"Test", "Lint"},
+	}, logging.TextLogger(io.Discard), store, map[string]activeJob{}, notifier, nil)
+
+	calls := notifier.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "Job: app/8/\nStatus: FAILURE\nFailed stages: Test, Lint", calls[0].Message)
+}
+
+func TestRecordEvent_SkipsRoutinePolls(t *testing.T) {
+	log := eventlog.New(filepath.Join(t.TempDir(), "events.jsonl"))
+	logger := logging.TextLogger(io.Discard)
+	recordEvent(log, monitor.JobEvent{JobURL: "https://j/job/a/1", Kind: monitor.EventStatusChecked}, logger)
+	recordEvent(log, monitor.JobEvent{JobURL: "https://j/job/a/1", Kind: monitor.EventStatusChecked, Failed: true}, logger)
+	recordEvent(log, monitor.JobEvent{JobURL: "https://j/job/a/1", Kind: monitor.EventFinished, Result: "SUCCESS"}, logger)
+	recordEvent(nil, monitor.JobEvent{JobURL: "https://j/job/a/1", Kind: monitor.EventFinished}, logger)
+
+	events, err := log.ReadAll(eventlog.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.True(t, events[0].Failed)
+	assert.Equal(t, "SUCCESS", events[1].Result)
+}
+
+func TestUpdateJobCheckStatus_RecordsEveryPoll(t *testing.T) {
+	jobURL := "https://jenkins/job/app/8/"
+	polled := time.Now().Add(-time.Minute)
+	store := newMemStore(config.Job{URL: jobURL, LastPollTime: polled})
+	logger := logging.TextLogger(io.Discard)
+
+	job, _ := updateJobCheckStatus(jobURL, false, time.Now(), 8, logger, store)
+	assert.True(t, job.LastPollTime.After(polled), "a routine successful poll is recorded")
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Jobs[jobURL].LastPollTime.Equal(job.LastPollTime))
+
+	updateJobCheckStatus(jobURL, true, time.Now(), 8, logger, store)
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.Jobs[jobURL].CheckFailureCount)
+	assert.Equal(t, 8, cfg.Jobs[jobURL].BuildNumber)
+}
+
+func TestMetricsAddrFromEnv(t *testing.T) {
+	t.Setenv("JW_METRICS_PORT", "")
+	addr, err := metricsAddrFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, addr)
+
+	t.Setenv("JW_METRICS_PORT", "9090")
+	addr, err = metricsAddrFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9090", addr)
+
+	t.Setenv("JW_METRICS_HOST", "::")
+	addr, err = metricsAddrFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "[::]:9090", addr)
+
+	t.Setenv("JW_METRICS_PORT", "http")
+	_, err = metricsAddrFromEnv()
+	assert.ErrorContains(t, err, "JW_METRICS_PORT")
+}
+
+func TestRunDaemonLoop_MetricsPortTaken(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close()
+
+	deps := DaemonDeps{
+		Store:          newMemStore(),
+		Notifier:       &recordingNotifier{},
+		SigChan:        make(chan os.Signal),
+		Stop:           make(chan struct{}),
+		TickerInterval: time.Millisecond,
+		MetricsAddr:    taken.Addr().String(),
+	}
+	var logs bytes.Buffer
+	require.NoError(t, runDaemonLoop(deps, logging.TextLogger(&logs)), "a taken metrics port must not stop the daemon")
+	assert.Contains(t, logs.String(), "Cannot serve metrics")
+	assert.Contains(t, logs.String(), "No more jobs to monitor")
+}
+
+func TestDaemonArgs_PassesLogFormat(t *testing.T) {
+	t.Setenv("JW_LOG_FORMAT", "")
+	assert.Equal(t, []string{"_start_jw_daemon"}, daemonArgs())
+
+	t.Setenv("JW_LOG_FORMAT", "json")
+	assert.Equal(t, []string{"_start_jw_daemon", "--log-format", "json"}, daemonArgs())
+}
+
+func TestReloadConfigAndJobs_RebuildsNotifier(t *testing.T) {
+	store := newMemStore()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.Notifications.SlackWebhookURL = "https://hooks.slack.test/new"
+		return nil
+	}))
+	before, after := &recordingNotifier{}, &recordingNotifier{}
+	notifier := &swappableNotifier{notifier: before}
+	var webhook string
+	deps := DaemonDeps{
+		Store:    store,
+		Notifier: notifier,
+		RebuildNotifier: func(cfg *config.Config) {
+			webhook = cfg.Notifications.SlackWebhookURL
+			notifier.set(after)
+		},
+	}
+
+	reloadConfigAndJobs(deps, logging.TextLogger(io.Discard), make(map[string]activeJob), make(chan monitor.JobEvent, 1))
+	assert.Equal(t, "https://hooks.slack.test/new", webhook)
+	require.NoError(t, deps.Notifier.Send(notify.Notification{Title: "t"}))
+	assert.Empty(t, before.getCalls())
+	assert.Len(t, after.getCalls(), 1)
+}
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/pidfile"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// DoctorResult is the outcome of a single jw doctor check.
+type DoctorResult struct {
+	Name   string
+	Pass   bool
+	Detail string
+	Fix    string // suggestion printed when the check fails
+}
+
+type doctorCheck func(fix bool) DoctorResult
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that jw is set up correctly",
+	Long:  `Run a series of checks on credentials, config, daemon and notification setup. With --fix, repairable problems are fixed automatically.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := []doctorCheck{
+			checkCredentialsFile,
+			checkConfigDirWritable,
+			checkJenkinsReachable,
+			checkDaemonRunning,
+			checkPIDFile,
+			checkLogFileWritable,
+			checkNotifier,
+			checkExtensionManifest,
+		}
+
+		failed := 0
+		for _, check := range checks {
+			result := check(doctorFix)
+			printDoctorResult(result)
+			if !result.Pass {
+				failed++
+			}
+		}
+
+		fmt.Println()
+		if failed > 0 {
+			fmt.Println(ui.RedText(fmt.Sprintf("%d check(s) failed.", failed)))
+			os.Exit(1)
+		}
+		fmt.Println(ui.GreenText("All checks passed."))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Automatically repair fixable issues")
+}
+
+func printDoctorResult(r DoctorResult) {
+	if r.Pass {
+		fmt.Println(ui.GreenText("✓ "+r.Name) + ": " + r.Detail)
+		return
+	}
+	fmt.Println(ui.RedText("✗ "+r.Name) + ": " + r.Detail)
+	if r.Fix != "" {
+		fmt.Println(ui.MutedText("    fix: " + r.Fix))
+	}
+}
+
+func checkCredentialsFile(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Credentials file"}
+
+	path, err := config.GetCredentialsPath()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if _, err := config.GetCredentials(); err == nil {
+				result.Pass = true
+				result.Detail = "not present, using environment variables or the keychain"
+				return result
+			}
+			result.Detail = "not found at " + path
+			result.Fix = "run 'jw auth'"
+			return result
+		}
+		result.Detail = err.Error()
+		return result
+	}
+
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		if fix {
+			if err := os.Chmod(path, 0o600); err != nil {
+				result.Detail = fmt.Sprintf("mode %04o, chmod failed: %v", mode, err)
+				return result
+			}
+			result.Pass = true
+			result.Detail = fmt.Sprintf("mode fixed from %04o to 0600", mode)
+			return result
+		}
+		result.Detail = fmt.Sprintf("mode is %04o, expected 0600", mode)
+		result.Fix = "chmod 600 " + path + " (or run 'jw doctor --fix')"
+		return result
+	}
+
+	result.Pass = true
+	result.Detail = path
+	return result
+}
+
+func checkConfigDirWritable(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Config directory"}
+
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if !fix {
+			result.Detail = dir + " does not exist"
+			result.Fix = "mkdir -p " + dir + " (or run 'jw doctor --fix')"
+			return result
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			result.Detail = fmt.Sprintf("creating %s: %v", dir, err)
+			return result
+		}
+	}
+
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		result.Detail = fmt.Sprintf("%s is not writable: %v", dir, err)
+		result.Fix = "check ownership and permissions of " + dir
+		return result
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	result.Pass = true
+	result.Detail = dir
+	return result
+}
+
+func checkJenkinsReachable(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Jenkins server"}
+
+	creds, err := config.LoadCredentials()
+	if err != nil || creds.BaseURL == "" {
+		result.Pass = true
+		result.Detail = "skipped, no Jenkins URL stored"
+		return result
+	}
+
+	resp, err := jenkins.NewClient().Get(creds.BaseURL)
+	if err != nil {
+		result.Detail = fmt.Sprintf("%s is not reachable: %v", creds.BaseURL, err)
+		result.Fix = "check network, proxy (JW_PROXY) and TLS (JW_CA_BUNDLE) settings"
+		return result
+	}
+	resp.Body.Close()
+
+	result.Pass = true
+	result.Detail = fmt.Sprintf("%s reachable (%s)", creds.BaseURL, resp.Status)
+	return result
+}
+
+func checkDaemonRunning(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Daemon", Pass: true}
+	if pid, running := pidfile.IsDaemonRunning(); running {
+		result.Detail = fmt.Sprintf("running (PID: %d)", pid)
+	} else {
+		result.Detail = "not running (starts automatically on 'jw add')"
+	}
+	return result
+}
+
+func checkPIDFile(fix bool) DoctorResult {
+	result := DoctorResult{Name: "PID file"}
+
+	path, err := pidfile.GetPidFilePath()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if pid, found := pidfile.FindDaemonProcess(); found {
+			result.Detail = fmt.Sprintf("missing, but daemon process %d is running", pid)
+			result.Fix = "wait a few seconds for the daemon to restore it, or run 'kill " + strconv.Itoa(pid) + "'"
+			return result
+		}
+		result.Pass = true
+		result.Detail = "absent, no daemon running"
+		return result
+	} else if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err == nil && syscall.Kill(pid, 0) == nil {
+		result.Pass = true
+		result.Detail = fmt.Sprintf("matches live process %d", pid)
+		return result
+	}
+
+	if fix {
+		if err := os.Remove(path); err != nil {
+			result.Detail = fmt.Sprintf("stale, removing failed: %v", err)
+			return result
+		}
+		result.Pass = true
+		result.Detail = "stale PID file removed"
+		return result
+	}
+	result.Detail = fmt.Sprintf("points at PID %q which is not running", strings.TrimSpace(string(data)))
+	result.Fix = "rm " + path + " (or run 'jw doctor --fix')"
+	return result
+}
+
+func checkLogFileWritable(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Log file"}
+
+	path, err := logging.GetLogFilePath()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+	if _, err := os.Stat(filepath.Dir(path)); os.IsNotExist(err) {
+		result.Pass = true
+		result.Detail = "not created yet"
+		return result
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		result.Detail = fmt.Sprintf("%s is not writable: %v", path, err)
+		result.Fix = "check ownership and permissions of " + path
+		return result
+	}
+	f.Close()
+
+	result.Pass = true
+	result.Detail = path
+	return result
+}
+
+func checkNotifier(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Notifier"}
+
+	candidates := []string{"terminal-notifier", "osascript"}
+	install := "brew install terminal-notifier"
+	if runtime.GOOS == "linux" {
+		candidates = []string{"notify-send", "zenity"}
+		install = "install libnotify (notify-send) from your package manager"
+	}
+
+	for _, name := range candidates {
+		if path, err := exec.LookPath(name); err == nil {
+			result.Pass = true
+			result.Detail = "using " + path
+			return result
+		}
+	}
+
+	result.Detail = "none of " + strings.Join(candidates, ", ") + " found in PATH"
+	result.Fix = install
+	return result
+}
+
+func checkExtensionManifest(fix bool) DoctorResult {
+	result := DoctorResult{Name: "Chrome extension host"}
+
+	if runtime.GOOS != "darwin" {
+		result.Pass = true
+		result.Detail = "skipped, macOS only"
+		return result
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		result.Detail = err.Error()
+		return result
+	}
+
+	path := nativeHostManifestPath(home)
+	if _, err := os.Stat(path); err != nil {
+		result.Pass = true
+		result.Detail = "not installed (optional)"
+		return result
+	}
+
+	result.Pass = true
+	result.Detail = path
+	return result
+}
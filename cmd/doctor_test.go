@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCredentialsFile(t *testing.T, home string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(home, ".jw", ".credentials")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"username":"u","token":"t"}`), mode))
+	require.NoError(t, os.Chmod(path, mode))
+	return path
+}
+
+func TestCheckCredentialsFile_Permissions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := writeCredentialsFile(t, home, 0o644)
+
+	result := checkCredentialsFile(false)
+	assert.False(t, result.Pass)
+	assert.Contains(t, result.Detail, "0644")
+	assert.NotEmpty(t, result.Fix)
+
+	result = checkCredentialsFile(true)
+	assert.True(t, result.Pass)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	result = checkCredentialsFile(false)
+	assert.True(t, result.Pass)
+}
+
+func TestCheckCredentialsFile_Missing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("JENKINS_USER", "")
+	t.Setenv("JENKINS_API_TOKEN", "")
+	t.Setenv("JENKINS_TOKEN", "")
+
+	result := checkCredentialsFile(false)
+	assert.False(t, result.Pass)
+	assert.Equal(t, "run 'jw auth'", result.Fix)
+
+	t.Setenv("JENKINS_USER", "alice")
+	result = checkCredentialsFile(false)
+	assert.False(t, result.Pass, "a user without a token is not usable")
+
+	t.Setenv("JENKINS_API_TOKEN", "s3cret")
+	result = checkCredentialsFile(false)
+	assert.True(t, result.Pass)
+}
+
+func TestCheckPIDFile_StaleIsFixed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".jw", "daemon.pid")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	// PIDs are capped well below this on Linux and macOS.
+	require.NoError(t, os.WriteFile(path, []byte(strconv.Itoa(1<<30)), 0o644))
+
+	result := checkPIDFile(false)
+	assert.False(t, result.Pass)
+
+	result = checkPIDFile(true)
+	assert.True(t, result.Pass)
+	assert.NoFileExists(t, path)
+}
+
+func TestCheckConfigDirWritable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	result := checkConfigDirWritable(false)
+	assert.False(t, result.Pass, "missing directory should fail without --fix")
+
+	result = checkConfigDirWritable(true)
+	assert.True(t, result.Pass)
+	dir, err := config.GetConfigDir()
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+}
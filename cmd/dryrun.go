@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"jenkins-monitor/pkg/config"
+)
+
+// dryRunReport describes what jw add or jw remove would change.
+type dryRunReport struct {
+	DryRun    bool     `json:"dry_run"`
+	Add       []string `json:"add,omitempty"`
+	Remove    []string `json:"remove,omitempty"`
+	Unchanged []string `json:"unchanged,omitempty"`
+	// Jobs are the monitored job URLs after the change.
+	Jobs []string `json:"jobs"`
+}
+
+func newDryRunReport(cfg *config.Config) dryRunReport {
+	jobs := make([]string, 0, len(cfg.Jobs))
+	for jobURL := range cfg.Jobs {
+		jobs = append(jobs, jobURL)
+	}
+	slices.Sort(jobs)
+	return dryRunReport{DryRun: true, Jobs: jobs}
+}
+
+func writeDryRunReport(w io.Writer, report dryRunReport, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	for _, jobURL := range report.Add {
+		fmt.Fprintln(w, "Would add: "+jobURL)
+	}
+	for _, jobURL := range report.Remove {
+		fmt.Fprintln(w, "Would remove: "+jobURL)
+	}
+	for _, jobURL := range report.Unchanged {
+		fmt.Fprintln(w, "Unchanged: "+jobURL)
+	}
+	fmt.Fprintf(w, "Resulting jobs (%d):\n", len(report.Jobs))
+	for _, jobURL := range report.Jobs {
+		fmt.Fprintln(w, "  - "+jobURL)
+	}
+	return nil
+}
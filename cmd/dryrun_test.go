@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun_LeavesConfigUntouched(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+	_, err := addJobs(&bytes.Buffer{}, store, []string{"https://j/job/a/1", "https://j/job/b/1"}, jobOptions{})
+	require.NoError(t, err)
+
+	path, err := config.GetConfigPath()
+	require.NoError(t, err)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	addReport, err := previewAdd(store, []string{"https://j/job/a/1", "https://j/job/c/1"}, jobOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://j/job/c/1"}, addReport.Add)
+	assert.Equal(t, []string{"https://j/job/a/1"}, addReport.Unchanged)
+	assert.Equal(t, []string{"https://j/job/a/1", "https://j/job/b/1", "https://j/job/c/1"}, addReport.Jobs)
+
+	removeReport, err := previewRemove(store, []string{"https://j/job/b/1", "https://j/job/z/1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://j/job/b/1"}, removeReport.Remove)
+	assert.Equal(t, []string{"https://j/job/z/1"}, removeReport.Unchanged)
+	assert.Equal(t, []string{"https://j/job/a/1"}, removeReport.Jobs)
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	infoAfter, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, info.ModTime(), infoAfter.ModTime())
+}
+
+func TestWriteDryRunReport(t *testing.T) {
+	report := dryRunReport{DryRun: true, Add: []string{"https://j/job/c/1"}, Unchanged: []string{"https://j/job/a/1"}, Jobs: []string{"https://j/job/a/1", "https://j/job/c/1"}}
+
+	var text bytes.Buffer
+	require.NoError(t, writeDryRunReport(&text, report, false))
+	assert.Equal(t, "Would add: https://j/job/c/1\nUnchanged: https://j/job/a/1\nResulting jobs (2):\n  - https://j/job/a/1\n  - https://j/job/c/1\n", text.String())
+
+	var js bytes.Buffer
+	require.NoError(t, writeDryRunReport(&js, report, true))
+	var decoded dryRunReport
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// redacted replaces secrets in exported configs.
+const redacted = "REDACTED"
+
+var exportOut string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the config as JSON, with secrets redacted",
+	Long: `Write the full config (monitored jobs, history and settings) as JSON to stdout
+or --out. Notification tokens, webhook URLs and webhook headers are redacted.
+Load the result on another machine with "jw import".`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		w := io.Writer(os.Stdout)
+		if exportOut != "" {
+			f, err := os.OpenFile(exportOut, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if err := exportConfig(w, config.NewDiskStore()); err != nil {
+			fmt.Fprintln(os.Stderr, ui.RedText(fmt.Sprintf("Error exporting config: %v", err)))
+			os.Exit(1)
+		}
+		if exportOut != "" {
+			fmt.Println(ui.GreenText("Exported config to " + exportOut))
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Write to this file instead of stdout")
+}
+
+func exportConfig(w io.Writer, store config.ConfigStore) error {
+	cfg, err := store.Load()
+	if err != nil {
+		return err
+	}
+	redactConfig(cfg)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
+
+// redactConfig blanks out every secret in cfg, leaving a marker where one
+// was set.
+func redactConfig(cfg *config.Config) {
+	for _, secret := range []*string{
+		&cfg.Notifications.SlackWebhookURL,
+		&cfg.Notifications.DiscordWebhookURL,
+		&cfg.Notifications.TelegramBotToken,
+		&cfg.Webhook.URL,
+	} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	for name := range cfg.Webhook.Headers {
+		cfg.Webhook.Headers[name] = redacted
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	start := time.Date(2026, 2, 3, 4, 5, 6, 0, time.UTC)
+	source := config.NewDiskStore()
+	require.NoError(t, source.Update(func(cfg *config.Config) error {
+		cfg.Jobs["https://jenkins/job/a/1"] = config.Job{URL: "https://jenkins/job/a/1", StartTime: start, PollInterval: time.Minute}
+		cfg.Jobs["https://jenkins/job/b/2"] = config.Job{URL: "https://jenkins/job/b/2", StartTime: start, Profile: "staging", Paused: true}
+		cfg.Notifications.SlackWebhookURL = "https://hooks.slack.com/services/secret"
+		cfg.Notifications.TelegramBotToken = "123:secret"
+		cfg.Webhook = config.WebhookConfig{URL: "https://example.com/hook?token=secret", Headers: map[string]string{"Authorization": "Bearer secret"}}
+		return nil
+	}))
+
+	var exported bytes.Buffer
+	require.NoError(t, exportConfig(&exported, source))
+	assert.NotContains(t, exported.String(), "secret")
+	assert.Contains(t, exported.String(), redacted)
+
+	want, err := source.Load()
+	require.NoError(t, err)
+
+	t.Setenv("HOME", t.TempDir())
+	dest := config.NewDiskStore()
+	var out bytes.Buffer
+	changed, err := importJobs(&out, dest, strings.NewReader(exported.String()), false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, out.String(), "Imported 2 job(s).")
+
+	got, err := dest.Load()
+	require.NoError(t, err)
+	assert.Equal(t, want.Jobs, got.Jobs)
+	assert.Empty(t, got.Notifications.SlackWebhookURL, "settings are not imported")
+}
+
+func TestImportJobs_MergeAndReplace(t *testing.T) {
+	export := `{"jobs": {
+		"https://jenkins/job/a/1": {"url": "https://jenkins/job/a/1"},
+		"https://jenkins/job/b/2": {"url": "https://jenkins/job/b/2"}
+	}}`
+
+	store := newMemStore(config.Job{URL: "https://jenkins/job/a/1", Profile: "local"}, config.Job{URL: "https://jenkins/job/c/3"})
+	var out bytes.Buffer
+	changed, err := importJobs(&out, store, strings.NewReader(export), false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, out.String(), "Skipped 1 job(s) already being monitored.")
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 3)
+	assert.Equal(t, "local", cfg.Jobs["https://jenkins/job/a/1"].Profile, "existing jobs are kept on merge")
+
+	changed, err = importJobs(&bytes.Buffer{}, store, strings.NewReader(export), true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 2)
+	assert.NotContains(t, cfg.Jobs, "https://jenkins/job/c/3")
+	assert.Empty(t, cfg.Jobs["https://jenkins/job/a/1"].Profile)
+
+	_, err = importJobs(&bytes.Buffer{}, store, strings.NewReader("not json"), false)
+	assert.ErrorContains(t, err, "invalid export file")
+}
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"jenkins-monitor/pkg/paths"
 	"jenkins-monitor/pkg/ui"
 
 	"github.com/spf13/cobra"
@@ -16,98 +19,309 @@ import (
 const (
 	nativeHostName = "com.jw.monitor"
 	extensionID    = "njbfammdojdlkbhihjiiedogedglkonc"
+	// firefoxExtensionID is the add-on ID set under browser_specific_settings
+	// in extension/manifest.json.
+	firefoxExtensionID = "jw@baggiiiie.github.io"
 )
 
+var extensionBrowser string
+
 var extensionCmd = &cobra.Command{
 	Use:   "extension",
-	Short: "Manage the jw Chrome extension",
+	Short: "Manage the jw browser extension",
 }
 
 var extensionInstallCmd = &cobra.Command{
 	Use:   "install",
-	Short: "Install the Chrome native messaging host",
+	Short: "Install the native messaging host",
+	Args:  cobra.NoArgs,
 	Run:   runExtensionInstall,
 }
 
+var extensionUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the native messaging host",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		browsers, home := extensionTargets()
+		if err := uninstallNativeHost(os.Stdout, home, browsers); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+var extensionStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the native messaging host is installed",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		browsers, home := extensionTargets()
+		if err := nativeHostStatus(os.Stdout, home, browsers); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
 func init() {
-	extensionCmd.AddCommand(extensionInstallCmd)
+	extensionCmd.AddCommand(extensionInstallCmd, extensionUninstallCmd, extensionStatusCmd)
+	extensionCmd.PersistentFlags().StringVar(&extensionBrowser, "browser", "chrome", "Browser to target: chrome, chromium, firefox, or all")
 	RootCmd.AddCommand(extensionCmd)
 }
 
+// nativeHostManifest is the native messaging host manifest. Chromium-based
+// browsers list allowed extensions as origins, Firefox by add-on ID.
 type nativeHostManifest struct {
-	Name           string   `json:"name"`
-	Description    string   `json:"description"`
-	Path           string   `json:"path"`
-	Type           string   `json:"type"`
-	AllowedOrigins []string `json:"allowed_origins"`
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	Path              string   `json:"path"`
+	Type              string   `json:"type"`
+	AllowedOrigins    []string `json:"allowed_origins,omitempty"`
+	AllowedExtensions []string `json:"allowed_extensions,omitempty"`
 }
 
-func runExtensionInstall(cmd *cobra.Command, args []string) {
-	// 1. Resolve absolute path to jw binary
-	exe, err := os.Executable()
-	if err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error finding executable: %v", err)))
-		os.Exit(1)
+// nativeHostBrowser is a browser jw can register its native messaging host with.
+type nativeHostBrowser struct {
+	Name string
+	// ManifestDir is where the browser on macOS looks for host manifests,
+	// relative to the home directory.
+	ManifestDir []string
+	Firefox     bool
+}
+
+var nativeHostBrowsers = []nativeHostBrowser{
+	{Name: "chrome", ManifestDir: []string{"Library", "Application Support", "Google", "Chrome", "NativeMessagingHosts"}},
+	{Name: "chromium", ManifestDir: []string{"Library", "Application Support", "Chromium", "NativeMessagingHosts"}},
+	{Name: "firefox", ManifestDir: []string{"Library", "Application Support", "Mozilla", "NativeMessagingHosts"}, Firefox: true},
+}
+
+// lookupBrowsers resolves a --browser value to the browsers it selects.
+func lookupBrowsers(name string) ([]nativeHostBrowser, error) {
+	if name == "all" {
+		return nativeHostBrowsers, nil
 	}
-	exe, err = filepath.EvalSymlinks(exe)
+	for _, b := range nativeHostBrowsers {
+		if b.Name == name {
+			return []nativeHostBrowser{b}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown browser %q (want chrome, chromium, firefox, or all)", name)
+}
+
+// manifestPath returns where the browser looks for the jw host manifest.
+func (b nativeHostBrowser) manifestPath(home string) string {
+	return filepath.Join(append(append([]string{home}, b.ManifestDir...), nativeHostName+".json")...)
+}
+
+// manifest returns the host manifest pointing at wrapperPath in the schema
+// the browser expects.
+func (b nativeHostBrowser) manifest(wrapperPath string) nativeHostManifest {
+	m := nativeHostManifest{
+		Name:        nativeHostName,
+		Description: "Jenkins job monitor - jw",
+		Path:        wrapperPath,
+		Type:        "stdio",
+	}
+	if b.Firefox {
+		m.AllowedExtensions = []string{firefoxExtensionID}
+	} else {
+		m.AllowedOrigins = []string{fmt.Sprintf("chrome-extension://%s/", extensionID)}
+	}
+	return m
+}
+
+// extensionTargets resolves --browser and the home directory, exiting on error.
+func extensionTargets() ([]nativeHostBrowser, string) {
+	browsers, err := lookupBrowsers(extensionBrowser)
 	if err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error resolving symlinks: %v", err)))
+		fmt.Println(ui.RedText("Error: " + err.Error()))
 		os.Exit(1)
 	}
-
 	home, err := os.UserHomeDir()
 	if err != nil {
 		fmt.Println(ui.RedText(fmt.Sprintf("Error finding home directory: %v", err)))
 		os.Exit(1)
 	}
+	return browsers, home
+}
 
-	// 2. Write wrapper script
-	jwDir := filepath.Join(home, ".jw")
-	if err := os.MkdirAll(jwDir, 0o755); err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error creating directory: %v", err)))
-		os.Exit(1)
-	}
+// nativeHostManifestPath returns where Chrome on macOS looks for the jw native messaging host manifest.
+func nativeHostManifestPath(home string) string {
+	return nativeHostBrowsers[0].manifestPath(home)
+}
 
-	wrapperPath := filepath.Join(jwDir, "native-messaging-host.sh")
-	wrapperContent := fmt.Sprintf("#!/bin/sh\nexec %s _native_messaging\n", exe)
+// nativeHostWrapperPath returns where the install command writes the wrapper
+// script the manifest points at.
+func nativeHostWrapperPath(home string) string {
+	return filepath.Join(paths.ConfigDirIn(home), "native-messaging-host.sh")
+}
+
+// installNativeHost writes the wrapper script running exe and a host manifest
+// for each browser, reporting the written paths to w.
+func installNativeHost(w io.Writer, home, exe string, browsers []nativeHostBrowser) error {
+	wrapperPath := nativeHostWrapperPath(home)
+	if err := os.MkdirAll(filepath.Dir(wrapperPath), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	wrapperContent := "#!/bin/sh\n"
+	if dir := paths.OverrideDir(); dir != "" {
+		wrapperContent += fmt.Sprintf("export JW_CONFIG_DIR=%s\n", shellQuote(dir))
+	}
+	wrapperContent += fmt.Sprintf("exec %s _native_messaging\n", exe)
 	if err := os.WriteFile(wrapperPath, []byte(wrapperContent), 0o755); err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error writing wrapper script: %v", err)))
-		os.Exit(1)
+		return fmt.Errorf("writing wrapper script: %w", err)
 	}
+	fmt.Fprintf(w, "  Wrapper script: %s\n", wrapperPath)
 
-	// 3. Write native messaging host manifest
-	manifestDir := filepath.Join(home, "Library", "Application Support", "Google", "Chrome", "NativeMessagingHosts")
-	if err := os.MkdirAll(manifestDir, 0o755); err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error creating manifest directory: %v", err)))
-		os.Exit(1)
+	for _, b := range browsers {
+		manifestPath := b.manifestPath(home)
+		if err := os.MkdirAll(filepath.Dir(manifestPath), 0o755); err != nil {
+			return fmt.Errorf("creating manifest directory: %w", err)
+		}
+		data, err := json.MarshalIndent(b.manifest(wrapperPath), "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling manifest: %w", err)
+		}
+		if err := os.WriteFile(manifestPath, data, 0o644); err != nil {
+			return fmt.Errorf("writing manifest: %w", err)
+		}
+		fmt.Fprintf(w, "  Host manifest (%s): %s\n", b.Name, manifestPath)
 	}
+	return nil
+}
 
-	manifest := nativeHostManifest{
-		Name:           nativeHostName,
-		Description:    "Jenkins job monitor - jw",
-		Path:           wrapperPath,
-		Type:           "stdio",
-		AllowedOrigins: []string{fmt.Sprintf("chrome-extension://%s/", extensionID)},
+// uninstallNativeHost removes the manifests of browsers, and the wrapper
+// script once no browser's manifest is left, reporting each deleted file to w.
+func uninstallNativeHost(w io.Writer, home string, browsers []nativeHostBrowser) error {
+	removed := 0
+	remove := func(path string) error {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "Removed "+path)
+		removed++
+		return nil
 	}
 
-	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	for _, b := range browsers {
+		if err := remove(b.manifestPath(home)); err != nil {
+			return err
+		}
+	}
+	inUse := false
+	for _, b := range nativeHostBrowsers {
+		if _, err := os.Stat(b.manifestPath(home)); err == nil {
+			inUse = true
+		}
+	}
+	if !inUse {
+		if err := remove(nativeHostWrapperPath(home)); err != nil {
+			return err
+		}
+	}
+
+	if removed == 0 {
+		return errors.New("native messaging host is not installed")
+	}
+	fmt.Fprintln(w, ui.GreenText("Native messaging host uninstalled."))
+	return nil
+}
+
+// installExtensionHost registers the running jw binary as the native
+// messaging host for browsers.
+func installExtensionHost(w io.Writer, home string, browsers []nativeHostBrowser) error {
+	exe, err := os.Executable()
 	if err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error marshaling manifest: %v", err)))
-		os.Exit(1)
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return fmt.Errorf("resolving symlinks: %w", err)
 	}
 
-	manifestPath := filepath.Join(manifestDir, nativeHostName+".json")
-	if err := os.WriteFile(manifestPath, manifestData, 0o644); err != nil {
-		fmt.Println(ui.RedText(fmt.Sprintf("Error writing manifest: %v", err)))
-		os.Exit(1)
+	fmt.Fprintln(w, ui.GreenText("Installing native messaging host..."))
+	fmt.Fprintln(w)
+	if err := installNativeHost(w, home, exe, browsers); err != nil {
+		return err
 	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.GreenText("Native messaging host installed successfully!"))
+	return nil
+}
 
-	// 4. Print success
-	fmt.Println(ui.GreenText("Native messaging host installed successfully!"))
-	fmt.Println()
-	fmt.Printf("  Wrapper script: %s\n", wrapperPath)
-	fmt.Printf("  Host manifest:  %s\n", manifestPath)
+// nativeHostStatus prints, for each browser, the installed manifest's wrapper
+// path, whether that wrapper is executable and the allowed extension IDs. It
+// fails if the host is not installed or the wrapper cannot be run.
+func nativeHostStatus(w io.Writer, home string, browsers []nativeHostBrowser) error {
+	var failed []string
+	for i, b := range browsers {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := browserHostStatus(w, home, b); err != nil {
+			if len(browsers) == 1 {
+				return err
+			}
+			fmt.Fprintln(w, ui.RedText(fmt.Sprintf("%s: %v", b.Name, err)))
+			failed = append(failed, b.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("native messaging host not usable for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func browserHostStatus(w io.Writer, home string, b nativeHostBrowser) error {
+	manifestPath := b.manifestPath(home)
+	data, err := os.ReadFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("native messaging host is not installed (no manifest at %s)", manifestPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	var manifest nativeHostManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("invalid manifest %s: %w", manifestPath, err)
+	}
+
+	executable := false
+	if info, err := os.Stat(manifest.Path); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0 {
+		executable = true
+	}
+
+	ids := append([]string(nil), manifest.AllowedExtensions...)
+	for _, origin := range manifest.AllowedOrigins {
+		ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(origin, "chrome-extension://"), "/"))
+	}
+
+	fmt.Fprintf(w, "Browser:      %s\n", b.Name)
+	fmt.Fprintf(w, "Manifest:     %s\n", manifestPath)
+	fmt.Fprintf(w, "Wrapper:      %s\n", manifest.Path)
+	fmt.Fprintf(w, "Executable:   %t\n", executable)
+	fmt.Fprintf(w, "Extension ID: %s\n", strings.Join(ids, ", "))
+
+	if !executable {
+		return fmt.Errorf("wrapper %s is missing or not executable; run 'jw extension install'", manifest.Path)
+	}
+	return nil
+}
+
+func runExtensionInstall(cmd *cobra.Command, args []string) {
+	browsers, home := extensionTargets()
+	if err := installExtensionHost(os.Stdout, home, browsers); err != nil {
+		fmt.Println(ui.RedText("Error: " + err.Error()))
+		os.Exit(1)
+	}
 	fmt.Println()
+
 	// Determine extension path: prefer Homebrew share dir, fall back to relative
 	extensionPath := "extension/"
 	if out, err := exec.Command("brew", "--prefix").Output(); err == nil {
@@ -117,11 +331,28 @@ func runExtensionInstall(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	fmt.Println("To load the Chrome extension:")
-	fmt.Println("  1. Open chrome://extensions")
-	fmt.Println("  2. Enable 'Developer mode'")
-	fmt.Println("  3. Copy extension to your desired location:")
-	fmt.Printf("       `cp -r %s /path/to/extension`\n", extensionPath)
-	fmt.Println("  4. Click 'Load unpacked' and select extension")
-	fmt.Println("  5. Right-click on any Jenkins page and select 'Watch with jw'")
+	for _, b := range browsers {
+		if b.Firefox {
+			fmt.Println("To load the Firefox extension:")
+			fmt.Println("  1. Open about:debugging#/runtime/this-firefox")
+			fmt.Println("  2. Click 'Load Temporary Add-on...'")
+			fmt.Printf("  3. Select manifest.json in %s\n", extensionPath)
+			fmt.Println("  4. Right-click on any Jenkins page and select 'Watch with jw'")
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("To load the extension in %s:\n", b.Name)
+		fmt.Println("  1. Open chrome://extensions")
+		fmt.Println("  2. Enable 'Developer mode'")
+		fmt.Println("  3. Copy extension to your desired location:")
+		fmt.Printf("       `cp -r %s /path/to/extension`\n", extensionPath)
+		fmt.Println("  4. Click 'Load unpacked' and select extension")
+		fmt.Println("  5. Right-click on any Jenkins page and select 'Watch with jw'")
+		fmt.Println()
+	}
+}
+
+// shellQuote quotes s as a single sh word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedNativeHost writes a manifest and wrapper script as runExtensionInstall
+// would, under home.
+func seedNativeHost(t *testing.T, home string, wrapperMode os.FileMode) {
+	t.Helper()
+	wrapper := nativeHostWrapperPath(home)
+	require.NoError(t, os.MkdirAll(filepath.Dir(wrapper), 0o755))
+	require.NoError(t, os.WriteFile(wrapper, []byte("#!/bin/sh\n"), wrapperMode))
+
+	manifestPath := nativeHostManifestPath(home)
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifestPath), 0o755))
+	data, err := json.Marshal(nativeHostManifest{
+		Name:           nativeHostName,
+		Path:           wrapper,
+		Type:           "stdio",
+		AllowedOrigins: []string{"chrome-extension://" + extensionID + "/"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, data, 0o644))
+}
+
+func TestNativeHostStatus(t *testing.T) {
+	home := t.TempDir()
+	seedNativeHost(t, home, 0o755)
+
+	var out bytes.Buffer
+	require.NoError(t, nativeHostStatus(&out, home, nativeHostBrowsers[:1]))
+	assert.Contains(t, out.String(), nativeHostWrapperPath(home))
+	assert.Contains(t, out.String(), "Executable:   true")
+	assert.Contains(t, out.String(), "Extension ID: "+extensionID)
+}
+
+func TestNativeHostStatus_NotExecutable(t *testing.T) {
+	home := t.TempDir()
+	seedNativeHost(t, home, 0o644)
+
+	var out bytes.Buffer
+	err := nativeHostStatus(&out, home, nativeHostBrowsers[:1])
+	assert.ErrorContains(t, err, "not executable")
+	assert.Contains(t, out.String(), "Executable:   false")
+}
+
+func TestNativeHostStatus_NotInstalled(t *testing.T) {
+	err := nativeHostStatus(&bytes.Buffer{}, t.TempDir(), nativeHostBrowsers[:1])
+	assert.ErrorContains(t, err, "not installed")
+}
+
+func TestUninstallNativeHost(t *testing.T) {
+	home := t.TempDir()
+	seedNativeHost(t, home, 0o755)
+
+	var out bytes.Buffer
+	require.NoError(t, uninstallNativeHost(&out, home, nativeHostBrowsers[:1]))
+	assert.Contains(t, out.String(), "Removed "+nativeHostManifestPath(home))
+	assert.Contains(t, out.String(), "Removed "+nativeHostWrapperPath(home))
+	assert.NoFileExists(t, nativeHostManifestPath(home))
+	assert.NoFileExists(t, nativeHostWrapperPath(home))
+
+	assert.ErrorContains(t, uninstallNativeHost(&bytes.Buffer{}, home, nativeHostBrowsers[:1]), "not installed")
+}
+
+func TestInstallNativeHost_ManifestDirPerBrowser(t *testing.T) {
+	tests := []struct {
+		browser string
+		dirs    []string
+	}{
+		{"chrome", []string{"Google/Chrome"}},
+		{"chromium", []string{"Chromium"}},
+		{"firefox", []string{"Mozilla"}},
+		{"all", []string{"Google/Chrome", "Chromium", "Mozilla"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.browser, func(t *testing.T) {
+			home := t.TempDir()
+			browsers, err := lookupBrowsers(tt.browser)
+			require.NoError(t, err)
+			require.NoError(t, installNativeHost(&bytes.Buffer{}, home, "/usr/local/bin/jw", browsers))
+
+			for _, dir := range tt.dirs {
+				path := filepath.Join(home, "Library", "Application Support", dir, "NativeMessagingHosts", nativeHostName+".json")
+				assert.FileExists(t, path)
+			}
+			wrapper, err := os.ReadFile(nativeHostWrapperPath(home))
+			require.NoError(t, err)
+			assert.Contains(t, string(wrapper), "exec /usr/local/bin/jw _native_messaging")
+			assert.NotContains(t, string(wrapper), "JW_CONFIG_DIR")
+		})
+	}
+
+	_, err := lookupBrowsers("safari")
+	assert.ErrorContains(t, err, "unknown browser")
+}
+
+func TestInstallNativeHost_PassesConfigDir(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "it's jw")
+	t.Setenv("JW_CONFIG_DIR", dir)
+	require.NoError(t, installNativeHost(&bytes.Buffer{}, home, "/bin/jw", nativeHostBrowsers[:1]))
+
+	wrapper, err := os.ReadFile(nativeHostWrapperPath(home))
+	require.NoError(t, err)
+	out, err := exec.Command("sh", "-c", `eval "$(grep '^export' "$0")" && printf %s "$JW_CONFIG_DIR"`, nativeHostWrapperPath(home)).Output()
+	require.NoError(t, err, string(wrapper))
+	assert.Equal(t, dir, string(out))
+}
+
+func TestNativeHostManifestSchema(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, installNativeHost(&bytes.Buffer{}, home, "/bin/jw", nativeHostBrowsers))
+
+	read := func(b nativeHostBrowser) map[string]any {
+		data, err := os.ReadFile(b.manifestPath(home))
+		require.NoError(t, err)
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(data, &m))
+		return m
+	}
+
+	chrome := read(nativeHostBrowsers[0])
+	assert.Equal(t, []any{"chrome-extension://" + extensionID + "/"}, chrome["allowed_origins"])
+	assert.NotContains(t, chrome, "allowed_extensions")
+
+	firefox := read(nativeHostBrowsers[2])
+	assert.Equal(t, []any{firefoxExtensionID}, firefox["allowed_extensions"])
+	assert.NotContains(t, firefox, "allowed_origins")
+	assert.Equal(t, nativeHostWrapperPath(home), firefox["path"])
+}
+
+func TestUninstallNativeHost_KeepsWrapperForOtherBrowsers(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, installNativeHost(&bytes.Buffer{}, home, "/bin/jw", nativeHostBrowsers))
+
+	firefox, err := lookupBrowsers("firefox")
+	require.NoError(t, err)
+	require.NoError(t, uninstallNativeHost(&bytes.Buffer{}, home, firefox))
+	assert.NoFileExists(t, firefox[0].manifestPath(home))
+	assert.FileExists(t, nativeHostWrapperPath(home), "chrome still uses the wrapper")
+
+	var out bytes.Buffer
+	require.NoError(t, nativeHostStatus(&out, home, nativeHostBrowsers[:2]))
+	assert.Error(t, nativeHostStatus(&bytes.Buffer{}, home, nativeHostBrowsers))
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"jenkins-monitor/pkg/eventlog"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/output"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit  int
+	historySince  time.Duration
+	historyResult string
+	historyJSON   bool
+	historyEvents bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [job_url]",
+	Short: "List completed builds, newest first",
+	Long: `List the completed builds the daemon recorded in its event log, newest first.
+Builds of the same job are grouped under the job URL; pass a job or build URL to
+show just that job.
+
+With --events, list the job events the daemon recorded instead, such as
+finished builds, failed checks and jobs removed for errors.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeURLHints,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := historyOptions{limit: historyLimit, since: historySince, result: historyResult}
+		if len(args) == 1 {
+			opts.jobURL = args[0]
+		}
+		log, err := eventlog.Default()
+		if err == nil {
+			if historyEvents {
+				err = runEventHistory(os.Stdout, log, opts, time.Now(), outputFormat(cmd, historyJSON))
+			} else {
+				err = runHistory(os.Stdout, log, opts, time.Now(), outputFormat(cmd, historyJSON))
+			}
+		}
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "l", 20, "Show at most this many builds (0 for all)")
+	historyCmd.Flags().DurationVar(&historySince, "since", 0, "Only show builds that finished within this long (e.g. 24h)")
+	historyCmd.Flags().StringVar(&historyResult, "result", "", "Only show builds with this result (e.g. SUCCESS or FAILURE)")
+	historyCmd.Flags().BoolVarP(&historyJSON, "json", "j", false, "Print the history as JSON")
+	historyCmd.Flags().BoolVar(&historyEvents, "events", false, "List the job events the daemon recorded")
+}
+
+// historyOptions selects which recorded builds jw history lists.
+type historyOptions struct {
+	jobURL string
+	limit  int
+	since  time.Duration
+	result string
+}
+
+// historyEntry is one completed build in jw history.
+type historyEntry struct {
+	Job             string    `json:"job"`
+	Result          string    `json:"result"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// runHistory prints the finished builds in log selected by opts in format, a
+// table by default.
+func runHistory(w io.Writer, log *eventlog.Log, opts historyOptions, now time.Time, format string) error {
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if opts.since < 0 {
+		return fmt.Errorf("--since must not be negative")
+	}
+
+	events, err := log.ReadAll(historyFilter(opts, now))
+	if err != nil {
+		return fmt.Errorf("reading event log: %w", err)
+	}
+
+	var entries []historyEntry
+	for _, e := range slices.Backward(events) {
+		if e.Kind != monitor.EventFinished {
+			continue
+		}
+		if opts.limit > 0 && len(entries) == opts.limit {
+			break
+		}
+		entries = append(entries, historyEntry{
+			Job:             jenkins.JobURLFromBuildURL(e.JobURL),
+			Result:          e.Result,
+			FinishedAt:      e.Time,
+			DurationSeconds: e.Duration.Seconds(),
+		})
+	}
+	if opts.jobURL != "" && len(entries) == 0 && opts.since == 0 && opts.result == "" {
+		return fmt.Errorf("no completed builds recorded for %s", jenkins.JobURLFromBuildURL(opts.jobURL))
+	}
+
+	if format == "" {
+		format = output.FormatTable
+	}
+	if format == output.FormatTable && len(entries) == 0 {
+		fmt.Fprintln(w, "No completed builds match.")
+		return nil
+	}
+	renderer, err := output.New(format, w)
+	if err != nil {
+		return err
+	}
+
+	records := output.Records{Columns: []string{"Job", "Result", "Finished", "Duration"}, Data: entries}
+	for _, e := range entries {
+		duration := time.Duration(e.DurationSeconds * float64(time.Second)).Round(time.Second)
+		records.Rows = append(records.Rows, []string{jobDisplayName(e.Job), e.Result, e.FinishedAt.Local().Format("2006-01-02 15:04"), duration.String()})
+	}
+	return output.Render(records, renderer)
+}
+
+// historyFilter selects the logged events matching opts.
+func historyFilter(opts historyOptions, now time.Time) eventlog.EventFilter {
+	filter := eventlog.EventFilter{JobURL: opts.jobURL, Result: opts.result}
+	if opts.since > 0 {
+		filter.Since = now.Add(-opts.since)
+	}
+	return filter
+}
+
+// eventEntry is one recorded job event in jw history --events.
+type eventEntry struct {
+	Time        time.Time `json:"time"`
+	Job         string    `json:"job"`
+	Event       string    `json:"event"`
+	Result      string    `json:"result,omitempty"`
+	BuildNumber int       `json:"build_number,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// runEventHistory prints the events in log selected by opts, newest first,
+// in format, a table by default.
+func runEventHistory(w io.Writer, log *eventlog.Log, opts historyOptions, now time.Time, format string) error {
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if opts.since < 0 {
+		return fmt.Errorf("--since must not be negative")
+	}
+
+	events, err := log.ReadAll(historyFilter(opts, now))
+	if err != nil {
+		return fmt.Errorf("reading event log: %w", err)
+	}
+
+	entries := make([]eventEntry, 0, len(events))
+	for _, e := range slices.Backward(events) {
+		if opts.limit > 0 && len(entries) == opts.limit {
+			break
+		}
+		entries = append(entries, eventEntry{
+			Time:        e.Time,
+			Job:         e.JobURL,
+			Event:       e.Kind.String(),
+			Result:      e.Result,
+			BuildNumber: e.BuildNumber,
+			Error:       e.Error,
+		})
+	}
+
+	if format == "" {
+		format = output.FormatTable
+	}
+	if format == output.FormatTable && len(entries) == 0 {
+		fmt.Fprintln(w, "No recorded events match.")
+		return nil
+	}
+	renderer, err := output.New(format, w)
+	if err != nil {
+		return err
+	}
+
+	records := output.Records{Columns: []string{"Time", "Job", "Event", "Result"}, Data: entries}
+	for _, e := range entries {
+		result := e.Result
+		if result == "" {
+			result = e.Error
+		}
+		if result == "" {
+			result = "-"
+		}
+		records.Rows = append(records.Rows, []string{e.Time.Local().Format("2006-01-02 15:04:05"), jobDisplayName(e.Job), e.Event, result})
+	}
+	return output.Render(records, renderer)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/eventlog"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/output"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seededHistoryLog(t *testing.T, now time.Time) *eventlog.Log {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	var data []byte
+	for _, e := range []eventlog.Event{
+		{Time: now.Add(-72 * time.Hour), JobEvent: monitor.JobEvent{JobURL: "https://j/job/a/10", Kind: monitor.EventFinished, Result: "SUCCESS", Duration: time.Minute}},
+		{Time: now.Add(-30 * time.Hour), JobEvent: monitor.JobEvent{JobURL: "https://j/job/b/4", Kind: monitor.EventFinished, Result: "ABORTED", Duration: 3 * time.Minute}},
+		{Time: now.Add(-3 * time.Hour), JobEvent: monitor.JobEvent{JobURL: "https://j/job/a/12", Kind: monitor.EventError}, Error: "timeout"},
+		{Time: now.Add(-2 * time.Hour), JobEvent: monitor.JobEvent{JobURL: "https://j/job/a/12", Kind: monitor.EventFinished, Result: "FAILURE", Duration: 2 * time.Minute}},
+		{Time: now.Add(-time.Hour), JobEvent: monitor.JobEvent{JobURL: "https://j/job/b/5", Kind: monitor.EventFinished, Result: "SUCCESS", Duration: 4*time.Minute + 32*time.Second}},
+	} {
+		line, err := json.Marshal(e)
+		require.NoError(t, err)
+		data = append(append(data, line...), '\n')
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return eventlog.New(path)
+}
+
+func historyJobsAndResults(t *testing.T, log *eventlog.Log, opts historyOptions, now time.Time) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, runHistory(&buf, log, opts, now, output.FormatJSON))
+	var entries []historyEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+	out := make([]string, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, jobDisplayName(e.Job)+" "+e.Result)
+	}
+	return out
+}
+
+func TestRunHistory_Filters(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := seededHistoryLog(t, now)
+
+	tests := []struct {
+		name string
+		opts historyOptions
+		want []string
+	}{
+		{"all newest first", historyOptions{}, []string{"b SUCCESS", "a FAILURE", "b ABORTED", "a SUCCESS"}},
+		{"limit", historyOptions{limit: 2}, []string{"b SUCCESS", "a FAILURE"}},
+		{"since", historyOptions{since: 24 * time.Hour}, []string{"b SUCCESS", "a FAILURE"}},
+		{"result", historyOptions{result: "success"}, []string{"b SUCCESS", "a SUCCESS"}},
+		{"job from build URL", historyOptions{jobURL: "https://j/job/a/12/"}, []string{"a FAILURE", "a SUCCESS"}},
+		{"combined", historyOptions{jobURL: "https://j/job/b", since: 48 * time.Hour, result: "ABORTED"}, []string{"b ABORTED"}},
+		{"nothing matches", historyOptions{result: "UNSTABLE"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, historyJobsAndResults(t, log, tt.opts, now))
+		})
+	}
+}
+
+func TestRunHistory_Table(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := seededHistoryLog(t, now)
+
+	var buf bytes.Buffer
+	require.NoError(t, runHistory(&buf, log, historyOptions{limit: 1}, now, ""))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"JOB", "RESULT", "FINISHED", "DURATION"}, strings.Fields(lines[0]))
+	fields := strings.Fields(lines[1])
+	assert.Equal(t, "b", fields[0])
+	assert.Equal(t, "SUCCESS", fields[1])
+	assert.Equal(t, "4m32s", fields[len(fields)-1])
+
+	buf.Reset()
+	require.NoError(t, runHistory(&buf, log, historyOptions{result: "UNSTABLE"}, now, ""))
+	assert.Equal(t, "No completed builds match.\n", buf.String())
+}
+
+func TestRunHistory_Plain(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	log := seededHistoryLog(t, now)
+
+	var buf bytes.Buffer
+	require.NoError(t, runHistory(&buf, log, historyOptions{limit: 1}, now, output.FormatPlain))
+	fields := strings.Split(strings.TrimSpace(buf.String()), "\t")
+	assert.Equal(t, "b", fields[0])
+	assert.Equal(t, "SUCCESS", fields[1])
+	assert.Equal(t, "4m32s", fields[3])
+
+	err := runHistory(&buf, log, historyOptions{}, now, "yaml")
+	assert.ErrorContains(t, err, "unknown output format")
+}
+
+func TestRunHistory_Errors(t *testing.T) {
+	log := eventlog.New(filepath.Join(t.TempDir(), "events.jsonl"))
+
+	err := runHistory(&bytes.Buffer{}, log, historyOptions{jobURL: "https://j/job/missing"}, time.Now(), "")
+	assert.ErrorContains(t, err, "no completed builds recorded for https://j/job/missing")
+
+	err = runHistory(&bytes.Buffer{}, log, historyOptions{limit: -1}, time.Now(), "")
+	assert.ErrorContains(t, err, "--limit")
+}
+
+func TestRunEventHistory(t *testing.T) {
+	log := eventlog.New(filepath.Join(t.TempDir(), "events.jsonl"))
+	for _, e := range []monitor.JobEvent{
+		{JobURL: "https://j/job/a/1", Kind: monitor.EventFinished, Result: "SUCCESS"},
+		{JobURL: "https://j/job/b/3", Kind: monitor.EventNotFound, Error: errors.New("http error: 404")},
+		{JobURL: "https://j/job/a/2", Kind: monitor.EventFinished, Result: "FAILURE", BuildNumber: 2},
+	} {
+		require.NoError(t, log.Append(e))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, runEventHistory(&buf, log, historyOptions{}, time.Now(), output.FormatJSON))
+	var entries []eventEntry
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entries))
+	require.Len(t, entries, 3)
+	assert.Equal(t, "https://j/job/a/2", entries[0].Job)
+	assert.Equal(t, "finished", entries[0].Event)
+	assert.Equal(t, "not_found", entries[1].Event)
+	assert.Equal(t, "http error: 404", entries[1].Error)
+
+	buf.Reset()
+	require.NoError(t, runEventHistory(&buf, log, historyOptions{jobURL: "https://j/job/a", result: "success"}, time.Now(), output.FormatTable))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], "SUCCESS")
+
+	buf.Reset()
+	require.NoError(t, runEventHistory(&buf, log, historyOptions{result: "ABORTED"}, time.Now(), ""))
+	assert.Equal(t, "No recorded events match.\n", buf.String())
+	assert.Error(t, runEventHistory(&buf, log, historyOptions{limit: -1}, time.Now(), ""))
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var importReplace bool
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Load monitored jobs from a file written by jw export",
+	Long: `Load the monitored jobs from a file written by "jw export" (- for stdin).
+
+By default (--merge) jobs that are not already monitored are added. With
+--replace the imported jobs replace every monitored job. Settings and history
+in the file are ignored.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r := io.Reader(os.Stdin)
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		changed, err := importJobs(os.Stdout, config.NewDiskStore(), r, importReplace)
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error importing config: %v", err)))
+			os.Exit(1)
+		}
+		if changed && signalDaemonReload() {
+			fmt.Println("Daemon signaled to reload the config.")
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(importCmd)
+	// --merge is the default; the flag only exists to be explicit about it.
+	importCmd.Flags().Bool("merge", true, "Add imported jobs that are not already monitored")
+	importCmd.Flags().BoolVar(&importReplace, "replace", false, "Replace all monitored jobs with the imported ones")
+	importCmd.MarkFlagsMutuallyExclusive("merge", "replace")
+}
+
+// importJobs reads an exported config from r and merges its jobs into store,
+// or replaces the monitored jobs with them. It reports whether the monitored
+// jobs changed.
+func importJobs(w io.Writer, store config.ConfigStore, r io.Reader, replace bool) (bool, error) {
+	var exported config.Config
+	if err := json.NewDecoder(r).Decode(&exported); err != nil {
+		return false, fmt.Errorf("invalid export file: %w", err)
+	}
+
+	var added, skipped, removed int
+	err := store.Update(func(cfg *config.Config) error {
+		added, skipped, removed = 0, 0, 0
+		if replace {
+			removed = len(cfg.Jobs)
+			cfg.Jobs = make(map[string]config.Job, len(exported.Jobs))
+		}
+		for url, job := range exported.Jobs {
+			if job.URL == "" {
+				job.URL = url
+			}
+			if cfg.HasJob(job.URL) {
+				skipped++
+				continue
+			}
+			cfg.Jobs[job.URL] = job
+			added++
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if replace {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Replaced %d monitored job(s).", removed)))
+	}
+	fmt.Fprintln(w, ui.GreenText(fmt.Sprintf("Imported %d job(s).", added)))
+	if skipped > 0 {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Skipped %d job(s) already being monitored.", skipped)))
+	}
+	return added > 0 || removed > 0, nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up jw for the first time",
+	Long: `Walk through first-time setup: store Jenkins credentials, install the browser
+extension's native messaging host and start the daemon. Steps that are already
+done are skipped, and each remaining one can be declined.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		browsers, home := extensionTargets()
+		steps := initSteps{
+			hasCredentials: func() bool {
+				_, err := config.GetCredentials()
+				return err == nil
+			},
+			authenticate: func(r *bufio.Reader, w io.Writer) error {
+				return authenticate(r, w, nil, config.DefaultProfile, false, readTerminalPassword)
+			},
+			extensionInstalled: func() bool {
+				return nativeHostStatus(io.Discard, home, browsers) == nil
+			},
+			installExtension: func(w io.Writer) error {
+				return installExtensionHost(w, home, browsers)
+			},
+			daemonRunning: func() bool {
+				_, running := pidfileIsDaemonRunning()
+				return running
+			},
+			startDaemon: startDaemonIfNeeded,
+		}
+		runInit(bufio.NewReader(os.Stdin), os.Stdout, steps)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(initCmd)
+}
+
+// initSteps checks and performs each jw init step.
+type initSteps struct {
+	hasCredentials     func() bool
+	authenticate       func(r *bufio.Reader, w io.Writer) error
+	extensionInstalled func() bool
+	installExtension   func(w io.Writer) error
+	daemonRunning      func() bool
+	startDaemon        func() error
+}
+
+// initStep is one jw init step: done reports whether it is already complete
+// and run completes it.
+type initStep struct {
+	name   string
+	prompt string
+	done   func() bool
+	run    func() error
+}
+
+// runInit walks through the steps, asking on w and reading answers from r,
+// then prints a summary of what each step ended up as.
+func runInit(r *bufio.Reader, w io.Writer, steps initSteps) {
+	plan := []initStep{
+		{
+			name:   "Credentials",
+			prompt: "No Jenkins credentials found. Set them up now?",
+			done:   steps.hasCredentials,
+			run:    func() error { return steps.authenticate(r, w) },
+		},
+		{
+			name:   "Browser extension",
+			prompt: "Install the native messaging host for the browser extension?",
+			done:   steps.extensionInstalled,
+			run:    func() error { return steps.installExtension(w) },
+		},
+		{
+			name:   "Daemon",
+			prompt: "Start the monitoring daemon?",
+			done:   steps.daemonRunning,
+			run:    steps.startDaemon,
+		},
+	}
+
+	outcomes := make([]string, len(plan))
+	for i, step := range plan {
+		switch {
+		case step.done():
+			outcomes[i] = ui.GreenText("already set up")
+		case !confirm(r, w, step.prompt):
+			outcomes[i] = ui.YellowText("skipped")
+		default:
+			if err := step.run(); err != nil {
+				fmt.Fprintln(w, ui.RedText(fmt.Sprintf("%s: %v", step.name, err)))
+				outcomes[i] = ui.RedText("failed")
+			} else {
+				outcomes[i] = ui.GreenText("done")
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Setup summary:")
+	for i, step := range plan {
+		fmt.Fprintf(w, "  %-18s %s\n", step.name+":", outcomes[i])
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.MutedText("Add a job to monitor with: jw add <job_url>"))
+}
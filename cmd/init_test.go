@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInit_Integration(t *testing.T) {
+	var authUser string
+	var extensionInstalled, daemonStarted bool
+	steps := initSteps{
+		hasCredentials: func() bool { return false },
+		authenticate: func(r *bufio.Reader, w io.Writer) error {
+			authUser, _ = r.ReadString('\n')
+			return nil
+		},
+		extensionInstalled: func() bool { return false },
+		installExtension: func(w io.Writer) error {
+			extensionInstalled = true
+			return nil
+		},
+		daemonRunning: func() bool { return false },
+		startDaemon: func() error {
+			daemonStarted = true
+			return errors.New("daemon failed to start")
+		},
+	}
+
+	var out bytes.Buffer
+	runInit(bufio.NewReader(strings.NewReader("y\nalice\nn\ny\n")), &out, steps)
+
+	assert.Equal(t, "alice\n", authUser, "the auth flow reads from the same input")
+	assert.False(t, extensionInstalled, "declined steps are skipped")
+	assert.True(t, daemonStarted)
+
+	output := out.String()
+	assert.Contains(t, output, "Daemon: daemon failed to start")
+	summary := output[strings.Index(output, "Setup summary:"):]
+	assert.Contains(t, summary, "Credentials:       "+ui.GreenText("done"))
+	assert.Contains(t, summary, "Browser extension: "+ui.YellowText("skipped"))
+	assert.Contains(t, summary, "Daemon:            "+ui.RedText("failed"))
+	assert.Contains(t, summary, "jw add <job_url>")
+}
+
+func TestRunInit_AlreadySetUp(t *testing.T) {
+	fail := func() error {
+		t.Fatal("no step should run")
+		return nil
+	}
+	steps := initSteps{
+		hasCredentials:     func() bool { return true },
+		authenticate:       func(*bufio.Reader, io.Writer) error { return fail() },
+		extensionInstalled: func() bool { return true },
+		installExtension:   func(io.Writer) error { return fail() },
+		daemonRunning:      func() bool { return true },
+		startDaemon:        fail,
+	}
+
+	var out bytes.Buffer
+	runInit(bufio.NewReader(strings.NewReader("")), &out, steps)
+
+	assert.NotContains(t, out.String(), "[y/N]")
+	require.Equal(t, 3, strings.Count(out.String(), ui.GreenText("already set up")))
+}
@@ -3,11 +3,11 @@ package cmd
 import (
 	"encoding/base64"
 	"encoding/json"
-	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -15,7 +15,9 @@ import (
 
 	"jenkins-monitor/pkg/config"
 	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
 	"jenkins-monitor/pkg/notify"
+	"jenkins-monitor/pkg/pidfile"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,26 +25,20 @@ import (
 
 type recordingNotifier struct {
 	mu    sync.Mutex
-	calls []notifyCall
+	calls []notify.Notification
 }
 
-type notifyCall struct {
-	Title   string
-	Message string
-	URL     string
-}
-
-func (r *recordingNotifier) Send(title, message, url string) error {
+func (r *recordingNotifier) Send(n notify.Notification) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.calls = append(r.calls, notifyCall{Title: title, Message: message, URL: url})
+	r.calls = append(r.calls, n)
 	return nil
 }
 
-func (r *recordingNotifier) getCalls() []notifyCall {
+func (r *recordingNotifier) getCalls() []notify.Notification {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	out := make([]notifyCall, len(r.calls))
+	out := make([]notify.Notification, len(r.calls))
 	copy(out, r.calls)
 	return out
 }
@@ -93,7 +89,7 @@ func TestAddMonitorRemoveIntegration(t *testing.T) {
 	token := base64.StdEncoding.EncodeToString([]byte("test:fake"))
 	notifier := &recordingNotifier{}
 	stopChan := make(chan struct{})
-	logger := log.New(os.Stderr, "test-daemon: ", log.LstdFlags)
+	logger := logging.TextLogger(os.Stderr)
 
 	deps := DaemonDeps{
 		Store:          store,
@@ -134,6 +130,8 @@ func TestAddMonitorRemoveIntegration(t *testing.T) {
 	cfg, err = store.Load()
 	require.NoError(t, err)
 	assert.Empty(t, cfg.Jobs, "config should have zero jobs after completion")
+	require.Len(t, cfg.CompletionHistory[jobURL], 1, "completion should be recorded")
+	assert.Equal(t, "SUCCESS", cfg.CompletionHistory[jobURL][0].Result)
 
 	// Notification should have been sent exactly once.
 	calls := notifier.getCalls()
@@ -148,3 +146,64 @@ func TestAddMonitorRemoveIntegration(t *testing.T) {
 	require.True(t, ok, "auth header should have been captured")
 	assert.Equal(t, "Basic "+token, auth, "auth header mismatch")
 }
+
+func TestIntegration_ConfigDirOverride(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(t.TempDir(), "instance")
+	t.Setenv("JW_CONFIG_DIR", dir)
+
+	require.NoError(t, config.NewDiskStore().Update(func(cfg *config.Config) error {
+		cfg.AddJob("https://jenkins/job/app/1")
+		return nil
+	}))
+	require.NoError(t, config.SaveCredentials(&config.Credentials{Username: "user", Token: "token"}))
+	require.NoError(t, pidfile.Write())
+	logger, err := logging.SetupLogger(logging.FormatText)
+	require.NoError(t, err)
+	logger.Info("hello")
+
+	for _, name := range []string{"monitored_jobs.json", ".credentials", "daemon.pid", "jenkins_monitor.log"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+	entries, err := os.ReadDir(home)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is written under HOME")
+}
+
+func TestIntegration_AddThreeURLs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := config.NewDiskStore()
+
+	file := filepath.Join(t.TempDir(), "jobs.txt")
+	require.NoError(t, os.WriteFile(file, []byte("https://jenkins/job/b/2\n# skipped\n\nhttps://jenkins/job/c/3\n"), 0o600))
+	fromFile, err := readURLsFromFile(file, nil)
+	require.NoError(t, err)
+	urls := append([]string{"https://jenkins/job/a/1"}, fromFile...)
+	require.Len(t, urls, 3)
+
+	var out strings.Builder
+	added, err := addJobs(&out, store, urls, jobOptions{profile: config.DefaultProfile})
+	require.NoError(t, err)
+	assert.Equal(t, 3, added)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 3)
+	for _, jobURL := range urls {
+		assert.True(t, cfg.HasJob(jobURL), jobURL)
+		assert.Contains(t, out.String(), "Added job to config: "+jobURL)
+	}
+
+	// Adding them again reports each duplicate and adds only the new URL.
+	out.Reset()
+	added, err = addJobs(&out, store, append(urls, "https://jenkins/job/d/4"), jobOptions{profile: config.DefaultProfile})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	for _, jobURL := range urls {
+		assert.Contains(t, out.String(), "Job is already being monitored: "+jobURL)
+	}
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 4)
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"text/template"
+
+	"jenkins-monitor/pkg/paths"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const launchdLabel = "com.jw.monitor"
+
+var launchdCmd = &cobra.Command{
+	Use:   "launchd",
+	Short: "Manage the launchd agent that starts the daemon on macOS",
+	Long: `Manage a launchd agent for the jw daemon. The agent is neither kept alive nor
+run at load, because the daemon exits on its own once no jobs are left;
+'jw add' starts it again when needed.`,
+}
+
+var launchdInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Write the launchd agent plist",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := launchdTarget()
+		if err == nil {
+			var exe string
+			if exe, err = os.Executable(); err == nil {
+				err = installLaunchdAgent(os.Stdout, path, exe)
+			}
+		}
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+var launchdUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Unload and remove the launchd agent plist",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := launchdTarget()
+		if err == nil {
+			err = uninstallLaunchdAgent(os.Stdout, path, launchctlUnload)
+		}
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	launchdCmd.AddCommand(launchdInstallCmd, launchdUninstallCmd)
+	RootCmd.AddCommand(launchdCmd)
+}
+
+// launchdTarget returns the plist path, failing off macOS.
+func launchdTarget() (string, error) {
+	if runtime.GOOS != "darwin" {
+		return "", errors.New("launchd is only available on macOS")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return launchdPlistPath(home), nil
+}
+
+// launchdPlistPath returns where the agent plist lives under home.
+func launchdPlistPath(home string) string {
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist")
+}
+
+var launchdPlistTemplate = template.Must(template.New("plist").Funcs(template.FuncMap{
+	"xml": func(s string) (string, error) {
+		var buf bytes.Buffer
+		err := xml.EscapeText(&buf, []byte(s))
+		return buf.String(), err
+	},
+}).Parse(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Label}}</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>{{xml .Executable}}</string>
+		<string>_start_jw_daemon</string>
+	</array>
+{{- with .ConfigDir}}
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>JW_CONFIG_DIR</key>
+		<string>{{xml .}}</string>
+	</dict>
+{{- end}}
+	<key>RunAtLoad</key>
+	<false/>
+	<key>KeepAlive</key>
+	<false/>
+</dict>
+</plist>
+`))
+
+// launchdPlist returns the agent plist running exe as the daemon, passing a
+// non-empty configDir to it as JW_CONFIG_DIR.
+func launchdPlist(exe, configDir string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := launchdPlistTemplate.Execute(&buf, struct{ Label, Executable, ConfigDir string }{launchdLabel, exe, configDir})
+	return buf.Bytes(), err
+}
+
+// installLaunchdAgent writes the plist for exe to path and tells w how to
+// load it.
+func installLaunchdAgent(w io.Writer, path, exe string) error {
+	data, err := launchdPlist(exe, paths.OverrideDir())
+	if err != nil {
+		return fmt.Errorf("generating plist: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing plist: %w", err)
+	}
+	fmt.Fprintln(w, ui.GreenText("Wrote "+path))
+	fmt.Fprintln(w, "Load it with:")
+	fmt.Fprintf(w, "  launchctl load %s\n", path)
+	return nil
+}
+
+// uninstallLaunchdAgent unloads and deletes the plist at path. A failed
+// unload, usually because the agent was never loaded, is reported but does
+// not stop the removal.
+func uninstallLaunchdAgent(w io.Writer, path string, unload func(path string) error) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return errors.New("launchd agent is not installed")
+	}
+	if err := unload(path); err != nil {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("launchctl unload %s: %v", path, err)))
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing plist: %w", err)
+	}
+	fmt.Fprintln(w, ui.GreenText("Removed "+path))
+	fmt.Fprintln(w, ui.MutedText(fmt.Sprintf("To reinstall, run jw launchd install, then launchctl load %s", path)))
+	return nil
+}
+
+func launchctlUnload(path string) error {
+	return exec.Command("launchctl", "unload", path).Run()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plistDict is the top-level dict of a plist, decoded just far enough to
+// check the keys jw writes.
+type plistDict struct {
+	XMLName xml.Name `xml:"plist"`
+	Dict    struct {
+		Inner []byte `xml:",innerxml"`
+	} `xml:"dict"`
+}
+
+// parsePlist returns the top-level keys of a plist mapped to their string,
+// string array or boolean values.
+func parsePlist(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	var doc plistDict
+	require.NoError(t, xml.Unmarshal(data, &doc), "plist should be valid XML")
+
+	dec := xml.NewDecoder(bytes.NewReader(doc.Dict.Inner))
+	values := map[string]any{}
+	var key string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "key":
+			require.NoError(t, dec.DecodeElement(&key, &start))
+		case "string":
+			var s string
+			require.NoError(t, dec.DecodeElement(&s, &start))
+			values[key] = s
+		case "array":
+			var arr struct {
+				Strings []string `xml:"string"`
+			}
+			require.NoError(t, dec.DecodeElement(&arr, &start))
+			values[key] = arr.Strings
+		case "true", "false":
+			values[key] = start.Name.Local == "true"
+		}
+	}
+	return values
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	exe := "/Users/me/Tools & Bins/<jw>"
+	data, err := launchdPlist(exe, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"Label":            launchdLabel,
+		"ProgramArguments": []string{exe, "_start_jw_daemon"},
+		"RunAtLoad":        false,
+		"KeepAlive":        false,
+	}, parsePlist(t, data))
+}
+
+func TestLaunchdPlist_ConfigDir(t *testing.T) {
+	data, err := launchdPlist("/usr/local/bin/jw", "/srv/a&b")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<key>EnvironmentVariables</key>")
+	assert.Contains(t, string(data), "<key>JW_CONFIG_DIR</key>\n\t\t<string>/srv/a&amp;b</string>")
+}
+
+func TestLaunchdInstallUninstall(t *testing.T) {
+	path := launchdPlistPath(t.TempDir())
+
+	var out bytes.Buffer
+	require.NoError(t, installLaunchdAgent(&out, path, "/usr/local/bin/jw"))
+	assert.Contains(t, out.String(), path)
+	assert.Contains(t, out.String(), "launchctl load "+path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/usr/local/bin/jw", "_start_jw_daemon"}, parsePlist(t, data)["ProgramArguments"])
+
+	var unloaded string
+	out.Reset()
+	require.NoError(t, uninstallLaunchdAgent(&out, path, func(p string) error {
+		unloaded = p
+		return errors.New("not loaded")
+	}))
+	assert.Equal(t, path, unloaded)
+	assert.Contains(t, out.String(), "not loaded")
+	assert.Contains(t, out.String(), "Removed "+path)
+	assert.NoFileExists(t, path)
+
+	err = uninstallLaunchdAgent(&out, path, func(string) error { return nil })
+	assert.ErrorContains(t, err, "not installed")
+}
+
+func TestLaunchdPlistPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/home", "Library", "LaunchAgents", "com.jw.monitor.plist"), launchdPlistPath("/home"))
+}
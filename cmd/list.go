@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"text/template"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/output"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	JSON   bool
+	Format string
+	Status string
+	Output string
+	Label  string
+}
+
+var listOpts listOptions
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List monitored jobs",
+	Long: `List monitored jobs, one URL per line. Works whether or not the daemon is running.
+
+--format takes a Go text/template executed once per job, e.g.
+  jw list --format '{{.URL}} {{.StartTime}} {{.LastCheckFailed}}'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := listOpts
+		opts.Output = outputFormat(cmd, opts.JSON)
+		if err := runList(os.Stdout, statusStore(), opts); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(listCmd)
+	listCmd.Flags().BoolVar(&listOpts.JSON, "json", false, "Print jobs as a JSON array")
+	listCmd.Flags().StringVar(&listOpts.Format, "format", "", "Go template applied to each job")
+	listCmd.Flags().StringVar(&listOpts.Status, "status", "all", "Filter by last check status: ok, failing, or all")
+	listCmd.Flags().StringVar(&listOpts.Label, "label", "", "Only list jobs with this label")
+	listCmd.Flags().BoolVar(&statusIgnoreChecksum, "ignore-checksum", false, "Read the config even if its checksum does not match (see jw clean --repair)")
+}
+
+func runList(w io.Writer, store config.ConfigStore, opts listOptions) error {
+	if opts.JSON {
+		opts.Output = output.FormatJSON
+	}
+	if opts.Output != "" && opts.Format != "" {
+		return fmt.Errorf("--json/--output and --format are mutually exclusive")
+	}
+	if !slices.Contains([]string{"", "all", "ok", "failing"}, opts.Status) {
+		return fmt.Errorf("invalid --status %q (want ok, failing, or all)", opts.Status)
+	}
+
+	var renderer output.Renderer
+	if opts.Output != "" {
+		var err error
+		if renderer, err = output.New(opts.Output, w); err != nil {
+			return err
+		}
+	}
+
+	var tmpl *template.Template
+	if opts.Format != "" {
+		var err error
+		tmpl, err = template.New("list").Parse(opts.Format)
+		if err != nil {
+			return fmt.Errorf("parsing --format: %w", err)
+		}
+	}
+
+	cfg, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	jobs := filterJobsByStatus(cfg.Jobs, opts.Status)
+	if opts.Label != "" {
+		jobs = slices.DeleteFunc(jobs, func(job config.Job) bool { return !job.HasLabel(opts.Label) })
+	}
+
+	switch {
+	case renderer != nil:
+		records := output.Records{Columns: []string{"URL", "Status", "Build", "Started"}, Data: jobs}
+		for _, job := range jobs {
+			status, _, _ := jobStatus(job)
+			records.Rows = append(records.Rows, []string{job.URL, status, formatBuildNumber(job.BuildNumber), job.StartTime.Local().Format("2006-01-02 15:04")})
+		}
+		return output.Render(records, renderer)
+	case tmpl != nil:
+		for _, job := range jobs {
+			if err := tmpl.Execute(w, job); err != nil {
+				return fmt.Errorf("executing --format: %w", err)
+			}
+			fmt.Fprintln(w)
+		}
+	default:
+		for _, job := range jobs {
+			fmt.Fprintln(w, job.URL)
+		}
+	}
+	return nil
+}
+
+// filterJobsByStatus returns the jobs matching status (ok, failing, or all),
+// sorted by URL.
+func filterJobsByStatus(jobs map[string]config.Job, status string) []config.Job {
+	out := make([]config.Job, 0, len(jobs))
+	for _, job := range jobs {
+		switch status {
+		case "ok":
+			if job.LastCheckFailed {
+				continue
+			}
+		case "failing":
+			if !job.LastCheckFailed {
+				continue
+			}
+		}
+		out = append(out, job)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].URL < out[j].URL
+	})
+	return out
+}
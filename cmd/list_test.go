@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemStore(jobs ...config.Job) *config.MemoryStore {
+	store := config.NewMemoryStore()
+	_ = store.Update(func(cfg *config.Config) error {
+		for _, job := range jobs {
+			cfg.Jobs[job.URL] = job
+		}
+		return nil
+	})
+	return store
+}
+
+func listTestStore() *config.MemoryStore {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return newMemStore(
+		config.Job{URL: "https://jenkins/job/b/2", StartTime: start, LastCheckFailed: true, BuildNumber: 2},
+		config.Job{URL: "https://jenkins/job/a/1", StartTime: start},
+	)
+}
+
+func TestRunList_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{}))
+	assert.Equal(t, "https://jenkins/job/a/1\nhttps://jenkins/job/b/2\n", buf.String())
+}
+
+func TestRunList_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{JSON: true}))
+
+	var jobs []config.Job
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &jobs))
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "https://jenkins/job/a/1", jobs[0].URL)
+	assert.True(t, jobs[1].LastCheckFailed)
+}
+
+func TestRunList_Output(t *testing.T) {
+	started := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Local().Format("2006-01-02 15:04")
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{Output: "plain"}))
+	assert.Equal(t, "https://jenkins/job/a/1\tOK\t-\t"+started+"\nhttps://jenkins/job/b/2\tFailing\t#2\t"+started+"\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{Output: "table"}))
+	assert.Contains(t, buf.String(), "URL")
+	assert.Contains(t, buf.String(), "STATUS")
+
+	assert.Error(t, runList(&buf, listTestStore(), listOptions{Output: "json", Format: "{{.URL}}"}))
+	assert.Error(t, runList(&buf, listTestStore(), listOptions{Output: "yaml"}))
+}
+
+func TestRunList_Format(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{Format: "{{.URL}} failed={{.LastCheckFailed}}"}))
+	assert.Equal(t, "https://jenkins/job/a/1 failed=false\nhttps://jenkins/job/b/2 failed=true\n", buf.String())
+}
+
+func TestRunList_StatusFilter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{Status: "failing"}))
+	assert.Equal(t, "https://jenkins/job/b/2\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runList(&buf, listTestStore(), listOptions{Status: "ok"}))
+	assert.Equal(t, "https://jenkins/job/a/1\n", buf.String())
+
+	err := runList(&buf, listTestStore(), listOptions{Status: "bogus"})
+	assert.ErrorContains(t, err, "invalid --status")
+	err = runList(&buf, config.NewMemoryStore(), listOptions{Status: "bogus"})
+	assert.ErrorContains(t, err, "invalid --status", "rejected even with no jobs")
+}
+
+func TestRunList_LabelFilter(t *testing.T) {
+	cfg := &config.Config{Jobs: map[string]config.Job{}}
+	applyAdd(cfg, []string{"https://jenkins/job/a/1"}, jobOptions{profile: config.DefaultProfile, labels: []string{"team-a", " team-a", ""}}, nil)
+	applyAdd(cfg, []string{"https://jenkins/job/b/2"}, jobOptions{profile: config.DefaultProfile, labels: []string{"team-b"}}, nil)
+	applyAdd(cfg, []string{"https://jenkins/job/c/3"}, jobOptions{profile: config.DefaultProfile}, nil)
+	assert.Equal(t, []string{"team-a"}, cfg.Jobs["https://jenkins/job/a/1"].Labels)
+	assert.Equal(t, []string{"https://jenkins/job/b/2"}, cfg.JobsWithLabel("team-b"))
+
+	store := newMemStore(slices.Collect(maps.Values(cfg.Jobs))...)
+	var buf bytes.Buffer
+	require.NoError(t, runList(&buf, store, listOptions{Label: "team-a"}))
+	assert.Equal(t, "https://jenkins/job/a/1\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runList(&buf, store, listOptions{Label: "team-c"}))
+	assert.Empty(t, buf.String())
+}
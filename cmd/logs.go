@@ -1,16 +1,34 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/pidfile"
 	"os"
-	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 )
 
+var (
+	logsTail     int
+	logsGrep     string
+	logsJob      string
+	logsFollow   bool
+	logsNoFollow bool
+	logsDaemon   bool
+	logsStamps   bool
+)
+
 var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Follow the logs of the jenkins-monitor daemon",
@@ -26,9 +44,55 @@ var logsCmd = &cobra.Command{
 			return
 		}
 
-		tailCmd := exec.Command("tail", "-f", logFile)
-		tailCmd.Stdout = os.Stdout
-		tailCmd.Stderr = os.Stderr
+		var annotate func(string) string
+		if logsDaemon {
+			if _, running := pidfile.IsDaemonRunning(); !running {
+				fmt.Println("Daemon is not running.")
+				os.Exit(1)
+			}
+			start, err := pidfile.StartTime()
+			if err != nil {
+				fmt.Println("Error reading daemon start time:", err)
+				os.Exit(1)
+			}
+			if logsStamps {
+				annotate = elapsedAnnotator(start, time.Now)
+			}
+			logsFollow, logsNoFollow = true, false
+		}
+
+		var filter lineFilter
+		if logsGrep != "" {
+			re, err := regexp.Compile(logsGrep)
+			if err != nil {
+				fmt.Println("Invalid --grep pattern:", err)
+				os.Exit(1)
+			}
+			filter = append(filter, re)
+		}
+		if logsJob != "" {
+			filter = append(filter, jobLineRegexp(logsJob))
+		}
+
+		f, err := os.Open(logFile)
+		if err != nil {
+			fmt.Println("Error opening log file:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		lines, err := tailLines(f, logsTail, filter)
+		if err != nil {
+			fmt.Println("Error reading log file:", err)
+			os.Exit(1)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+
+		if !logsFollow || logsNoFollow {
+			return
+		}
 
 		// Handle Ctrl+C
 		sigs := make(chan os.Signal, 1)
@@ -40,8 +104,8 @@ var logsCmd = &cobra.Command{
 			os.Exit(0)
 		}()
 
-		if err := tailCmd.Run(); err != nil {
-			fmt.Println("Error tailing log file:", err)
+		if err := followLog(f, os.Stdout, filter, annotate, nil); err != nil {
+			fmt.Println("Error following log file:", err)
 			os.Exit(1)
 		}
 	},
@@ -49,4 +113,198 @@ var logsCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 50, "Number of lines to show from the end of the log")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show lines matching this regular expression")
+	logsCmd.Flags().StringVarP(&logsJob, "job", "j", "", "Only show lines about this job URL")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", true, "Keep printing lines as they are written (the default)")
+	logsCmd.Flags().BoolVar(&logsNoFollow, "no-follow", false, "Print the last lines and exit instead of following")
+	logsCmd.Flags().BoolVar(&logsDaemon, "daemon", false, "Follow the running daemon, prefixing new lines with the time since it started")
+	logsCmd.Flags().BoolVar(&logsStamps, "timestamps", true, "With --daemon, prefix new lines with [+HH:MM:SS]")
+}
+
+// elapsedAnnotator returns a line transform prefixing each line with the time
+// elapsed since start, as now reports it.
+func elapsedAnnotator(start time.Time, now func() time.Time) func(string) string {
+	return func(line string) string {
+		return formatElapsed(now().Sub(start)) + " " + line
+	}
+}
+
+// formatElapsed renders d as "[+HH:MM:SS]"; hours are not wrapped at 24.
+func formatElapsed(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Second)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	return fmt.Sprintf("[+%02d:%02d:%02d]", h, m, s)
+}
+
+// lineFilter keeps lines matching every one of its patterns. An empty filter
+// keeps everything.
+type lineFilter []*regexp.Regexp
+
+func (f lineFilter) match(line string) bool {
+	for _, re := range f {
+		if !re.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// jobLineRegexp matches log lines mentioning jobURL, either in full or by the
+// short name the monitor logs (the part after the last "/job/").
+func jobLineRegexp(jobURL string) *regexp.Regexp {
+	jobURL = strings.TrimRight(jobURL, "/")
+	name := jobDisplayName(jobURL)
+	return regexp.MustCompile(`(?:^|[^\w-])(?:` + regexp.QuoteMeta(jobURL) + `|` + regexp.QuoteMeta(name) + `)(?:[^\w-]|$)`)
+}
+
+// tailLines reads r to EOF and returns the last n lines matching filter.
+func tailLines(r io.Reader, n int, filter lineFilter) ([]string, error) {
+	if n <= 0 {
+		_, err := io.Copy(io.Discard, r)
+		return nil, err
+	}
+
+	ring := make([]string, 0, n)
+	start := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !filter.match(line) {
+			continue
+		}
+		if len(ring) < n {
+			ring = append(ring, line)
+			continue
+		}
+		ring[start] = line
+		start = (start + 1) % n
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return append(ring[start:], ring[:start]...), nil
+}
+
+// followLog streams lines appended to f after its current offset to w until
+// stop is closed, passing each through annotate if it is set. Truncation
+// restarts from the beginning, and a file moved or removed (e.g. by log
+// rotation) is followed by the one created in its place.
+func followLog(f *os.File, w io.Writer, filter lineFilter, annotate func(string) string, stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Watching the directory keeps events coming after the file is replaced.
+	path := filepath.Clean(f.Name())
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	cur := f
+	defer func() {
+		if cur != f {
+			cur.Close()
+		}
+	}()
+
+	var partial string
+	emit := func() error {
+		info, err := cur.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Size() < offset {
+			offset, partial = 0, ""
+			if _, err := cur.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+
+		data, err := io.ReadAll(cur)
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+
+		chunk := partial + string(data)
+		lines := strings.Split(chunk, "\n")
+		partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			if !filter.match(line) {
+				continue
+			}
+			if annotate != nil {
+				line = annotate(line)
+			}
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+
+	// reopen switches to the file now at path once it is not the one being
+	// read, after reading what is left of the old one.
+	reopen := func() error {
+		next, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		curInfo, err := cur.Stat()
+		nextInfo, nextErr := next.Stat()
+		if err == nil && nextErr == nil && os.SameFile(curInfo, nextInfo) {
+			next.Close()
+			return nil
+		}
+		if err := emit(); err != nil {
+			next.Close()
+			return err
+		}
+		if cur != f {
+			cur.Close()
+		}
+		cur, offset, partial = next, 0, ""
+		return emit()
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != path {
+				continue
+			}
+			var err error
+			switch {
+			case event.Has(fsnotify.Create):
+				err = reopen()
+			case event.Has(fsnotify.Write), event.Has(fsnotify.Chmod), event.Has(fsnotify.Rename), event.Has(fsnotify.Remove):
+				err = emit()
+			}
+			if err != nil {
+				return err
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
 }
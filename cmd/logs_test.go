@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailLines(t *testing.T) {
+	input := "one\ntwo\nthree\nfour\nfive\n"
+
+	lines, err := tailLines(strings.NewReader(input), 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"four", "five"}, lines)
+
+	lines, err = tailLines(strings.NewReader(input), 10, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two", "three", "four", "five"}, lines)
+
+	lines, err = tailLines(strings.NewReader(input), 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestTailLines_Grep(t *testing.T) {
+	input := "Started monitoring: a\nError getting status for a\nStarted monitoring: b\nError getting status for b\nError getting status for c\n"
+
+	lines, err := tailLines(strings.NewReader(input), 2, lineFilter{regexp.MustCompile(`^Error`)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Error getting status for b", "Error getting status for c"}, lines)
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestFollowLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jw.log")
+	require.NoError(t, os.WriteFile(path, []byte("old line\n"), 0o644))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = tailLines(f, 10, nil)
+	require.NoError(t, err)
+
+	var out syncBuffer
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- followLog(f, &out, lineFilter{regexp.MustCompile("keep")}, nil, stop) }()
+
+	time.Sleep(100 * time.Millisecond)
+	w, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = w.WriteString("keep one\nskip two\nkeep three\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.Eventually(t, func() bool {
+		return out.String() == "keep one\nkeep three\n"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	close(stop)
+	require.NoError(t, <-done)
+}
+
+func TestFollowLog_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jw.log")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var out syncBuffer
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- followLog(f, &out, nil, nil, stop) }()
+
+	time.Sleep(100 * time.Millisecond)
+	appendLine := func(line string) {
+		w, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		require.NoError(t, err)
+		_, err = w.WriteString(line + "\n")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+
+	appendLine("before rotation")
+	require.Eventually(t, func() bool { return out.String() == "before rotation\n" }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendLine("after rotation")
+	require.Eventually(t, func() bool {
+		return out.String() == "before rotation\nafter rotation\n"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.Remove(path))
+	appendLine("after removal")
+	require.Eventually(t, func() bool {
+		return out.String() == "before rotation\nafter rotation\nafter removal\n"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	close(stop)
+	require.NoError(t, <-done)
+}
+
+func TestTailLines_JobFilter(t *testing.T) {
+	log := strings.Join([]string{
+		"2024/01/02 10:00:00 Started monitoring: myjob/12/ job=https://jenkins/job/myjob/12/",
+		"2024/01/02 10:00:00 Started monitoring: other/3/ job=https://jenkins/job/other/3/",
+		"2024/01/02 10:00:30 Received status for myjob/12/: Building=true, Result=",
+		"2024/01/02 10:00:30 Received status for myjob-2/5/: Building=true, Result=",
+		"2024/01/02 10:00:30 Received status for notmyjob/7/: Building=true, Result=",
+		"2024/01/02 10:00:30 Received status for other/3/: Building=true, Result=",
+		"2024/01/02 10:01:00 Build finished: myjob/12/ - Status: SUCCESS",
+		"2024/01/02 10:01:00 Configuration reloaded. Monitoring 1 jobs.",
+		"",
+	}, "\n")
+
+	lines, err := tailLines(strings.NewReader(log), 10, lineFilter{jobLineRegexp("https://jenkins/job/myjob")})
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		assert.Contains(t, line, "myjob/12/")
+	}
+
+	// Combined with --tail and --grep.
+	filter := lineFilter{jobLineRegexp("https://jenkins/job/myjob/12/"), regexp.MustCompile("Status")}
+	lines, err = tailLines(strings.NewReader(log), 1, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024/01/02 10:01:00 Build finished: myjob/12/ - Status: SUCCESS"}, lines)
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "[+00:00:00]", formatElapsed(0))
+	assert.Equal(t, "[+00:00:00]", formatElapsed(-time.Second))
+	assert.Equal(t, "[+00:01:05]", formatElapsed(65*time.Second+900*time.Millisecond))
+	assert.Equal(t, "[+02:03:04]", formatElapsed(2*time.Hour+3*time.Minute+4*time.Second))
+	assert.Equal(t, "[+27:00:00]", formatElapsed(27*time.Hour))
+}
+
+func TestElapsedAnnotator(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	clock := start.Add(90 * time.Minute)
+	annotate := elapsedAnnotator(start, func() time.Time { return clock })
+	assert.Equal(t, "[+01:30:00] Started monitoring: app/1/", annotate("Started monitoring: app/1/"))
+}
+
+func TestLogsFlags_JobAndFollow(t *testing.T) {
+	t.Cleanup(func() { logsJob, logsFollow = "", true })
+	require.NoError(t, logsCmd.ParseFlags([]string{"-j", "https://jenkins/job/myjob", "-f"}))
+	assert.Equal(t, "https://jenkins/job/myjob", logsJob)
+	assert.True(t, logsFollow)
+	assert.False(t, logsNoFollow)
+}
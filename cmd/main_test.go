@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain clears the XDG variables and JW_CONFIG_DIR so tests that point HOME at a temporary
+// directory never touch the real config, log or runtime directories.
+func TestMain(m *testing.M) {
+	for _, name := range []string{"XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_RUNTIME_DIR", "JW_CONFIG_DIR"} {
+		os.Unsetenv(name)
+	}
+	os.Exit(m.Run())
+}
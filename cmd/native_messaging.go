@@ -6,10 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
 	"syscall"
 
 	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
 	"jenkins-monitor/pkg/pidfile"
 
 	"github.com/spf13/cobra"
@@ -61,8 +61,9 @@ func handleNativeAdd(jobURL string) nativeResponse {
 		return nativeResponse{Error: err.Error()}
 	}
 
-	if !strings.HasPrefix(jobURL, "http://") && !strings.HasPrefix(jobURL, "https://") {
-		return nativeResponse{Error: "URL must start with http:// or https://"}
+	jobURL, err := jenkins.NormalizeJobURL(jobURL)
+	if err != nil {
+		return nativeResponse{Error: err.Error()}
 	}
 
 	store := config.NewDiskStore()
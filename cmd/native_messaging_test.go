@@ -127,6 +127,20 @@ func TestHandleNativeAdd_Duplicate(t *testing.T) {
 	assert.Contains(t, resp.Message, "already being monitored")
 }
 
+func TestHandleNativeAdd_DuplicateNearMatch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("JENKINS_USER", "user")
+	t.Setenv("JENKINS_API_TOKEN", "token")
+	withNoDaemon(t)
+
+	handleNativeAdd("https://jenkins.example.com/job/test/1")
+
+	resp := handleNativeAdd("https://jenkins.example.com/job/test/1/api/json/")
+	assert.True(t, resp.Success)
+	assert.Contains(t, resp.Message, "already being monitored")
+}
+
 func TestHandleNativeAdd_NoCredentials(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("HOME", dir)
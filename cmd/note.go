@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note <job_url> [text]",
+	Short: "Set or clear the note on a monitored job",
+	Long: `Set the note on a monitored job, e.g. why it is being watched. The note is
+shown by jw status and included in the job's notification. Without text, the
+note is cleared.`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var note string
+		if len(args) == 2 {
+			note = args[1]
+		}
+		if err := setJobNote(os.Stdout, config.NewDiskStore(), args[0], note); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(noteCmd)
+}
+
+// setJobNote replaces the note on the job at jobURL, failing if it is not
+// monitored.
+func setJobNote(w io.Writer, store config.ConfigStore, jobURL, note string) error {
+	found := false
+	if err := store.Update(func(cfg *config.Config) error {
+		found = cfg.SetJobNote(jobURL, note)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("job not found in config: %s", jobURL)
+	}
+	if note == "" {
+		fmt.Fprintln(w, ui.GreenText("Cleared note for "+jobURL))
+	} else {
+		fmt.Fprintln(w, ui.GreenText("Updated note for "+jobURL))
+	}
+	return nil
+}
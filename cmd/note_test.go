@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJobNote(t *testing.T) {
+	jobURL := "https://jenkins/job/app/8"
+	store := newMemStore(config.Job{URL: jobURL})
+
+	var out bytes.Buffer
+	require.NoError(t, setJobNote(&out, store, jobURL, "waiting for hotfix deploy"))
+	assert.Contains(t, out.String(), "Updated note for "+jobURL)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "waiting for hotfix deploy", cfg.Jobs[jobURL].Notes)
+
+	out.Reset()
+	require.NoError(t, setJobNote(&out, store, jobURL, ""))
+	assert.Contains(t, out.String(), "Cleared note for "+jobURL)
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Jobs[jobURL].Notes)
+
+	err = setJobNote(&out, store, "https://jenkins/job/other/1", "note")
+	assert.ErrorContains(t, err, "job not found")
+}
+
+func TestAddJobs_Note(t *testing.T) {
+	store := newMemStore()
+	jobURL := "https://jenkins/job/app/8"
+
+	_, err := addJobs(&bytes.Buffer{}, store, []string{jobURL}, jobOptions{note: "waiting for hotfix deploy"})
+	require.NoError(t, err)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "waiting for hotfix deploy", cfg.Jobs[jobURL].Notes)
+}
+
+func TestWriteStatus_ShowsTruncatedNote(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	note := strings.Repeat("x", 100)
+	cfg := &config.Config{Jobs: map[string]config.Job{
+		"https://jenkins/job/app/8": {URL: "https://jenkins/job/app/8", StartTime: now.Add(-5 * time.Minute), Notes: note},
+	}}
+
+	var out bytes.Buffer
+	writeStatus(&out, 1, true, cfg, now, false)
+
+	assert.Contains(t, out.String(), "job/app/8 (monitored for 5m) - "+strings.Repeat("x", 79)+"…\n")
+	assert.NotContains(t, out.String(), note)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/notify"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	slackWebhook   string
+	discordWebhook string
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Configure notification destinations",
+}
+
+var notifySlackCmd = &cobra.Command{
+	Use:   "slack",
+	Short: "Send notifications to a Slack incoming webhook",
+	Long:  `Send notifications to a Slack incoming webhook in addition to desktop notifications. Pass an empty --webhook to disable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if slackWebhook != "" && !strings.HasPrefix(slackWebhook, "https://") && !strings.HasPrefix(slackWebhook, "http://") {
+			fmt.Println(ui.RedText("Error: Webhook URL must start with http:// or https://"))
+			os.Exit(1)
+		}
+
+		store := config.NewDiskStore()
+		if err := store.Update(func(cfg *config.Config) error {
+			cfg.Notifications.SlackWebhookURL = slackWebhook
+			return nil
+		}); err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+			os.Exit(1)
+		}
+
+		if slackWebhook == "" {
+			fmt.Println(ui.GreenText("Slack notifications disabled."))
+		} else {
+			fmt.Println(ui.GreenText("Slack webhook saved."))
+		}
+		fmt.Println("Takes effect the next time the daemon starts.")
+	},
+}
+
+var notifyDiscordCmd = &cobra.Command{
+	Use:   "discord",
+	Short: "Send notifications to a Discord channel webhook",
+	Long:  `Send notifications to a Discord channel webhook in addition to desktop notifications. Pass an empty --webhook to disable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if discordWebhook != "" && !strings.HasPrefix(discordWebhook, "https://") && !strings.HasPrefix(discordWebhook, "http://") {
+			fmt.Println(ui.RedText("Error: Webhook URL must start with http:// or https://"))
+			os.Exit(1)
+		}
+
+		store := config.NewDiskStore()
+		if err := store.Update(func(cfg *config.Config) error {
+			cfg.Notifications.DiscordWebhookURL = discordWebhook
+			return nil
+		}); err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+			os.Exit(1)
+		}
+
+		if discordWebhook == "" {
+			fmt.Println(ui.GreenText("Discord notifications disabled."))
+		} else {
+			fmt.Println(ui.GreenText("Discord webhook saved."))
+		}
+		fmt.Println("Takes effect the next time the daemon starts.")
+	},
+}
+
+func init() {
+	notifyDiscordCmd.Flags().StringVar(&discordWebhook, "webhook", "", "Discord channel webhook URL")
+	notifyDiscordCmd.MarkFlagRequired("webhook")
+	notifyCmd.AddCommand(notifyDiscordCmd)
+	notifySlackCmd.Flags().StringVar(&slackWebhook, "webhook", "", "Slack incoming webhook URL")
+	notifySlackCmd.MarkFlagRequired("webhook")
+	notifyCmd.AddCommand(notifySlackCmd)
+	RootCmd.AddCommand(notifyCmd)
+}
+
+// buildNotifier returns the platform notifier, fanned out to any additional
+// destinations configured in cfg.
+func buildNotifier(cfg *config.Config) notify.Notifier {
+	notifiers := []notify.Notifier{notify.New()}
+	if cfg.Notifications.SlackWebhookURL != "" {
+		notifiers = append(notifiers, &notify.SlackNotifier{WebhookURL: cfg.Notifications.SlackWebhookURL})
+	}
+	if cfg.Notifications.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, &notify.DiscordNotifier{WebhookURL: cfg.Notifications.DiscordWebhookURL})
+	}
+	if cfg.Notifications.TelegramBotToken != "" && cfg.Notifications.TelegramChatID != "" {
+		notifiers = append(notifiers, &notify.TelegramNotifier{
+			BotToken: cfg.Notifications.TelegramBotToken,
+			ChatID:   cfg.Notifications.TelegramChatID,
+		})
+	}
+	if cfg.Webhook.URL != "" {
+		notifiers = append(notifiers, &notify.WebhookNotifier{URL: cfg.Webhook.URL, Headers: cfg.Webhook.Headers})
+	}
+	if len(notifiers) == 1 {
+		return notifiers[0]
+	}
+	return notify.NewMultiNotifier(notifiers...)
+}
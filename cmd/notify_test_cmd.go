@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/notify"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample notification to every configured destination",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.NewDiskStore().Load()
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+			os.Exit(1)
+		}
+		if err := sendTestNotification(os.Stdout, buildNotifier(cfg)); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	notifyCmd.AddCommand(notifyTestCmd)
+}
+
+// sendTestNotification sends a sample notification through each destination
+// of n separately and reports which ones succeeded.
+func sendTestNotification(w io.Writer, n notify.Notifier) error {
+	destinations := []notify.Notifier{n}
+	if multi, ok := n.(*notify.MultiNotifier); ok {
+		destinations = multi.Notifiers
+	}
+
+	failed := 0
+	for _, d := range destinations {
+		if err := d.Send(notify.Notification{
+			Title:   "jw Test",
+			Message: "Notification system is working!",
+			URL:     "https://github.com/baggiiiie/jw",
+			JobName: "jw",
+			Result:  "SUCCESS",
+		}); err != nil {
+			fmt.Fprintln(w, ui.RedText("✗ "+notifierName(d))+": "+err.Error())
+			failed++
+			continue
+		}
+		fmt.Fprintln(w, ui.GreenText("✓ "+notifierName(d)))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d notification destination(s) failed", failed, len(destinations))
+	}
+	return nil
+}
+
+func notifierName(n notify.Notifier) string {
+	switch n.(type) {
+	case *notify.MacNotifier:
+		return "Desktop (macOS)"
+	case *notify.LinuxNotifier:
+		return "Desktop (Linux)"
+	case *notify.SlackNotifier:
+		return "Slack"
+	case *notify.DiscordNotifier:
+		return "Discord"
+	case *notify.TelegramNotifier:
+		return "Telegram"
+	case *notify.WebhookNotifier:
+		return "Webhook"
+	}
+	return fmt.Sprintf("%T", n)
+}
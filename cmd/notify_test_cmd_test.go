@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"jenkins-monitor/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingTestNotifier struct{}
+
+func (failingTestNotifier) Send(notify.Notification) error {
+	return errors.New("webhook down")
+}
+
+func TestSendTestNotification(t *testing.T) {
+	rec := &recordingNotifier{}
+
+	var out bytes.Buffer
+	require.NoError(t, sendTestNotification(&out, rec))
+
+	calls := rec.getCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "jw Test", calls[0].Title)
+	assert.Equal(t, "Notification system is working!", calls[0].Message)
+	assert.Equal(t, "https://github.com/baggiiiie/jw", calls[0].URL)
+	assert.Contains(t, out.String(), "✓")
+}
+
+func TestSendTestNotification_ReportsEachDestination(t *testing.T) {
+	ok := &recordingNotifier{}
+	multi := notify.NewMultiNotifier(ok, failingTestNotifier{})
+
+	var out bytes.Buffer
+	err := sendTestNotification(&out, multi)
+	assert.ErrorContains(t, err, "1 of 2")
+	assert.Len(t, ok.getCalls(), 1)
+	assert.Contains(t, out.String(), "✓ *cmd.recordingNotifier")
+	assert.Contains(t, out.String(), "✗ cmd.failingTestNotifier")
+	assert.Contains(t, out.String(), "webhook down")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"jenkins-monitor/pkg/browser"
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open [job_url]",
+	Short: "Open a Jenkins job in the browser",
+	Long: `Open a Jenkins job in the default browser. Without a URL, pick one of the
+monitored jobs from a list.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.NewDiskStore().Load()
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+			os.Exit(1)
+		}
+
+		var jobURL string
+		if len(args) == 1 {
+			jobURL = args[0]
+			if !cfg.HasJob(jobURL) {
+				fmt.Println(ui.YellowText("Warning: job is not being monitored: " + jobURL))
+			}
+		} else {
+			jobURLs := make([]string, 0, len(cfg.Jobs))
+			for u := range cfg.Jobs {
+				jobURLs = append(jobURLs, u)
+			}
+			sort.Strings(jobURLs)
+			if len(jobURLs) == 0 {
+				fmt.Println(ui.YellowText("No jobs are being monitored."))
+				return
+			}
+			jobURL, err = selectJob(os.Stdin, os.Stdout, jobURLs)
+			if err != nil {
+				fmt.Println(ui.RedText("Error: " + err.Error()))
+				os.Exit(1)
+			}
+		}
+
+		if err := browser.Open(jobURL); err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error opening browser: %v", err)))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(openCmd)
+}
+
+// selectJob prints a numbered list of jobURLs to w and returns the one chosen
+// on r.
+func selectJob(r io.Reader, w io.Writer, jobURLs []string) (string, error) {
+	for i, jobURL := range jobURLs {
+		fmt.Fprintf(w, "%3d) %s\n", i+1, jobURL)
+	}
+	fmt.Fprintf(w, "Select a job [1-%d]: ", len(jobURLs))
+
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(jobURLs) {
+		return "", fmt.Errorf("invalid selection %q", answer)
+	}
+	return jobURLs[n-1], nil
+}
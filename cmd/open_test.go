@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectJob(t *testing.T) {
+	jobs := []string{"http://j/job/a", "http://j/job/b"}
+
+	var out bytes.Buffer
+	got, err := selectJob(strings.NewReader("2\n"), &out, jobs)
+	require.NoError(t, err)
+	assert.Equal(t, "http://j/job/b", got)
+	assert.Contains(t, out.String(), "  1) http://j/job/a")
+
+	for _, input := range []string{"0\n", "3\n", "b\n", ""} {
+		_, err := selectJob(strings.NewReader(input), &bytes.Buffer{}, jobs)
+		assert.Error(t, err, "input %q", input)
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var pauseAll bool
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [job_url]",
+	Short: "Temporarily stop monitoring a job without removing it",
+	Long: `Temporarily stop monitoring a job without removing it.
+
+The job stays in the config and can be resumed with 'jw resume'.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runSetPaused(args, pauseAll, true)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(pauseCmd)
+	pauseCmd.Flags().BoolVar(&pauseAll, "all", false, "Pause all monitored jobs")
+}
+
+func runSetPaused(args []string, all, paused bool) {
+	if (len(args) == 1) == all {
+		fmt.Println(ui.RedText("Error: specify either a job URL or --all"))
+		os.Exit(1)
+	}
+
+	changed, err := setJobsPaused(os.Stdout, config.NewDiskStore(), args, all, paused)
+	if err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+	if changed == 0 {
+		return
+	}
+
+	// Pausing never needs a daemon to be started; resuming does.
+	if !paused && signalDaemonReload() || paused && signalDaemonIfRunning() {
+		fmt.Println("Daemon signaled to reload the config.")
+	}
+}
+
+// setJobsPaused pauses or resumes the jobs at urls, or every job if all is
+// set, and returns how many changed state.
+func setJobsPaused(w io.Writer, store config.ConfigStore, urls []string, all, paused bool) (int, error) {
+	action, verb, state := "pause", "Paused", "already paused"
+	if !paused {
+		action, verb, state = "resume", "Resumed", "not paused"
+	}
+
+	var changed, missing, unchanged []string
+	err := store.Update(func(cfg *config.Config) error {
+		changed, missing, unchanged = nil, nil, nil
+		targets := urls
+		if all {
+			targets = make([]string, 0, len(cfg.Jobs))
+			for jobURL := range cfg.Jobs {
+				targets = append(targets, jobURL)
+			}
+			sort.Strings(targets)
+		}
+		for _, jobURL := range targets {
+			switch {
+			case !cfg.HasJob(jobURL):
+				missing = append(missing, jobURL)
+			case cfg.SetJobPaused(jobURL, paused):
+				changed = append(changed, jobURL)
+			default:
+				unchanged = append(unchanged, jobURL)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, jobURL := range missing {
+		fmt.Fprintln(w, ui.YellowText("Job not found in config: "+jobURL))
+	}
+	if !all {
+		for _, jobURL := range unchanged {
+			fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Job is %s: %s", state, jobURL)))
+		}
+	}
+	for _, jobURL := range changed {
+		fmt.Fprintln(w, ui.GreenText(verb+": "+jobURL))
+	}
+	if all && len(changed) == 0 {
+		fmt.Fprintln(w, ui.YellowText("No jobs to "+action+"."))
+	}
+	return len(changed), nil
+}
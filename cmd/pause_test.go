@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/monitor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJobsPaused(t *testing.T) {
+	a, b := "https://jenkins/job/a/1", "https://jenkins/job/b/2"
+	store := newMemStore(config.Job{URL: a}, config.Job{URL: b})
+
+	var out bytes.Buffer
+	changed, err := setJobsPaused(&out, store, []string{a}, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	cfg, _ := store.Load()
+	assert.True(t, cfg.Jobs[a].Paused)
+	assert.False(t, cfg.Jobs[b].Paused)
+
+	out.Reset()
+	changed, err = setJobsPaused(&out, store, []string{a}, false, true)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	assert.Contains(t, out.String(), "already paused")
+
+	out.Reset()
+	changed, err = setJobsPaused(&out, store, []string{"https://jenkins/job/missing/1"}, false, true)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	assert.Contains(t, out.String(), "not found")
+
+	changed, err = setJobsPaused(io.Discard, store, nil, true, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed, "only b was still running")
+
+	changed, err = setJobsPaused(io.Discard, store, nil, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+	cfg, _ = store.Load()
+	assert.False(t, cfg.Jobs[a].Paused)
+	assert.False(t, cfg.Jobs[b].Paused)
+
+	out.Reset()
+	changed, err = setJobsPaused(&out, store, nil, true, false)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	assert.Contains(t, out.String(), "No jobs to resume")
+}
+
+func TestReloadConfigAndJobs_Paused(t *testing.T) {
+	var mu sync.Mutex
+	polls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls[r.URL.Path]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	running, paused := server.URL+"/job/running", server.URL+"/job/paused"
+	store := newMemStore(config.Job{URL: running}, config.Job{URL: paused, Paused: true})
+	deps := DaemonDeps{Store: store, Token: "token", PollInterval: time.Hour}
+	logger := logging.TextLogger(io.Discard)
+	activeJobs := make(map[string]activeJob)
+	events := make(chan monitor.JobEvent, 10)
+	defer func() {
+		for _, active := range activeJobs {
+			close(active.stop)
+		}
+	}()
+
+	reloadConfigAndJobs(deps, logger, activeJobs, events)
+	assert.Contains(t, activeJobs, running)
+	assert.NotContains(t, activeJobs, paused, "paused jobs are not monitored")
+
+	// Pausing a running job stops its monitor.
+	runningStop := activeJobs[running].stop
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.SetJobPaused(running, true)
+		cfg.SetJobPaused(paused, false)
+		return nil
+	}))
+	reloadConfigAndJobs(deps, logger, activeJobs, events)
+	assert.NotContains(t, activeJobs, running)
+	assert.Contains(t, activeJobs, paused, "resumed job is monitored again")
+	select {
+	case <-runningStop:
+	default:
+		t.Fatal("monitor of the paused job was not stopped")
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return polls["/job/paused/api/json"] > 0
+	}, 2*time.Second, 5*time.Millisecond)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, cfg.Jobs, 2, "pausing keeps jobs in the config")
+}
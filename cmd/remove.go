@@ -2,8 +2,11 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"jenkins-monitor/pkg/config"
 	"jenkins-monitor/pkg/ui"
@@ -11,39 +14,199 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	removeAll     bool
+	removePattern string
+	removeYes     bool
+	removeDryRun  bool
+	removeJSON    bool
+	removeLabel   string
+)
+
 var removeCmd = &cobra.Command{
 	Use:   "remove [job_url]",
 	Short: "Remove a Jenkins job from monitoring",
-	Args:  cobra.ExactArgs(1),
+	Long: `Remove a Jenkins job from monitoring.
+
+Use --all to remove every job, --label to remove the jobs with a label, or
+--pattern to remove jobs whose URL matches a glob where * matches any sequence
+of characters (e.g. "*/job/feature-*"), ? a single character and [...] one of
+a set of characters.
+Bulk removals ask for confirmation unless --yes is given.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		store := config.NewDiskStore()
-		cfg, err := store.Load()
-		if err != nil {
-			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+		modes := 0
+		for _, set := range []bool{len(args) == 1, removeAll, removePattern != "", removeLabel != ""} {
+			if set {
+				modes++
+			}
+		}
+		if modes != 1 {
+			fmt.Println(ui.RedText("Error: specify exactly one of a job URL, --all, --pattern, or --label"))
 			os.Exit(1)
 		}
 
-		jobURL := args[0]
-		if !cfg.HasJob(jobURL) {
-			fmt.Println(ui.YellowText("Job not found in config: " + jobURL))
+		if removeDryRun {
+			previewRemoveCmd(args)
+			return
+		}
+		if len(args) == 1 {
+			removeSingleJob(args[0])
 			return
 		}
+		removeJobsBulk()
+	},
+}
 
-		cfg.RemoveJob(jobURL)
+func init() {
+	RootCmd.AddCommand(removeCmd)
+	removeCmd.Flags().BoolVar(&removeAll, "all", false, "Remove all monitored jobs")
+	removeCmd.Flags().StringVar(&removePattern, "pattern", "", "Remove jobs whose URL matches this glob")
+	removeCmd.Flags().StringVar(&removeLabel, "label", "", "Remove the jobs with this label")
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip the confirmation prompt")
+	removeCmd.Flags().BoolVarP(&removeDryRun, "dry-run", "n", false, "Show what would be removed without changing the config")
+	removeCmd.Flags().BoolVar(&removeJSON, "json", false, "With --dry-run, print the preview as JSON")
+}
+
+func removeSingleJob(jobURL string) {
+	store := config.NewDiskStore()
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
+	}
+
+	if !cfg.HasJob(jobURL) {
+		fmt.Println(ui.YellowText("Job not found in config: " + jobURL))
+		return
+	}
+
+	cfg.RemoveJob(jobURL)
+
+	if err := store.Save(cfg); err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.GreenText("Removed job from config: " + jobURL))
+
+	if signalDaemonReload() {
+		fmt.Println("Daemon signaled to stop monitoring the job.")
+	}
+}
 
-		if err := store.Save(cfg); err != nil {
-			fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+func removeJobsBulk() {
+	store := config.NewDiskStore()
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
+	}
+
+	matches, err := bulkRemoveMatches(cfg)
+	if err != nil {
+		fmt.Println(ui.RedText("Error: " + err.Error()))
+		os.Exit(1)
+	}
+	if len(matches) == 0 {
+		fmt.Println(ui.YellowText("No monitored jobs match."))
+		return
+	}
+
+	fmt.Printf("This will remove %d job(s):\n", len(matches))
+	for _, jobURL := range matches {
+		fmt.Println("  - " + jobURL)
+	}
+	if !removeYes && !confirm(os.Stdin, os.Stdout, "Continue?") {
+		fmt.Println("Aborted.")
+		return
+	}
+
+	if err := store.Update(func(cfg *config.Config) error {
+		applyRemove(cfg, matches)
+		return nil
+	}); err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(ui.GreenText(fmt.Sprintf("Removed %d job(s) from config.", len(matches))))
+
+	if signalDaemonReload() {
+		fmt.Println("Daemon signaled to stop monitoring the removed jobs.")
+	}
+}
+
+func previewRemoveCmd(args []string) {
+	store := config.NewDiskStore()
+	jobURLs := args
+	if len(args) == 0 {
+		cfg, err := store.Load()
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+			os.Exit(1)
+		}
+		if jobURLs, err = bulkRemoveMatches(cfg); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
 			os.Exit(1)
 		}
+	}
+
+	report, err := previewRemove(store, jobURLs)
+	if err == nil {
+		err = writeDryRunReport(os.Stdout, report, removeJSON)
+	}
+	if err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+}
 
-		fmt.Println(ui.GreenText("Removed job from config: " + jobURL))
+// bulkRemoveMatches returns the jobs selected by --label, --all or --pattern.
+func bulkRemoveMatches(cfg *config.Config) ([]string, error) {
+	if removeLabel != "" {
+		return cfg.JobsWithLabel(removeLabel), nil
+	}
+	pattern := removePattern
+	if removeAll {
+		pattern = "*"
+	}
+	if err := config.ValidateJobPattern(pattern); err != nil {
+		return nil, err
+	}
+	return cfg.MatchingJobs(pattern), nil
+}
 
-		if signalDaemonReload() {
-			fmt.Println("Daemon signaled to stop monitoring the job.")
+// applyRemove removes jobURLs from cfg, reporting which were monitored.
+func applyRemove(cfg *config.Config, jobURLs []string) (removed, missing []string) {
+	for _, jobURL := range jobURLs {
+		if !cfg.HasJob(jobURL) {
+			missing = append(missing, jobURL)
+			continue
 		}
-	},
+		cfg.RemoveJob(jobURL)
+		removed = append(removed, jobURL)
+	}
+	return removed, missing
 }
 
-func init() {
-	RootCmd.AddCommand(removeCmd)
+// previewRemove reports what removing jobURLs would do without saving
+// anything.
+func previewRemove(store config.ConfigStore, jobURLs []string) (dryRunReport, error) {
+	cfg, err := store.Load()
+	if err != nil {
+		return dryRunReport{}, err
+	}
+	removed, missing := applyRemove(cfg, jobURLs)
+	report := newDryRunReport(cfg)
+	report.Remove, report.Unchanged = removed, missing
+	return report, nil
+}
+
+// confirm prints a y/N prompt to w and reports whether the answer read from r was yes.
+func confirm(r io.Reader, w io.Writer, prompt string) bool {
+	fmt.Fprintf(w, "%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
 }
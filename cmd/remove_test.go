@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"maybe\n", false},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		got := confirm(strings.NewReader(tt.input), &out, "Continue?")
+		assert.Equal(t, tt.want, got, "input %q", tt.input)
+		assert.Equal(t, "Continue? [y/N]: ", out.String())
+	}
+}
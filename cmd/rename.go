@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old_url> <new_url>",
+	Short: "Move a monitored job to a new URL",
+	Long: `Move a monitored job to a new URL, e.g. after the Jenkins job was renamed or
+moved into a folder. The job keeps its start time, labels, note and history,
+and the daemon starts watching the new URL instead of the old one.`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := renameJob(os.Stdout, config.NewDiskStore(), args[0], args[1]); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		if signalDaemonReload() {
+			fmt.Println("Daemon signaled to reload the config.")
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(renameCmd)
+}
+
+// renameJob moves the job at oldURL to newURL in a single config update. Both
+// URLs are compared in their canonical form, so oldURL need not be spelled
+// exactly as it was added.
+func renameJob(w io.Writer, store config.ConfigStore, oldURL, newURL string) error {
+	newURL, err := jenkins.NormalizeJobURL(newURL)
+	if err != nil {
+		return err
+	}
+	if err := store.Update(func(cfg *config.Config) error {
+		key := monitoredJobURL(cfg, oldURL)
+		oldURL = key
+		return cfg.RenameJob(key, newURL, jenkins.JobURLFromBuildURL(key), jenkins.JobURLFromBuildURL(newURL))
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, ui.GreenText("Renamed "+oldURL+" to "+newURL))
+	return nil
+}
+
+// monitoredJobURL returns the key of the monitored job jobURL refers to, or
+// jobURL itself if none matches.
+func monitoredJobURL(cfg *config.Config, jobURL string) string {
+	if cfg.HasJob(jobURL) {
+		return jobURL
+	}
+	canonical, err := jenkins.NormalizeJobURL(jobURL)
+	if err != nil {
+		return jobURL
+	}
+	for key := range cfg.Jobs {
+		if normalized, err := jenkins.NormalizeJobURL(key); err == nil && normalized == canonical {
+			return key
+		}
+	}
+	return jobURL
+}
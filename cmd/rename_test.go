@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameJob(t *testing.T) {
+	oldURL := "https://jenkins/job/app/8"
+	newURL := "https://jenkins/job/team/job/app/8"
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMemStore(
+		config.Job{URL: oldURL, StartTime: start, Labels: []string{"team-a"}, Notes: "moved", BuildNumber: 8, ConsecutiveFailures: 2, CheckFailureCount: 3, LastPollTime: start},
+		config.Job{URL: "https://jenkins/job/other/1"},
+	)
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.RecordCompletion("https://jenkins/job/app", config.BuildRecord{Result: "SUCCESS"})
+		return nil
+	}))
+
+	var out bytes.Buffer
+	require.NoError(t, renameJob(&out, store, oldURL+"/", newURL+"/"))
+	assert.Contains(t, out.String(), "Renamed "+oldURL+" to "+newURL)
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, cfg.Jobs, oldURL)
+	require.Contains(t, cfg.Jobs, newURL)
+	job := cfg.Jobs[newURL]
+	assert.Equal(t, newURL, job.URL)
+	assert.True(t, start.Equal(job.StartTime))
+	assert.Equal(t, []string{"team-a"}, job.Labels)
+	assert.Equal(t, "moved", job.Notes)
+	assert.Zero(t, job.BuildNumber)
+	assert.Zero(t, job.ConsecutiveFailures)
+	assert.Zero(t, job.CheckFailureCount)
+	assert.True(t, job.LastPollTime.IsZero())
+	assert.NotContains(t, cfg.CompletionHistory, "https://jenkins/job/app")
+	assert.Len(t, cfg.CompletionHistory["https://jenkins/job/team/job/app"], 1)
+
+	assert.ErrorContains(t, renameJob(&out, store, oldURL, newURL), "job not found")
+	assert.ErrorContains(t, renameJob(&out, store, newURL, "https://jenkins/job/other/1"), "already monitored")
+	assert.ErrorContains(t, renameJob(&out, store, newURL, "jenkins/job/app/8"), "http:// or https://")
+}
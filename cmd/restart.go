@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"jenkins-monitor/pkg/pidfile"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const restartStopTimeout = 5 * time.Second
+
+// signalProcess and restartPollInterval are variables for testability.
+var (
+	signalProcess = func(pid int, sig syscall.Signal) error {
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return process.Signal(sig)
+	}
+	restartPollInterval = 250 * time.Millisecond
+)
+
+var errDaemonStopTimeout = errors.New("daemon did not stop in time")
+
+var restartForce bool
+
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the jenkins-monitor daemon",
+	Long: `Stop the running daemon and start a fresh one. If the daemon does not exit
+within 5 seconds the restart is abandoned, unless --force is given, in which
+case it is killed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if pid, running := pidfileIsDaemonRunning(); running {
+			fmt.Printf("Stopping daemon (PID: %d)", pid)
+			err := stopDaemon(os.Stdout, pid, restartStopTimeout, restartForce)
+			fmt.Println()
+			if err != nil {
+				fmt.Println(ui.RedText("Error: " + err.Error()))
+				os.Exit(1)
+			}
+		}
+
+		if err := startDaemonIfNeeded(); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		pid, _ := pidfileIsDaemonRunning()
+		fmt.Println(ui.GreenText(fmt.Sprintf("Daemon restarted (PID: %d).", pid)))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(restartCmd)
+	restartCmd.Flags().BoolVar(&restartForce, "force", false, "Send SIGKILL if the daemon does not stop after SIGTERM")
+}
+
+// stopDaemon sends SIGTERM to pid and waits up to timeout for the daemon to
+// exit, writing a progress dot to w on every poll. With force, a daemon that
+// outlives the timeout is sent SIGKILL.
+func stopDaemon(w io.Writer, pid int, timeout time.Duration, force bool) error {
+	if err := signalProcess(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			pidfile.Remove()
+			return nil
+		}
+		return fmt.Errorf("failed to send SIGTERM: %w", err)
+	}
+
+	if waitForDaemonExit(w, timeout) {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%w after %s (use --force to kill it)", errDaemonStopTimeout, timeout)
+	}
+
+	if err := signalProcess(pid, syscall.SIGKILL); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to send SIGKILL: %w", err)
+	}
+	if !waitForDaemonExit(w, timeout) {
+		return fmt.Errorf("%w even after SIGKILL", errDaemonStopTimeout)
+	}
+	// A killed daemon cannot clean up after itself.
+	pidfile.Remove()
+	return nil
+}
+
+// waitForDaemonExit polls until the daemon is gone or timeout elapses and
+// reports whether it exited.
+func waitForDaemonExit(w io.Writer, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if _, running := pidfileIsDaemonRunning(); !running {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		fmt.Fprint(w, ".")
+		time.Sleep(restartPollInterval)
+	}
+}
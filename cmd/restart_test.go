@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDaemon simulates a daemon process that exits after a number of polls
+// following SIGTERM, or immediately after SIGKILL.
+type fakeDaemon struct {
+	ticksToStop int // -1 means it ignores SIGTERM
+	signals     []syscall.Signal
+	terminated  bool
+}
+
+func stubDaemon(t *testing.T, d *fakeDaemon) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	origRunning, origSignal, origInterval := pidfileIsDaemonRunning, signalProcess, restartPollInterval
+	pidfileIsDaemonRunning = func() (int, bool) {
+		if d.terminated {
+			return 0, false
+		}
+		if len(d.signals) > 0 && d.ticksToStop >= 0 {
+			if d.ticksToStop == 0 {
+				d.terminated = true
+				return 0, false
+			}
+			d.ticksToStop--
+		}
+		return 42, true
+	}
+	signalProcess = func(pid int, sig syscall.Signal) error {
+		d.signals = append(d.signals, sig)
+		if sig == syscall.SIGKILL {
+			d.terminated = true
+		}
+		return nil
+	}
+	restartPollInterval = time.Millisecond
+	t.Cleanup(func() {
+		pidfileIsDaemonRunning, signalProcess, restartPollInterval = origRunning, origSignal, origInterval
+	})
+}
+
+func TestStopDaemon_StopsAfterTicks(t *testing.T) {
+	d := &fakeDaemon{ticksToStop: 3}
+	stubDaemon(t, d)
+
+	var out bytes.Buffer
+	require.NoError(t, stopDaemon(&out, 42, time.Second, false))
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, d.signals)
+	assert.Equal(t, "...", out.String())
+}
+
+func TestStopDaemon_Timeout(t *testing.T) {
+	d := &fakeDaemon{ticksToStop: -1}
+	stubDaemon(t, d)
+
+	err := stopDaemon(&bytes.Buffer{}, 42, 20*time.Millisecond, false)
+	assert.ErrorIs(t, err, errDaemonStopTimeout)
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM}, d.signals)
+}
+
+func TestStopDaemon_Force(t *testing.T) {
+	d := &fakeDaemon{ticksToStop: -1}
+	stubDaemon(t, d)
+
+	require.NoError(t, stopDaemon(&bytes.Buffer{}, 42, 20*time.Millisecond, true))
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, d.signals)
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var restoreBackup int
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the config from a backup",
+	Long: `Restore the config from one of the backups jw keeps before every write.
+Backup 1 is the newest. The current config becomes backup 1, so a restore can
+itself be undone with 'jw restore --backup 1'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.RestoreBackup(restoreBackup); err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error restoring backup: %v", err)))
+			os.Exit(1)
+		}
+		path, _ := config.GetBackupPath(restoreBackup)
+		fmt.Println(ui.GreenText("Restored config from " + path))
+
+		if signalDaemonIfRunning() {
+			fmt.Println("Daemon signaled to reload the config.")
+		}
+	},
+}
+
+func init() {
+	restoreCmd.Flags().IntVar(&restoreBackup, "backup", 1, "Backup number to restore, 1 being the newest")
+	RootCmd.AddCommand(restoreCmd)
+}
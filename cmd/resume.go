@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var resumeAll bool
+
+var resumeCmd = &cobra.Command{
+	Use:               "resume [job_url]",
+	Short:             "Resume monitoring a paused job",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeMonitoredJobs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runSetPaused(args, resumeAll, false)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(resumeCmd)
+	resumeCmd.Flags().BoolVar(&resumeAll, "all", false, "Resume all paused jobs")
+}
@@ -1,24 +1,71 @@
 package cmd
 
 import (
-	"time"
+	"os"
 
-	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/output"
+	"jenkins-monitor/pkg/ui"
 	"jenkins-monitor/pkg/upgrade"
 
 	"github.com/spf13/cobra"
 )
 
+var noColor bool
+
 var RootCmd = &cobra.Command{
 	Use:   "jw",
 	Short: "A Go-based Jenkins job monitor daemon",
-	Long:  `A daemon that monitors Jenkins jobs in the background and sends macOS notifications upon completion.`,
+	Long:  `A daemon that monitors Jenkins jobs in the background and sends desktop, Slack, Discord, Telegram or webhook notifications upon completion.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if noColor {
+			ui.SetEnabled(false)
+		}
+		// Started before the command runs so the lookup overlaps it rather
+		// than delaying the exit.
+		if !checksForUpgrade(cmd) {
+			return
+		}
+		if store, err := upgrade.NewFileStateStore(); err == nil {
+			upgrade.CheckInBackground(store)
+		}
+	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		store := config.NewDiskStore()
-		cfg, err := store.Load()
-		shouldCheck := time.Since(cfg.UpgradeState.LastChecked) > 24*time.Hour
-		if err == nil && shouldCheck {
-			upgrade.RunCheck(store, cfg)
+		if !checksForUpgrade(cmd) {
+			return
+		}
+		if store, err := upgrade.NewFileStateStore(); err == nil {
+			upgrade.NotifyIfOutdated(os.Stderr, store)
 		}
 	},
 }
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable coloured output (also set by NO_COLOR)")
+	RootCmd.PersistentFlags().String("output", "", "Output format for status, list, history and stats: json, table or plain")
+}
+
+// checksForUpgrade reports whether cmd should look for and point out a newer
+// release. Hidden commands (completion, the daemon, the native messaging host)
+// and JSON output are left alone.
+func checksForUpgrade(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden {
+			return false
+		}
+	}
+	if asJSON, err := cmd.Flags().GetBool("json"); err == nil && asJSON {
+		return false
+	}
+	return outputFormat(cmd, false) != output.FormatJSON
+}
+
+// outputFormat returns the format cmd should print in: json if the command's
+// own --json flag is set, otherwise --output. An empty format means the
+// command's usual output.
+func outputFormat(cmd *cobra.Command, asJSON bool) string {
+	if asJSON {
+		return output.FormatJSON
+	}
+	format, _ := cmd.Root().PersistentFlags().GetString("output")
+	return format
+}
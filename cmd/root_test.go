@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootCmd_NoColorFlag(t *testing.T) {
+	orig := ui.Enabled
+	ui.SetEnabled(true)
+	t.Cleanup(func() {
+		ui.SetEnabled(orig)
+		noColor = false
+	})
+
+	require.NoError(t, RootCmd.PersistentFlags().Set("no-color", "true"))
+	RootCmd.PersistentPreRun(RootCmd, nil)
+
+	assert.False(t, ui.Enabled)
+	assert.Equal(t, "plain", ui.GreenText("plain"))
+}
+
+func TestChecksForUpgrade(t *testing.T) {
+	newTree := func() (root, visible, hidden, withJSON *cobra.Command) {
+		root = &cobra.Command{Use: "jw"}
+		root.PersistentFlags().String("output", "", "")
+		visible = &cobra.Command{Use: "status"}
+		hidden = &cobra.Command{Use: "_native_messaging", Hidden: true}
+		withJSON = &cobra.Command{Use: "version"}
+		withJSON.Flags().Bool("json", false, "")
+		root.AddCommand(visible, hidden, withJSON)
+		return root, visible, hidden, withJSON
+	}
+
+	root, visible, hidden, withJSON := newTree()
+	assert.True(t, checksForUpgrade(visible))
+	assert.True(t, checksForUpgrade(withJSON))
+	assert.False(t, checksForUpgrade(hidden))
+
+	require.NoError(t, withJSON.Flags().Set("json", "true"))
+	assert.False(t, checksForUpgrade(withJSON))
+
+	require.NoError(t, root.PersistentFlags().Set("output", "json"))
+	assert.False(t, checksForUpgrade(visible))
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/output"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var statsJSON bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [job_url]",
+	Short: "Show completion history and success rates per job",
+	Long: `Show how often each job's monitored builds succeeded, how long they took on
+average and how the last one ended. Builds of the same job are grouped
+together; pass a job or build URL to show just that job.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeURLHints,
+	Run: func(cmd *cobra.Command, args []string) {
+		var jobURL string
+		if len(args) == 1 {
+			jobURL = args[0]
+		}
+		if err := runStats(os.Stdout, config.NewDiskStore(), jobURL, outputFormat(cmd, statsJSON)); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVarP(&statsJSON, "json", "j", false, "Print stats as JSON")
+}
+
+// jobStats summarises a job's completion history.
+type jobStats struct {
+	Job             string  `json:"job"`
+	Runs            int     `json:"runs"`
+	SuccessRate     float64 `json:"success_rate"`
+	AverageDuration float64 `json:"average_duration_seconds"`
+	LastResult      string  `json:"last_result"`
+}
+
+func runStats(w io.Writer, store config.ConfigStore, jobURL string, format string) error {
+	cfg, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	var stats []jobStats
+	if jobURL != "" {
+		key := jenkins.JobURLFromBuildURL(jobURL)
+		records, ok := cfg.CompletionHistory[key]
+		if !ok {
+			return fmt.Errorf("no completed builds recorded for %s", key)
+		}
+		stats = append(stats, computeStats(key, records))
+	} else {
+		for key, records := range cfg.CompletionHistory {
+			stats = append(stats, computeStats(key, records))
+		}
+		sort.Slice(stats, func(i, j int) bool { return stats[i].Job < stats[j].Job })
+	}
+
+	if format == "" {
+		format = output.FormatTable
+	}
+	if format == output.FormatTable && len(stats) == 0 {
+		fmt.Fprintln(w, "No completed builds recorded yet.")
+		return nil
+	}
+	renderer, err := output.New(format, w)
+	if err != nil {
+		return err
+	}
+
+	records := output.Records{Columns: []string{"Job", "Runs", "Success", "Avg Duration", "Last"}, Data: stats}
+	for _, s := range stats {
+		avg := (time.Duration(s.AverageDuration * float64(time.Second))).Round(time.Second)
+		records.Rows = append(records.Rows, []string{jobDisplayName(s.Job), fmt.Sprint(s.Runs), fmt.Sprintf("%.0f%%", s.SuccessRate), avg.String(), s.LastResult})
+	}
+	return output.Render(records, renderer)
+}
+
+// computeStats summarises records, which are ordered oldest first.
+func computeStats(job string, records []config.BuildRecord) jobStats {
+	s := jobStats{Job: job, Runs: len(records)}
+	if len(records) == 0 {
+		return s
+	}
+
+	var successes int
+	var total time.Duration
+	for _, r := range records {
+		if r.Result == "SUCCESS" {
+			successes++
+		}
+		total += r.Duration
+	}
+	s.SuccessRate = float64(successes) * 100 / float64(len(records))
+	s.AverageDuration = (total / time.Duration(len(records))).Seconds()
+	s.LastResult = records[len(records)-1].Result
+	return s
+}
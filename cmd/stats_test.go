@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/output"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedHistory(t *testing.T, history map[string][]config.BuildRecord) *config.MemoryStore {
+	t.Helper()
+	store := config.NewMemoryStore()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.CompletionHistory = history
+		return nil
+	}))
+	return store
+}
+
+func TestComputeStats(t *testing.T) {
+	records := []config.BuildRecord{
+		{Result: "SUCCESS", Duration: 2 * time.Minute},
+		{Result: "FAILURE", Duration: 4 * time.Minute},
+		{Result: "SUCCESS", Duration: 3 * time.Minute},
+		{Result: "ABORTED", Duration: 3 * time.Minute},
+	}
+
+	s := computeStats("https://j/job/a", records)
+	assert.Equal(t, 4, s.Runs)
+	assert.InDelta(t, 50.0, s.SuccessRate, 0.001)
+	assert.InDelta(t, 180.0, s.AverageDuration, 0.001)
+	assert.Equal(t, "ABORTED", s.LastResult)
+
+	s = computeStats("https://j/job/b", []config.BuildRecord{
+		{Result: "SUCCESS"}, {Result: "SUCCESS"}, {Result: "FAILURE"},
+	})
+	assert.InDelta(t, 66.667, s.SuccessRate, 0.001)
+}
+
+func TestRunStats_JSON(t *testing.T) {
+	store := seedHistory(t, map[string][]config.BuildRecord{
+		"https://j/job/b": {{Result: "FAILURE", Duration: time.Minute}},
+		"https://j/job/a": {{Result: "SUCCESS", Duration: time.Minute}, {Result: "SUCCESS", Duration: 3 * time.Minute}},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, runStats(&buf, store, "", output.FormatJSON))
+
+	var got []jobStats
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, jobStats{Job: "https://j/job/a", Runs: 2, SuccessRate: 100, AverageDuration: 120, LastResult: "SUCCESS"}, got[0])
+	assert.Equal(t, jobStats{Job: "https://j/job/b", Runs: 1, SuccessRate: 0, AverageDuration: 60, LastResult: "FAILURE"}, got[1])
+}
+
+func TestRunStats_SingleJobByBuildURL(t *testing.T) {
+	store := seedHistory(t, map[string][]config.BuildRecord{
+		"https://j/job/a": {{Result: "SUCCESS", Duration: 90 * time.Second}},
+		"https://j/job/b": {{Result: "FAILURE"}},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, runStats(&buf, store, "https://j/job/a/42/", ""))
+	assert.Contains(t, buf.String(), "JOB")
+	assert.Contains(t, buf.String(), "100%")
+	assert.Contains(t, buf.String(), "1m30s")
+	assert.NotContains(t, buf.String(), "FAILURE")
+
+	assert.Error(t, runStats(&bytes.Buffer{}, store, "https://j/job/missing", ""))
+}
@@ -2,82 +2,344 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/output"
 	"jenkins-monitor/pkg/pidfile"
 	"jenkins-monitor/pkg/ui"
 	"os"
+	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
-var tui bool
+var (
+	tui            bool
+	statusJSON     bool
+	statusWatch    bool
+	statusInterval time.Duration
+	statusVerbose  bool
+	// statusIgnoreChecksum is shared by jw status and jw list.
+	statusIgnoreChecksum bool
+)
+
+type statusOutput struct {
+	DaemonRunning bool        `json:"daemon_running"`
+	DaemonPID     int         `json:"daemon_pid,omitempty"`
+	Jobs          []statusJob `json:"jobs"`
+}
+
+type statusJob struct {
+	URL                 string    `json:"url"`
+	StartTime           time.Time `json:"start_time"`
+	MonitoredForSeconds int64     `json:"monitored_for_seconds"`
+	LastCheckFailed     bool      `json:"last_check_failed"`
+	Notes               string    `json:"notes,omitempty"`
+	BuildNumber         int       `json:"build_number,omitempty"`
+	LastPollTime        time.Time `json:"last_poll_time,omitzero"`
+	CheckFailureCount   int       `json:"check_failure_count,omitempty"`
+	PollIntervalSeconds int64     `json:"poll_interval_seconds,omitempty"`
+	Labels              []string  `json:"labels,omitempty"`
+}
 
 var statusCmd = &cobra.Command{
 	Use:     "status",
 	Aliases: []string{"st"},
 	Short:   "Get the status of the jenkins-monitor daemon",
 	Run: func(cmd *cobra.Command, args []string) {
+		format := outputFormat(cmd, statusJSON)
+		if format != "" && (tui || statusWatch) {
+			fmt.Println(ui.RedText("Error: --json/--output can't be combined with --tui or --watch"))
+			os.Exit(1)
+		}
 		if tui {
 			runTUI()
 			return
 		}
-		if pid, running := pidfile.IsDaemonRunning(); running {
-			fmt.Println(ui.GreenText(fmt.Sprintf("Daemon running (PID: %d)", pid)))
-		} else {
-			fmt.Println(ui.RedText("Daemon not running."))
+		if format != "" {
+			runStatusOutput(format)
+			return
+		}
+		if statusWatch {
+			if statusInterval <= 0 {
+				fmt.Println(ui.RedText("Error: --interval must be positive"))
+				os.Exit(1)
+			}
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(interrupt)
+			ticker := time.NewTicker(statusInterval)
+			defer ticker.Stop()
+			if err := watchStatus(os.Stdout, ticker.C, interrupt, loadStatusSnapshot, statusVerbose); err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
+				os.Exit(1)
+			}
 			return
 		}
 
-		store := config.NewDiskStore()
-		cfg, err := store.Load()
+		pid, running := pidfile.IsDaemonRunning()
+		if !running {
+			writeStatus(os.Stdout, pid, running, nil, time.Now(), statusVerbose)
+			return
+		}
+		cfg, err := statusStore().Load()
 		if err != nil {
 			fmt.Println(ui.RedText(fmt.Sprintf("Error loading config: %v", err)))
 			os.Exit(1)
 		}
+		writeStatus(os.Stdout, pid, running, cfg, time.Now(), statusVerbose)
+	},
+}
 
-		if len(cfg.Jobs) == 0 {
-			fmt.Println("Not monitoring any jobs.")
-		} else {
-			fmt.Printf("Monitoring %d job(s):\n", len(cfg.Jobs))
-			for _, job := range cfg.Jobs {
-				duration := time.Since(job.StartTime)
-				urlParts := strings.Split(job.URL, "/")
-				url := strings.Join(urlParts[len(urlParts)-3:], "/")
-				line := fmt.Sprintf("  - %s (monitored for %s)", url, formatDuration(duration))
-				if job.LastCheckFailed {
-					fmt.Println(ui.YellowText(line))
-				} else {
-					fmt.Println(line)
-				}
+// writeStatus prints the daemon state and, if it is running, the monitored
+// jobs and recent history. verbose adds each job's last poll, failed checks
+// and non-default poll interval.
+func writeStatus(w io.Writer, pid int, running bool, cfg *config.Config, now time.Time, verbose bool) {
+	if !running {
+		fmt.Fprintln(w, ui.RedText("Daemon not running."))
+		return
+	}
+	fmt.Fprintln(w, ui.GreenText(fmt.Sprintf("Daemon running (PID: %d)", pid)))
+
+	if len(cfg.Jobs) == 0 {
+		fmt.Fprintln(w, "Not monitoring any jobs.")
+	} else {
+		fmt.Fprintf(w, "Monitoring %d job(s):\n", len(cfg.Jobs))
+		for _, job := range cfg.Jobs {
+			duration := now.Sub(job.StartTime)
+			urlParts := strings.Split(job.URL, "/")
+			url := strings.Join(urlParts[len(urlParts)-3:], "/")
+			monitored := "monitored for " + formatDuration(duration)
+			if job.BuildNumber > 0 {
+				monitored = fmt.Sprintf("build #%d, %s", job.BuildNumber, monitored)
+			}
+			line := fmt.Sprintf("  - %s (%s)", url, monitored)
+			if job.Paused {
+				line += " [paused]"
+			}
+			if job.Notes != "" {
+				line += " - " + config.TruncateNote(job.Notes)
+			}
+			if job.LastCheckFailed {
+				fmt.Fprintln(w, ui.YellowText(line))
+			} else {
+				fmt.Fprintln(w, line)
+			}
+			if verbose {
+				fmt.Fprintln(w, ui.MutedText("    "+verboseJobDetails(job, now)))
 			}
 		}
+	}
 
-		if len(cfg.History) > 0 {
-			fmt.Printf("\nHistory (%d):\n", len(cfg.History))
-			sorted := make([]config.HistoryEntry, len(cfg.History))
-			copy(sorted, cfg.History)
-			sort.Slice(sorted, func(i, j int) bool {
-				return sorted[i].FinishedTime.After(sorted[j].FinishedTime)
-			})
-			for _, entry := range sorted {
-				urlParts := strings.Split(entry.URL, "/")
-				url := strings.Join(urlParts[len(urlParts)-3:], "/")
-				ago := formatDuration(time.Since(entry.FinishedTime))
-				line := fmt.Sprintf("  - %s [%s] (finished %s ago)", url, entry.Result, ago)
-				switch entry.Result {
-				case "SUCCESS":
-					fmt.Println(ui.GreenText(line))
-				case "FAILURE":
-					fmt.Println(ui.RedText(line))
-				default:
-					fmt.Println(ui.MutedText(line))
-				}
+	if len(cfg.History) > 0 {
+		fmt.Fprintf(w, "\nHistory (%d):\n", len(cfg.History))
+		sorted := make([]config.HistoryEntry, len(cfg.History))
+		copy(sorted, cfg.History)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].FinishedTime.After(sorted[j].FinishedTime)
+		})
+		for _, entry := range sorted {
+			urlParts := strings.Split(entry.URL, "/")
+			url := strings.Join(urlParts[len(urlParts)-3:], "/")
+			ago := formatDuration(now.Sub(entry.FinishedTime))
+			line := fmt.Sprintf("  - %s [%s] (finished %s ago)", url, entry.Result, ago)
+			switch entry.Result {
+			case "SUCCESS":
+				fmt.Fprintln(w, ui.GreenText(line))
+			case "FAILURE":
+				fmt.Fprintln(w, ui.RedText(line))
+			default:
+				fmt.Fprintln(w, ui.MutedText(line))
 			}
 		}
-	},
+	}
+}
+
+// statusSnapshot is what one refresh of jw status --watch shows.
+type statusSnapshot struct {
+	pid     int
+	running bool
+	cfg     *config.Config
+}
+
+func loadStatusSnapshot() (statusSnapshot, error) {
+	pid, running := pidfile.IsDaemonRunning()
+	cfg, err := statusStore().Load()
+	if err != nil {
+		return statusSnapshot{}, err
+	}
+	return statusSnapshot{pid: pid, running: running, cfg: cfg}, nil
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus redraws the status in place now and on every tick until no jobs
+// remain and the daemon has stopped, or a signal arrives on interrupt.
+func watchStatus(w io.Writer, ticks <-chan time.Time, interrupt <-chan os.Signal, load func() (statusSnapshot, error), verbose bool) error {
+	for {
+		snap, err := load()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		fmt.Fprint(w, clearScreen)
+		writeStatus(w, snap.pid, snap.running, snap.cfg, now, verbose)
+		fmt.Fprintf(w, "\n%s\n", ui.MutedText("Last updated: "+now.Format(time.DateTime)))
+		if !snap.running && len(snap.cfg.Jobs) == 0 {
+			return nil
+		}
+
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticks:
+		}
+	}
+}
+
+// runStatusOutput prints the daemon and job status in format, one of the
+// output formats.
+func runStatusOutput(format string) {
+	store := statusStore()
+	cfg, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	pid, running := pidfile.IsDaemonRunning()
+	if err := writeStatusOutput(os.Stdout, buildStatusOutput(pid, running, cfg, time.Now()), format, statusVerbose); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing status: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func buildStatusOutput(pid int, running bool, cfg *config.Config, now time.Time) statusOutput {
+	out := statusOutput{
+		DaemonRunning: running,
+		Jobs:          make([]statusJob, 0, len(cfg.Jobs)),
+	}
+	if running {
+		out.DaemonPID = pid
+	}
+	for _, job := range cfg.Jobs {
+		out.Jobs = append(out.Jobs, statusJob{
+			URL:                 job.URL,
+			StartTime:           job.StartTime,
+			MonitoredForSeconds: int64(now.Sub(job.StartTime).Seconds()),
+			LastCheckFailed:     job.LastCheckFailed,
+			Notes:               job.Notes,
+			BuildNumber:         job.BuildNumber,
+			LastPollTime:        job.LastPollTime,
+			CheckFailureCount:   job.CheckFailureCount,
+			PollIntervalSeconds: int64(job.PollInterval / time.Second),
+			Labels:              job.Labels,
+		})
+	}
+	sort.Slice(out.Jobs, func(i, j int) bool {
+		return out.Jobs[i].URL < out.Jobs[j].URL
+	})
+	return out
+}
+
+// writeStatusOutput renders out in format. JSON carries the daemon state
+// alongside the jobs; table and plain list just the jobs, with verbose adding
+// the last poll, failed checks and poll interval columns.
+func writeStatusOutput(w io.Writer, out statusOutput, format string, verbose bool) error {
+	renderer, err := output.New(format, w)
+	if err != nil {
+		return err
+	}
+	records := output.Records{Columns: []string{"URL", "Status", "Build", "Monitored For", "Note"}, Data: out}
+	if verbose {
+		records.Columns = append(records.Columns, "Last Poll", "Failed Checks", "Interval", "Labels")
+	}
+	now := time.Now()
+	for _, job := range out.Jobs {
+		status := "OK"
+		if job.LastCheckFailed {
+			status = "Failing"
+		}
+		monitored := formatDuration(time.Duration(job.MonitoredForSeconds) * time.Second)
+		row := []string{job.URL, status, formatBuildNumber(job.BuildNumber), monitored, config.TruncateNote(job.Notes)}
+		if verbose {
+			interval := "-"
+			if d := time.Duration(job.PollIntervalSeconds) * time.Second; d > 0 && d != defaultPollInterval() {
+				interval = d.String()
+			}
+			labels := strings.Join(job.Labels, ",")
+			if labels == "" {
+				labels = "-"
+			}
+			row = append(row, formatAgo(job.LastPollTime, now), strconv.Itoa(job.CheckFailureCount), interval, labels)
+		}
+		records.Rows = append(records.Rows, row)
+	}
+	return output.Render(records, renderer)
+}
+
+// verboseJobDetails describes when job was last polled, how many of its
+// checks failed and, if it has them, its own poll interval and labels.
+func verboseJobDetails(job config.Job, now time.Time) string {
+	details := fmt.Sprintf("last poll %s, %d failed check(s)", formatAgo(job.LastPollTime, now), job.CheckFailureCount)
+	if job.ConsecutiveFailures > 0 {
+		details += fmt.Sprintf(" (%d in a row)", job.ConsecutiveFailures)
+	}
+	if job.PollInterval > 0 && job.PollInterval != defaultPollInterval() {
+		details += ", polled every " + job.PollInterval.String()
+	}
+	if len(job.Labels) > 0 {
+		details += ", labels: " + strings.Join(job.Labels, ", ")
+	}
+	return details
+}
+
+// defaultPollInterval is the interval jobs without their own are polled at,
+// as far as this process's environment tells.
+func defaultPollInterval() time.Duration {
+	fromEnv, _ := monitor.PollIntervalFromEnv()
+	return monitor.ResolvePollInterval(0, fromEnv)
+}
+
+// formatAgo renders how long before now t was, like "3s ago" or "5m ago", or
+// "never" for the zero time.
+func formatAgo(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := max(now.Sub(t), 0)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+}
+
+// statusStore is the config store jw status and jw list read, which with
+// --ignore-checksum accepts a config whose checksum does not match.
+func statusStore() *config.DiskStore {
+	return &config.DiskStore{IgnoreChecksum: statusIgnoreChecksum}
+}
+
+// formatBuildNumber renders a Jenkins build number as "#123", or "-" if it is
+// not known yet.
+func formatBuildNumber(n int) string {
+	if n <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("#%d", n)
 }
 
 func formatDuration(d time.Duration) string {
@@ -99,4 +361,9 @@ func formatDuration(d time.Duration) string {
 func init() {
 	RootCmd.AddCommand(statusCmd)
 	statusCmd.Flags().BoolVar(&tui, "tui", false, "Display status in a TUI table")
+	statusCmd.Flags().BoolVarP(&statusJSON, "json", "j", false, "Print daemon and job status as JSON (no colour)")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Redraw the status in place until interrupted or nothing is left to monitor")
+	statusCmd.Flags().BoolVar(&statusIgnoreChecksum, "ignore-checksum", false, "Read the config even if its checksum does not match (see jw clean --repair)")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "Refresh interval for --watch")
+	statusCmd.Flags().BoolVarP(&statusVerbose, "verbose", "v", false, "Show each job's last poll, failed checks and poll interval")
 }
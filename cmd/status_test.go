@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/output"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStatusOutput_JSON(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Jobs: map[string]config.Job{
+		"https://jenkins/job/b/2": {URL: "https://jenkins/job/b/2", StartTime: now.Add(-90 * time.Second), LastCheckFailed: true},
+		"https://jenkins/job/a/1": {URL: "https://jenkins/job/a/1", StartTime: now.Add(-time.Hour)},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStatusOutput(&buf, buildStatusOutput(4242, true, cfg, now), output.FormatJSON, false))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded), "output should be valid JSON")
+	assert.Equal(t, true, decoded["daemon_running"])
+	assert.EqualValues(t, 4242, decoded["daemon_pid"])
+
+	var out statusOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Jobs, 2)
+	assert.Equal(t, "https://jenkins/job/a/1", out.Jobs[0].URL)
+	assert.EqualValues(t, 3600, out.Jobs[0].MonitoredForSeconds)
+	assert.False(t, out.Jobs[0].LastCheckFailed)
+	assert.Equal(t, "https://jenkins/job/b/2", out.Jobs[1].URL)
+	assert.EqualValues(t, 90, out.Jobs[1].MonitoredForSeconds)
+	assert.True(t, out.Jobs[1].LastCheckFailed)
+}
+
+func TestWriteStatusOutput_JSON_DaemonNotRunning(t *testing.T) {
+	cfg := &config.Config{Jobs: map[string]config.Job{}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStatusOutput(&buf, buildStatusOutput(0, false, cfg, time.Now()), output.FormatJSON, false))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, false, decoded["daemon_running"])
+	assert.NotContains(t, decoded, "daemon_pid")
+	assert.Equal(t, []any{}, decoded["jobs"])
+}
+
+func TestWriteStatusOutput_Table(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Jobs: map[string]config.Job{
+		"https://jenkins/job/a/1": {URL: "https://jenkins/job/a/1", StartTime: now.Add(-time.Hour), LastCheckFailed: true, Notes: "release", BuildNumber: 1},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeStatusOutput(&buf, buildStatusOutput(4242, true, cfg, now), output.FormatTable, false))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"URL", "STATUS", "BUILD", "MONITORED", "FOR", "NOTE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"https://jenkins/job/a/1", "Failing", "#1", "1h", "0m", "release"}, strings.Fields(lines[1]))
+
+	assert.Error(t, writeStatusOutput(&buf, buildStatusOutput(4242, true, cfg, now), "yaml", false))
+}
+
+func TestWatchStatus_RefreshesUntilNothingLeft(t *testing.T) {
+	now := time.Now()
+	snapshots := []statusSnapshot{
+		{pid: 4242, running: true, cfg: &config.Config{Jobs: map[string]config.Job{
+			"https://jenkins/job/app/7": {URL: "https://jenkins/job/app/7", StartTime: now.Add(-5 * time.Minute)},
+		}}},
+		{cfg: &config.Config{Jobs: map[string]config.Job{}}},
+	}
+	load := func() (statusSnapshot, error) {
+		snap := snapshots[0]
+		snapshots = snapshots[1:]
+		return snap, nil
+	}
+
+	ticks := make(chan time.Time, 1)
+	ticks <- now
+	var buf bytes.Buffer
+	require.NoError(t, watchStatus(&buf, ticks, make(chan os.Signal), load, false))
+
+	frames := strings.Split(buf.String(), clearScreen)
+	require.Len(t, frames, 3, "two redraws, each starting with a screen clear")
+	assert.Contains(t, frames[1], "Daemon running (PID: 4242)")
+	assert.Contains(t, frames[1], "Monitoring 1 job(s):")
+	assert.Contains(t, frames[1], "  - job/app/7 (monitored for 5m)")
+	assert.Contains(t, frames[1], "Last updated: ")
+	assert.Contains(t, frames[2], "Daemon not running.")
+	assert.Empty(t, snapshots)
+}
+
+func TestWatchStatus_StopsOnInterrupt(t *testing.T) {
+	load := func() (statusSnapshot, error) {
+		return statusSnapshot{pid: 1, running: true, cfg: &config.Config{Jobs: map[string]config.Job{}}}, nil
+	}
+	interrupt := make(chan os.Signal, 1)
+	interrupt <- os.Interrupt
+
+	var buf bytes.Buffer
+	require.NoError(t, watchStatus(&buf, make(chan time.Time), interrupt, load, false))
+	assert.Equal(t, 1, strings.Count(buf.String(), clearScreen))
+}
+
+func TestWriteStatus_Verbose(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Jobs: map[string]config.Job{
+		"https://jenkins/job/app/7": {
+			URL:                 "https://jenkins/job/app/7",
+			StartTime:           now.Add(-5 * time.Minute),
+			LastPollTime:        now.Add(-3 * time.Second),
+			CheckFailureCount:   2,
+			ConsecutiveFailures: 1,
+			PollInterval:        time.Minute,
+			Labels:              []string{"team-a", "release"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	writeStatus(&buf, 4242, true, cfg, now, false)
+	assert.NotContains(t, buf.String(), "last poll")
+
+	buf.Reset()
+	writeStatus(&buf, 4242, true, cfg, now, true)
+	assert.Contains(t, buf.String(), "    last poll 3s ago, 2 failed check(s) (1 in a row), polled every 1m0s, labels: team-a, release")
+
+	buf.Reset()
+	require.NoError(t, writeStatusOutput(&buf, buildStatusOutput(4242, true, cfg, now), output.FormatTable, true))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "LAST POLL")
+	assert.Contains(t, lines[0], "FAILED CHECKS")
+	fields := strings.Fields(lines[1])
+	assert.Equal(t, []string{"2", "1m0s", "team-a,release"}, fields[len(fields)-3:])
+}
+
+func TestFormatAgo(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "never", formatAgo(time.Time{}, now))
+	assert.Equal(t, "3s ago", formatAgo(now.Add(-3*time.Second), now))
+	assert.Equal(t, "0s ago", formatAgo(now.Add(time.Second), now))
+	assert.Equal(t, "5m ago", formatAgo(now.Add(-5*time.Minute), now))
+	assert.Equal(t, "2h ago", formatAgo(now.Add(-2*time.Hour), now))
+	assert.Equal(t, "3d ago", formatAgo(now.Add(-72*time.Hour), now))
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"jenkins-monitor/pkg/paths"
+	"jenkins-monitor/pkg/systemd"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var systemdCmd = &cobra.Command{
+	Use:   "systemd",
+	Short: "Manage the systemd user unit for the daemon on Linux",
+}
+
+var systemdInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Write the systemd user unit",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := systemdTarget()
+		if err == nil {
+			var exe string
+			if exe, err = os.Executable(); err == nil {
+				err = installSystemdUnit(os.Stdout, path, exe)
+			}
+		}
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+var systemdUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Disable and remove the systemd user unit",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := systemdTarget()
+		if err == nil {
+			err = uninstallSystemdUnit(os.Stdout, path, systemctlDisable)
+		}
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	systemdCmd.AddCommand(systemdInstallCmd, systemdUninstallCmd)
+	RootCmd.AddCommand(systemdCmd)
+}
+
+// systemdTarget returns the unit file path, failing off Linux.
+func systemdTarget() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errors.New("systemd is only available on Linux")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return systemdUnitPath(home), nil
+}
+
+// systemdUnitPath returns where systemd looks for jw's user unit.
+func systemdUnitPath(home string) string {
+	return filepath.Join(paths.SystemdUserDir(home), systemd.UnitName)
+}
+
+// installSystemdUnit writes the unit for exe to path and tells w how to
+// enable it.
+func installSystemdUnit(w io.Writer, path, exe string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(systemd.GenerateUserUnit(exe, paths.OverrideDir())), 0o644); err != nil {
+		return fmt.Errorf("writing unit: %w", err)
+	}
+	fmt.Fprintln(w, ui.GreenText("Wrote "+path))
+	fmt.Fprintln(w, "Enable it with:")
+	fmt.Fprintln(w, "  systemctl --user enable --now jw")
+	return nil
+}
+
+// uninstallSystemdUnit disables and deletes the unit at path. A failed
+// disable, usually because the unit was never enabled, is reported but does
+// not stop the removal.
+func uninstallSystemdUnit(w io.Writer, path string, disable func() error) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return errors.New("systemd unit is not installed")
+	}
+	if err := disable(); err != nil {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("systemctl --user disable --now jw: %v", err)))
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing unit: %w", err)
+	}
+	fmt.Fprintln(w, ui.GreenText("Removed "+path))
+	fmt.Fprintln(w, ui.MutedText("Run systemctl --user daemon-reload to forget it"))
+	return nil
+}
+
+func systemctlDisable() error {
+	return exec.Command("systemctl", "--user", "disable", "--now", "jw").Run()
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemdUnitPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/home", ".config", "systemd", "user", "jw.service"), systemdUnitPath("/home"))
+
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	assert.Equal(t, filepath.Join("/xdg", "systemd", "user", "jw.service"), systemdUnitPath("/home"))
+}
+
+func TestSystemdInstallUninstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "systemd", "user", "jw.service")
+
+	var out bytes.Buffer
+	require.NoError(t, installSystemdUnit(&out, path, "/usr/local/bin/jw"))
+	assert.Contains(t, out.String(), path)
+	assert.Contains(t, out.String(), "systemctl --user enable --now jw")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ExecStart=/usr/local/bin/jw _start_jw_daemon")
+
+	disabled := false
+	out.Reset()
+	require.NoError(t, uninstallSystemdUnit(&out, path, func() error {
+		disabled = true
+		return errors.New("not enabled")
+	}))
+	assert.True(t, disabled)
+	assert.Contains(t, out.String(), "not enabled")
+	assert.Contains(t, out.String(), "Removed "+path)
+	assert.NoFileExists(t, path)
+
+	err = uninstallSystemdUnit(&out, path, func() error { return nil })
+	assert.ErrorContains(t, err, "not installed")
+}
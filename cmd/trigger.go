@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const triggerQueueTimeout = 2 * time.Minute
+
+var (
+	triggerParams []string
+	triggerWatch  bool
+)
+
+var triggerCmd = &cobra.Command{
+	Use:   "trigger [job_url]",
+	Short: "Start a build of a Jenkins job",
+	Long: `Start a build of a Jenkins job. Use --parameter KEY=VALUE (repeatable) for
+parameterised builds and --watch to add the new build to background monitoring.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeURLHints,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := config.GetCredentials()
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+
+		params, err := parseBuildParams(triggerParams)
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+
+		var baseURL string
+		if creds, err := config.LoadCredentials(); err == nil {
+			baseURL = creds.BaseURL
+		}
+		jobURL := normalizeJobURL(args[0], baseURL)
+		if !strings.HasPrefix(jobURL, "http://") && !strings.HasPrefix(jobURL, "https://") {
+			fmt.Println(ui.RedText("Error: Job URL must start with http:// or https://"))
+			os.Exit(1)
+		}
+
+		queueURL, err := jenkins.TriggerBuild(jobURL, token, params)
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error triggering build: %v", err)))
+			os.Exit(1)
+		}
+		fmt.Println(ui.GreenText("Build triggered: " + jenkins.JobURLFromBuildURL(jobURL)))
+
+		if !triggerWatch {
+			return
+		}
+
+		spinner := ui.NewSpinner("Waiting for the build to leave the queue...")
+		spinner.Start()
+		buildURL, err := jenkins.WaitForQueuedBuild(queueURL, token, 2*time.Second, triggerQueueTimeout)
+		spinner.Stop()
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+			os.Exit(1)
+		}
+
+		added, err := addJobs(os.Stdout, config.NewDiskStore(), []string{buildURL}, jobOptions{})
+		if err != nil {
+			fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+			os.Exit(1)
+		}
+		if added > 0 && signalDaemonReload() {
+			fmt.Println("Daemon signaled to monitor the new build.")
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(triggerCmd)
+	triggerCmd.Flags().StringArrayVarP(&triggerParams, "parameter", "p", nil, "Build parameter as KEY=VALUE (repeatable)")
+	triggerCmd.Flags().BoolVarP(&triggerWatch, "watch", "w", false, "Add the triggered build to monitoring")
+}
+
+// parseBuildParams turns KEY=VALUE flag values into a parameter map.
+func parseBuildParams(raw []string) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string, len(raw))
+	for _, p := range raw {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid parameter %q: expected KEY=VALUE", p)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
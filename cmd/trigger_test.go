@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBuildParams(t *testing.T) {
+	params, err := parseBuildParams([]string{"BRANCH=main", "ARGS=a=b", "EMPTY="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BRANCH": "main", "ARGS": "a=b", "EMPTY": ""}, params)
+
+	params, err = parseBuildParams(nil)
+	require.NoError(t, err)
+	assert.Nil(t, params)
+
+	_, err = parseBuildParams([]string{"NOVALUE"})
+	assert.Error(t, err)
+	_, err = parseBuildParams([]string{"=x"})
+	assert.Error(t, err)
+}
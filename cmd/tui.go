@@ -3,19 +3,510 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
+	"jenkins-monitor/pkg/browser"
 	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
 	"jenkins-monitor/pkg/ui"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+const tuiHelpText = `Keys:
+  Enter  Show or hide details of the selected job
+  Esc    Hide the details
+  o      Open the selected job in the browser (or double-click it)
+  d      Stop monitoring the selected job
+  r      Refresh now
+  s      Sort by the next column (or click a header)
+  S      Reverse the sort order
+  q      Quit
+  ?      Show this help`
+
+// Sortable TUI table columns, in the order s cycles through them.
+const (
+	sortByURL = iota
+	sortByStatus
+	sortByDuration
+	sortColumns
+)
+
+var tuiHeaders = []string{"Job URL", "Status", "Monitored For", "Build", "Note"}
+
+const tuiFooterText = "[yellow]Enter[-] details  [yellow]o[-] open  [yellow]d[-] remove  [yellow]r[-] refresh  [yellow]s[-] sort  [yellow]q[-] quit  [yellow]?[-] help"
+
+// jobStatus is how the TUI shows a job's state. Its rank orders jobs when
+// sorting by status.
+func jobStatus(job config.Job) (text string, color tcell.Color, rank int) {
+	switch {
+	case job.Paused:
+		return "Paused", tcell.ColorGray, 2
+	case job.LastCheckFailed:
+		return "Failing", tcell.ColorRed, 1
+	default:
+		return "OK", tcell.ColorGreen, 0
+	}
+}
+
+// sortJobs returns jobs sorted by col, one of the sortBy constants; ties are
+// broken by URL. Sorting by duration puts the most recently added job first
+// when ascending.
+func sortJobs(jobs []config.Job, col int, asc bool) []config.Job {
+	sorted := append([]config.Job(nil), jobs...)
+	less := func(a, b config.Job) bool {
+		switch col {
+		case sortByStatus:
+			_, _, ra := jobStatus(a)
+			_, _, rb := jobStatus(b)
+			if ra != rb {
+				return ra < rb
+			}
+		case sortByDuration:
+			if !a.StartTime.Equal(b.StartTime) {
+				return a.StartTime.After(b.StartTime)
+			}
+		}
+		return a.URL < b.URL
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if asc {
+			return less(sorted[i], sorted[j])
+		}
+		return less(sorted[j], sorted[i])
+	})
+	return sorted
+}
+
+// sortHeader labels a column header, marking the sorted column with its
+// direction.
+func sortHeader(col, sortColumn int, asc bool) string {
+	if col != sortColumn {
+		return tuiHeaders[col]
+	}
+	if asc {
+		return tuiHeaders[col] + " ▲"
+	}
+	return tuiHeaders[col] + " ▼"
+}
+
+// tuiActions are what the TUI's key bindings do.
+type tuiActions struct {
+	quit    func()
+	refresh func()
+	remove  func()
+	help    func()
+	sort    func()
+	reverse func()
+	details func()
+	close   func()
+	open    func()
+}
+
+// handleTUIKey dispatches a key press on the job table to actions. Handled keys
+// are consumed; anything else is passed on to the focused widget.
+func handleTUIKey(event *tcell.EventKey, actions tuiActions) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEnter:
+		actions.details()
+		return nil
+	case tcell.KeyEscape:
+		actions.close()
+		return nil
+	case tcell.KeyRune:
+		// Handled below.
+	default:
+		return event
+	}
+	switch event.Rune() {
+	case 'q', 'Q':
+		actions.quit()
+	case 'r':
+		actions.refresh()
+	case 'o':
+		actions.open()
+	case 'd':
+		actions.remove()
+	case 's':
+		actions.sort()
+	case 'S':
+		actions.reverse()
+	case '?':
+		actions.help()
+	default:
+		return event
+	}
+	return nil
+}
+
+// removeTUIJob stops monitoring jobURL and asks a running daemon to reload.
+func removeTUIJob(store config.ConfigStore, jobURL string) error {
+	if err := store.Update(func(cfg *config.Config) error {
+		cfg.RemoveJob(jobURL)
+		return nil
+	}); err != nil {
+		return err
+	}
+	signalDaemonIfRunning()
+	return nil
+}
+
+// jobTUI is the interactive job table of jw status --tui.
+type jobTUI struct {
+	app     *tview.Application
+	store   config.ConfigStore
+	header  *tview.TextView
+	table   *tview.Table
+	details *tview.TextView
+	body    *tview.Flex
+	pages   *tview.Pages
+
+	// openURL opens a job in the browser; replaceable in tests.
+	openURL func(url string) error
+	// fetchStages looks up a job's pipeline stages for the details panel.
+	// Nil skips the lookup.
+	fetchStages func(job config.Job) ([]jenkins.StageStatus, error)
+	// stages caches fetchStages results by job URL. Like the other fields it
+	// is only touched on the UI goroutine.
+	stages map[string]stageLookup
+
+	sortColumn    int
+	sortAscending bool
+	showDetails   bool
+	quitting      bool
+}
+
+func newJobTUI(app *tview.Application, store config.ConfigStore) *jobTUI {
+	t := &jobTUI{
+		app:           app,
+		store:         store,
+		openURL:       browser.Open,
+		stages:        make(map[string]stageLookup),
+		sortColumn:    sortByURL,
+		sortAscending: true,
+	}
+	t.table = tview.NewTable().
+		SetBorders(true).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	t.table.SetSelectionChangedFunc(func(int, int) { t.updateDetails() })
+	t.details = tview.NewTextView()
+	t.details.SetBorder(true).SetTitle(" Details ")
+	t.body = tview.NewFlex().SetDirection(tview.FlexRow).AddItem(t.table, 0, 1, true)
+	t.header = tview.NewTextView().SetDynamicColors(true)
+	footer := tview.NewTextView().SetDynamicColors(true).SetText(tuiFooterText)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(t.header, 1, 0, false).
+		AddItem(t.body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	t.pages = tview.NewPages().AddPage("table", layout, true, true)
+
+	t.table.SetMouseCapture(t.handleMouse)
+	app.SetInputCapture(t.handleKey)
+	return t
+}
+
+// handleMouse sorts by a column when its header is clicked, or reverses an
+// existing sort, and opens a job when its row is double-clicked. Other mouse
+// actions, such as a click selecting a row, are left to the table.
+func (t *jobTUI) handleMouse(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action != tview.MouseLeftClick && action != tview.MouseLeftDoubleClick {
+		return action, event
+	}
+	row, col := t.table.CellAt(event.Position())
+	switch {
+	case row == 0 && action == tview.MouseLeftClick:
+		if col < 0 || col >= sortColumns {
+			return action, event
+		}
+		if col == t.sortColumn {
+			t.sortAscending = !t.sortAscending
+		} else {
+			t.sortColumn, t.sortAscending = col, true
+		}
+		t.refresh()
+		return action, nil
+	case row > 0 && action == tview.MouseLeftDoubleClick:
+		t.table.Select(row, 0)
+		t.openSelected()
+		return action, nil
+	}
+	return action, event
+}
+
+// openSelected opens the selected job in the browser.
+func (t *jobTUI) openSelected() {
+	details, ok := t.selected()
+	if !ok {
+		return
+	}
+	if err := t.openURL(details.Job.URL); err != nil {
+		t.showModal("Could not open the browser: "+err.Error(), []string{"Close"}, func(string) {})
+	}
+}
+
+// handleKey handles key presses while the table, not a dialog, is in front.
+func (t *jobTUI) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if name, _ := t.pages.GetFrontPage(); name != "table" {
+		return event
+	}
+	return handleTUIKey(event, t.actions())
+}
+
+func (t *jobTUI) actions() tuiActions {
+	return tuiActions{
+		quit: func() {
+			t.quitting = true
+			t.app.Stop()
+		},
+		refresh: t.refresh,
+		remove:  t.confirmRemove,
+		help: func() {
+			t.showModal(tuiHelpText, []string{"Close"}, func(string) {})
+		},
+		sort: func() {
+			t.sortColumn = (t.sortColumn + 1) % sortColumns
+			t.sortAscending = true
+			t.refresh()
+		},
+		reverse: func() {
+			t.sortAscending = !t.sortAscending
+			t.refresh()
+		},
+		open:    t.openSelected,
+		details: func() { t.setDetailsShown(!t.showDetails) },
+		close:   func() { t.setDetailsShown(false) },
+	}
+}
+
+// updateHeader shows whether the daemon is running.
+func (t *jobTUI) updateHeader() {
+	if pid, running := pidfileIsDaemonRunning(); running {
+		t.header.SetText(fmt.Sprintf("[green]Daemon running (PID: %d)[-]", pid))
+	} else {
+		t.header.SetText("[red]Daemon not running[-]")
+	}
+}
+
+// refresh reloads the daemon status and the jobs into the table. It stops the
+// application if the job list becomes empty.
+func (t *jobTUI) refresh() {
+	t.updateHeader()
+
+	cfg, err := t.store.Load()
+	if err != nil {
+		log.Printf("Error loading config: %v", err)
+		return
+	}
+
+	// If the job list is empty, stop the TUI.
+	if len(cfg.Jobs) == 0 {
+		t.app.Stop()
+		return
+	}
+
+	t.table.Clear()
+
+	// Set table headers
+	headerCell := func(text string) *tview.TableCell {
+		return tview.NewTableCell(text).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false)
+	}
+	for col := range tuiHeaders {
+		t.table.SetCell(0, col, headerCell(sortHeader(col, t.sortColumn, t.sortAscending)))
+	}
+
+	jobs := make([]config.Job, 0, len(cfg.Jobs))
+	for _, job := range cfg.Jobs {
+		jobs = append(jobs, job)
+	}
+
+	// Populate table rows
+	i := 1
+	for _, job := range sortJobs(jobs, t.sortColumn, t.sortAscending) {
+		duration := time.Since(job.StartTime)
+		status, statusColor, _ := jobStatus(job)
+		urlParts := strings.Split(job.URL, "/")
+		url := strings.Join(urlParts[len(urlParts)-3:], "/")
+		details := jobDetails{Job: job}
+		if records := cfg.CompletionHistory[jenkins.JobURLFromBuildURL(job.URL)]; len(records) > 0 {
+			details.PreviousResult = records[len(records)-1].Result
+		}
+		t.table.SetCell(i, 0, tview.NewTableCell(url).SetReference(details))
+		t.table.SetCell(i, 1, tview.NewTableCell(status).SetTextColor(statusColor))
+		t.table.SetCell(i, 2, tview.NewTableCell(formatDuration(duration)))
+		t.table.SetCell(i, 3, tview.NewTableCell(formatBuildNumber(job.BuildNumber)))
+		t.table.SetCell(i, 4, tview.NewTableCell(config.TruncateNote(job.Notes)))
+		i++
+	}
+	if row, _ := t.table.GetSelection(); row >= i {
+		t.table.Select(i-1, 0)
+	} else if row < 1 {
+		t.table.Select(1, 0)
+	}
+	t.updateDetails()
+}
+
+// jobDetails is what the details panel shows about a table row's job.
+type jobDetails struct {
+	Job            config.Job
+	PreviousResult string
+	Stages         []jenkins.StageStatus
+}
+
+// stageRefresh is how long fetched pipeline stages are shown before they are
+// looked up again.
+const stageRefresh = 5 * time.Second
+
+// stageLookup is the last pipeline stage lookup for a job.
+type stageLookup struct {
+	stages    []jenkins.StageStatus
+	fetchedAt time.Time
+	pending   bool
+}
+
+// selected returns the details of the job in the selected row.
+func (t *jobTUI) selected() (jobDetails, bool) {
+	row, _ := t.table.GetSelection()
+	cell := t.table.GetCell(row, 0)
+	if cell == nil {
+		return jobDetails{}, false
+	}
+	details, ok := cell.GetReference().(jobDetails)
+	return details, ok
+}
+
+func (t *jobTUI) setDetailsShown(show bool) {
+	if show == t.showDetails {
+		return
+	}
+	t.showDetails = show
+	if show {
+		t.body.AddItem(t.details, 0, 1, false)
+		t.updateDetails()
+	} else {
+		t.body.RemoveItem(t.details)
+	}
+}
+
+func (t *jobTUI) updateDetails() {
+	if !t.showDetails {
+		return
+	}
+	details, ok := t.selected()
+	if !ok {
+		t.details.SetText("")
+		return
+	}
+	details.Stages = t.stages[details.Job.URL].stages
+	t.details.SetText(formatJobDetails(details, time.Now()))
+	t.loadStages(details.Job)
+}
+
+// loadStages fetches job's pipeline stages in the background unless a recent
+// lookup is cached or one is already running, then redraws the details.
+func (t *jobTUI) loadStages(job config.Job) {
+	lookup := t.stages[job.URL]
+	if t.fetchStages == nil || lookup.pending || time.Since(lookup.fetchedAt) < stageRefresh {
+		return
+	}
+	lookup.pending = true
+	t.stages[job.URL] = lookup
+
+	go func() {
+		stages, err := t.fetchStages(job)
+		t.app.QueueUpdateDraw(func() {
+			lookup := t.stages[job.URL]
+			lookup.pending = false
+			lookup.fetchedAt = time.Now()
+			if err == nil {
+				lookup.stages = stages
+			}
+			t.stages[job.URL] = lookup
+			t.updateDetails()
+		})
+	}()
+}
+
+// formatJobDetails renders the details panel text for a job.
+func formatJobDetails(d jobDetails, now time.Time) string {
+	job := d.Job
+	lastCheck := "OK"
+	if job.LastCheckFailed {
+		lastCheck = "Failed"
+	}
+	previous := d.PreviousResult
+	if previous == "" {
+		previous = "none recorded"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "URL:             %s\n", job.URL)
+	if job.BuildNumber > 0 {
+		fmt.Fprintf(&b, "Build:           %s\n", formatBuildNumber(job.BuildNumber))
+	}
+	fmt.Fprintf(&b, "Started:         %s\n", job.StartTime.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Monitored for:   %s\n", formatDuration(now.Sub(job.StartTime)))
+	fmt.Fprintf(&b, "Last check:      %s\n", lastCheck)
+	fmt.Fprintf(&b, "Previous result: %s", previous)
+	if job.Notes != "" {
+		fmt.Fprintf(&b, "\nNotes:           %s", job.Notes)
+	}
+	if len(job.Labels) > 0 {
+		fmt.Fprintf(&b, "\nLabels:          %s", strings.Join(job.Labels, ", "))
+	}
+	if len(d.Stages) > 0 {
+		b.WriteString("\nStages:")
+		for _, stage := range d.Stages {
+			line := fmt.Sprintf("\n  %-20s %s", stage.Status, stage.Name)
+			if took := formatBuildDuration(time.Duration(stage.DurationMillis) * time.Millisecond); took != "" {
+				line += " (" + took + ")"
+			}
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+func (t *jobTUI) confirmRemove() {
+	details, ok := t.selected()
+	if !ok {
+		return
+	}
+	jobURL := details.Job.URL
+	t.showModal("Stop monitoring "+jobURL+"?", []string{"Remove", "Cancel"}, func(label string) {
+		if label != "Remove" {
+			return
+		}
+		if err := removeTUIJob(t.store, jobURL); err != nil {
+			log.Printf("Error removing job: %v", err)
+		}
+		t.refresh()
+	})
+}
+
+// showModal displays a dialog over the table until one of its buttons is
+// pressed; onDone gets the button's label.
+func (t *jobTUI) showModal(text string, buttons []string, onDone func(label string)) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(_ int, label string) {
+			t.pages.RemovePage("modal")
+			t.app.SetFocus(t.table)
+			onDone(label)
+		})
+	t.pages.AddPage("modal", modal, true, true)
+	t.app.SetFocus(modal)
+}
+
 func runTUI() {
 	// Initial check to prevent TUI from starting if there are no jobs.
-	store := config.NewDiskStore()
+	store := statusStore()
 	initialCfg, err := store.Load()
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
@@ -27,57 +518,17 @@ func runTUI() {
 	}
 
 	app := tview.NewApplication()
-	table := tview.NewTable().
-		SetBorders(true)
-
-	// updateTableContent refreshes the table view with the latest job statuses.
-	// It will stop the application if the job list becomes empty.
-	updateTableContent := func() {
-		cfg, err := store.Load()
+	tui := newJobTUI(app, store)
+	tui.fetchStages = func(job config.Job) ([]jenkins.StageStatus, error) {
+		token, err := config.GetProfileCredentials(job.Profile)
 		if err != nil {
-			log.Printf("Error loading config: %v", err)
-			return
-		}
-
-		// If the job list is empty, stop the TUI.
-		if len(cfg.Jobs) == 0 {
-			app.Stop()
-			return
-		}
-
-		table.Clear()
-
-		// Set table headers
-		headerCell := func(text string) *tview.TableCell {
-			return tview.NewTableCell(text).
-				SetTextColor(tcell.ColorYellow).
-				SetSelectable(false)
-		}
-		table.SetCell(0, 0, headerCell("Job URL"))
-		table.SetCell(0, 1, headerCell("Status"))
-		table.SetCell(0, 2, headerCell("Monitored For"))
-
-		// Populate table rows
-		i := 1
-		for _, job := range cfg.Jobs {
-			duration := time.Since(job.StartTime)
-			status := "OK"
-			statusColor := tcell.ColorGreen
-			if job.LastCheckFailed {
-				status = "Failing"
-				statusColor = tcell.ColorRed
-			}
-			urlParts := strings.Split(job.URL, "/")
-			url := strings.Join(urlParts[len(urlParts)-3:], "/")
-			table.SetCell(i, 0, tview.NewTableCell(url))
-			table.SetCell(i, 1, tview.NewTableCell(status).SetTextColor(statusColor))
-			table.SetCell(i, 2, tview.NewTableCell(formatDuration(duration)))
-			i++
+			return nil, err
 		}
+		return jenkins.GetPipelineStages(job.URL, token)
 	}
 
 	// Initial table population
-	updateTableContent()
+	tui.refresh()
 
 	// done channel is used to signal the ticker goroutine to stop.
 	done := make(chan struct{})
@@ -89,9 +540,7 @@ func runTUI() {
 		for {
 			select {
 			case <-ticker.C:
-				app.QueueUpdateDraw(func() {
-					updateTableContent()
-				})
+				app.QueueUpdateDraw(tui.refresh)
 			case <-done:
 				return
 			}
@@ -99,11 +548,13 @@ func runTUI() {
 	}()
 
 	// Run the application.
-	if err := app.SetRoot(table, true).Run(); err != nil {
+	if err := app.SetRoot(tui.pages, true).EnableMouse(true).Run(); err != nil {
 		fmt.Printf("Error running TUI: %v\n", err)
 	}
 
 	// Signal the ticker to stop.
-	fmt.Println(ui.GreenText("All watched jobs finished! TUI exited."))
 	close(done)
+	if !tui.quitting {
+		fmt.Println(ui.GreenText("All watched jobs finished! TUI exited."))
+	}
 }
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleTUIKey(t *testing.T) {
+	var called []string
+	actions := tuiActions{
+		quit:    func() { called = append(called, "quit") },
+		refresh: func() { called = append(called, "refresh") },
+		remove:  func() { called = append(called, "remove") },
+		help:    func() { called = append(called, "help") },
+		sort:    func() { called = append(called, "sort") },
+		reverse: func() { called = append(called, "reverse") },
+		details: func() { called = append(called, "details") },
+		close:   func() { called = append(called, "close") },
+		open:    func() { called = append(called, "open") },
+	}
+
+	tests := []struct {
+		r    rune
+		want string
+	}{
+		{'q', "quit"},
+		{'Q', "quit"},
+		{'r', "refresh"},
+		{'d', "remove"},
+		{'?', "help"},
+		{'s', "sort"},
+		{'S', "reverse"},
+		{'o', "open"},
+	}
+	for _, tt := range tests {
+		called = nil
+		event := tcell.NewEventKey(tcell.KeyRune, tt.r, tcell.ModNone)
+		assert.Nil(t, handleTUIKey(event, actions), "%q should be consumed", tt.r)
+		assert.Equal(t, []string{tt.want}, called, "key %q", tt.r)
+	}
+
+	called = nil
+	assert.Nil(t, handleTUIKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), actions))
+	assert.Nil(t, handleTUIKey(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), actions))
+	assert.Equal(t, []string{"details", "close"}, called)
+
+	called = nil
+	for _, event := range []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone),
+	} {
+		assert.Same(t, event, handleTUIKey(event, actions), "unbound keys pass through to the table")
+	}
+	assert.Empty(t, called)
+}
+
+func TestRemoveTUIJob(t *testing.T) {
+	withNoDaemon(t)
+	keep, remove := "https://jenkins/job/a/1", "https://jenkins/job/b/2"
+	store := newMemStore(config.Job{URL: keep}, config.Job{URL: remove})
+
+	require.NoError(t, removeTUIJob(store, remove))
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(keep))
+	assert.False(t, cfg.HasJob(remove))
+}
+
+func TestSortJobs(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	jobs := []config.Job{
+		{URL: "https://jenkins/job/b/1", StartTime: now.Add(-time.Hour), LastCheckFailed: true},
+		{URL: "https://jenkins/job/d/1", StartTime: now.Add(-3 * time.Hour)},
+		{URL: "https://jenkins/job/a/1", StartTime: now.Add(-2 * time.Hour), Paused: true},
+		{URL: "https://jenkins/job/c/1", StartTime: now.Add(-time.Minute)},
+	}
+	urls := func(jobs []config.Job) []string {
+		out := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			out = append(out, job.URL[len("https://jenkins/job/"):len("https://jenkins/job/")+1])
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		col  int
+		asc  bool
+		want []string
+	}{
+		{"url ascending", sortByURL, true, []string{"a", "b", "c", "d"}},
+		{"url descending", sortByURL, false, []string{"d", "c", "b", "a"}},
+		{"status ascending", sortByStatus, true, []string{"c", "d", "b", "a"}},
+		{"status descending", sortByStatus, false, []string{"a", "b", "d", "c"}},
+		{"duration ascending", sortByDuration, true, []string{"c", "b", "a", "d"}},
+		{"duration descending", sortByDuration, false, []string{"d", "a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, urls(sortJobs(jobs, tt.col, tt.asc)))
+		})
+	}
+	assert.Equal(t, "https://jenkins/job/b/1", jobs[0].URL, "the input is not reordered")
+}
+
+func TestSortHeader(t *testing.T) {
+	assert.Equal(t, "Job URL ▲", sortHeader(sortByURL, sortByURL, true))
+	assert.Equal(t, "Status ▼", sortHeader(sortByStatus, sortByStatus, false))
+	assert.Equal(t, "Monitored For", sortHeader(sortByDuration, sortByURL, true))
+}
+
+func TestJobTUI_DetailsPanelToggles(t *testing.T) {
+	withNoDaemon(t)
+	jobURL := "https://jenkins/job/app/8"
+	store := newMemStore(config.Job{URL: jobURL, StartTime: time.Now().Add(-time.Hour), Notes: "waiting for hotfix deploy"})
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.RecordCompletion("https://jenkins/job/app", config.BuildRecord{Result: "FAILURE"})
+		return nil
+	}))
+	tui := newJobTUI(tview.NewApplication(), store)
+	tui.refresh()
+
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	escape := tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)
+
+	require.Equal(t, 1, tui.body.GetItemCount())
+
+	assert.Nil(t, tui.handleKey(enter))
+	require.Equal(t, 2, tui.body.GetItemCount(), "Enter shows the details panel")
+	text := tui.details.GetText(true)
+	assert.Contains(t, text, "URL:             "+jobURL)
+	assert.Contains(t, text, "Monitored for:   1h 0m")
+	assert.Contains(t, text, "Previous result: FAILURE")
+	assert.Contains(t, text, "Notes:           waiting for hotfix deploy")
+
+	tui.handleKey(enter)
+	assert.Equal(t, 1, tui.body.GetItemCount(), "Enter again hides it")
+
+	tui.handleKey(enter)
+	tui.handleKey(escape)
+	assert.Equal(t, 1, tui.body.GetItemCount(), "Escape hides it")
+
+	tui.handleKey(escape)
+	assert.Equal(t, 1, tui.body.GetItemCount(), "Escape with nothing shown is harmless")
+}
+
+func TestJobTUI_DetailsShowPipelineStages(t *testing.T) {
+	withNoDaemon(t)
+	jobURL := "https://jenkins/job/app/8"
+	tui := newJobTUI(tview.NewApplication(), newMemStore(config.Job{URL: jobURL, StartTime: time.Now()}))
+	tui.stages[jobURL] = stageLookup{fetchedAt: time.Now(), stages: []jenkins.StageStatus{
+		{Name: "Checkout", Status: "SUCCESS", DurationMillis: 3200},
+		{Name: "Test", Status: "FAILED", DurationMillis: 91000},
+		{Name: "Deploy", Status: "NOT_EXECUTED"},
+	}}
+	tui.refresh()
+	tui.setDetailsShown(true)
+
+	text := tui.details.GetText(true)
+	assert.Contains(t, text, "\nStages:\n  SUCCESS              Checkout (3s)\n  FAILED               Test (1m 31s)\n  NOT_EXECUTED         Deploy")
+}
+
+func TestJobTUI_HeaderFollowsDaemon(t *testing.T) {
+	pid, running := 0, false
+	orig := pidfileIsDaemonRunning
+	pidfileIsDaemonRunning = func() (int, bool) { return pid, running }
+	t.Cleanup(func() { pidfileIsDaemonRunning = orig })
+
+	tui := newJobTUI(tview.NewApplication(), newMemStore(config.Job{URL: "https://jenkins/job/app/8"}))
+
+	tui.refresh()
+	assert.Equal(t, "Daemon not running", tui.header.GetText(true))
+
+	pid, running = 4242, true
+	tui.refresh()
+	assert.Equal(t, "Daemon running (PID: 4242)", tui.header.GetText(true))
+
+	pid = 5151
+	tui.refresh()
+	assert.Equal(t, "Daemon running (PID: 5151)", tui.header.GetText(true))
+}
+
+// drawTUI lays tui out on a simulated screen and returns a screen position
+// inside table row.
+func drawTUI(t *testing.T, tui *jobTUI, row int) (x, y int) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	t.Cleanup(screen.Fini)
+	screen.SetSize(120, 30)
+	tui.pages.SetRect(0, 0, 120, 30)
+	tui.pages.Draw(screen)
+
+	x, top, _, height := tui.table.GetInnerRect()
+	for y := top; y < top+height; y++ {
+		if r, _ := tui.table.CellAt(x+1, y); r == row {
+			return x + 1, y
+		}
+	}
+	t.Fatalf("table row %d is not on screen", row)
+	return 0, 0
+}
+
+func TestJobTUI_MouseOpensJob(t *testing.T) {
+	withNoDaemon(t)
+	store := newMemStore(
+		config.Job{URL: "https://jenkins/job/a/1"},
+		config.Job{URL: "https://jenkins/job/b/2"},
+	)
+	tui := newJobTUI(tview.NewApplication(), store)
+	var opened []string
+	tui.openURL = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+	tui.refresh()
+
+	mouse := tui.table.MouseHandler()
+	x, y := drawTUI(t, tui, 2)
+	click := func(action tview.MouseAction) {
+		mouse(action, tcell.NewEventMouse(x, y, tcell.ButtonPrimary, tcell.ModNone), func(tview.Primitive) {})
+	}
+
+	click(tview.MouseLeftClick)
+	row, _ := tui.table.GetSelection()
+	assert.Equal(t, 2, row, "a click selects the row")
+	assert.Empty(t, opened, "a single click does not open the job")
+
+	click(tview.MouseLeftDoubleClick)
+	assert.Equal(t, []string{"https://jenkins/job/b/2"}, opened)
+
+	tui.table.Select(1, 0)
+	tui.handleKey(tcell.NewEventKey(tcell.KeyRune, 'o', tcell.ModNone))
+	assert.Equal(t, []string{"https://jenkins/job/b/2", "https://jenkins/job/a/1"}, opened)
+}
+
+func TestJobTUI_HeaderClickSorts(t *testing.T) {
+	withNoDaemon(t)
+	tui := newJobTUI(tview.NewApplication(), newMemStore(config.Job{URL: "https://jenkins/job/a/1"}))
+	tui.refresh()
+
+	x, y := drawTUI(t, tui, 0)
+	mouse := tui.table.MouseHandler()
+	mouse(tview.MouseLeftClick, tcell.NewEventMouse(x, y, tcell.ButtonPrimary, tcell.ModNone), func(tview.Primitive) {})
+
+	assert.Equal(t, sortByURL, tui.sortColumn)
+	assert.False(t, tui.sortAscending, "clicking the sorted column reverses it")
+	assert.Equal(t, "Job URL ▼", tui.table.GetCell(0, 0).Text)
+}
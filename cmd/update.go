@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"jenkins-monitor/pkg/pidfile"
+	"jenkins-monitor/pkg/ui"
+	"jenkins-monitor/pkg/upgrade"
+
+	"github.com/spf13/cobra"
+)
+
+const updateTimeout = 5 * time.Minute
+
+var (
+	updatePreRelease bool
+	updateForce      bool
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update jw to the latest release",
+	Long: `Download the latest jw release for this OS and architecture, check it against
+the release's checksums and replace the running binary with it. Use
+--pre-release to include pre-releases. A dev build is only replaced with
+--force.
+
+If jw was installed with Homebrew, prefer brew upgrade jw.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+		defer cancel()
+		result, err := upgrade.SelfUpdate(ctx, updatePreRelease, updateForce)
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+		_, running := pidfile.IsDaemonRunning()
+		writeUpdateResult(os.Stdout, result, running)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updatePreRelease, "pre-release", false, "Include pre-releases")
+	updateCmd.Flags().BoolVar(&updateForce, "force", false, "Replace a dev build too")
+}
+
+// writeUpdateResult reports what jw update did and how to start using the
+// new binary.
+func writeUpdateResult(w io.Writer, result upgrade.UpdateResult, daemonRunning bool) {
+	if !result.Updated {
+		fmt.Fprintln(w, ui.GreenText(fmt.Sprintf("jw %s is up to date (latest release: %s)", result.Current, result.Latest)))
+		return
+	}
+	fmt.Fprintln(w, ui.GreenText(fmt.Sprintf("Updated jw %s -> %s at %s", result.Current, result.Latest, result.Path)))
+	fmt.Fprintln(w, "Run `hash -r` (or `rehash` in zsh) or open a new shell to use it.")
+	if daemonRunning {
+		fmt.Fprintln(w, "The daemon is still running the old version; run `jw restart` to switch it over.")
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"jenkins-monitor/pkg/upgrade"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteUpdateResult(t *testing.T) {
+	var out bytes.Buffer
+	writeUpdateResult(&out, upgrade.UpdateResult{Current: "v1.0.0", Latest: "v1.1.0", Path: "/usr/local/bin/jw", Updated: true}, true)
+	assert.Contains(t, out.String(), "Updated jw v1.0.0 -> v1.1.0 at /usr/local/bin/jw")
+	assert.Contains(t, out.String(), "hash -r")
+	assert.Contains(t, out.String(), "jw restart")
+
+	out.Reset()
+	writeUpdateResult(&out, upgrade.UpdateResult{Current: "v1.1.0", Latest: "v1.1.0"}, false)
+	assert.Contains(t, out.String(), "jw v1.1.0 is up to date (latest release: v1.1.0)")
+	assert.NotContains(t, out.String(), "hash -r")
+}
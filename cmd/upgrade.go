@@ -1,7 +1,10 @@
 package cmd
 
 import (
-	"jenkins-monitor/pkg/config"
+	"fmt"
+	"os"
+
+	"jenkins-monitor/pkg/ui"
 	"jenkins-monitor/pkg/upgrade"
 
 	"github.com/spf13/cobra"
@@ -12,12 +15,12 @@ var upgradeCmd = &cobra.Command{
 	Short:  "check upgrade",
 	Hidden: true,
 	Run: func(cmd *cobra.Command, args []string) {
-		store := config.NewDiskStore()
-		cfg, err := store.Load()
+		store, err := upgrade.NewFileStateStore()
 		if err != nil {
-			panic(err)
+			fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+			os.Exit(1)
 		}
-		upgrade.RunCheck(store, cfg)
+		upgrade.RunCheck(os.Stdout, store)
 	},
 }
 
@@ -1,20 +1,42 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+
+	"jenkins-monitor/pkg/ui"
 	"jenkins-monitor/pkg/version"
 
 	"github.com/spf13/cobra"
 )
 
+var versionJSON bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version information",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("Version: %s\n", version.GetVersion())
+		if err := writeVersion(os.Stdout, version.Get(), versionJSON); err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	RootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Print the version and build details as JSON")
+}
+
+// writeVersion prints info, as JSON if asJSON is set.
+func writeVersion(w io.Writer, info version.BuildInfo, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(info)
+	}
+	_, err := fmt.Fprintf(w, "Version: %s\n", info.Version)
+	return err
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"jenkins-monitor/pkg/version"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteVersion(t *testing.T) {
+	info := version.BuildInfo{Version: "v1.2.0", GoVersion: "go1.25.6", OS: "darwin", Arch: "arm64", BuildDate: "2026-10-01T12:00:00Z", Commit: "abc123"}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeVersion(&buf, info, false))
+	assert.Equal(t, "Version: v1.2.0\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeVersion(&buf, info, true))
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, map[string]string{
+		"version":    "v1.2.0",
+		"go_version": "go1.25.6",
+		"os":         "darwin",
+		"arch":       "arm64",
+		"build_date": "2026-10-01T12:00:00Z",
+		"commit":     "abc123",
+	}, got)
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/logging"
+	"jenkins-monitor/pkg/notify"
+	"jenkins-monitor/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const watchPollInterval = 10 * time.Second
+
+// errWatchInterrupted is returned by watchJob when the user stops watching.
+var errWatchInterrupted = errors.New("watch interrupted")
+
+// errWatchTimeout is returned by watchJob when its context's deadline passes
+// before the build finishes.
+var errWatchTimeout = errors.New("build timed out")
+
+// exitTimeout is the exit code of jw watch and jw add --wait when --timeout
+// passes before the build finishes.
+const exitTimeout = 2
+
+var (
+	watchProfile string
+	watchTimeout time.Duration
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [job_url]",
+	Short: "Watch a Jenkins job in the foreground until it finishes",
+	Long: `Watch a Jenkins job in the foreground until it finishes. Exits 0 if the build
+succeeds, 1 otherwise and 2 if --timeout passes first. Nothing is written to
+the config unless you choose to hand the job over to the background daemon after
+pressing Ctrl-C.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeURLHints,
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := config.GetProfileCredentials(watchProfile)
+		if err != nil {
+			fmt.Println(ui.RedText("Error: " + err.Error()))
+			os.Exit(1)
+		}
+
+		var baseURL string
+		if creds, err := config.LoadProfile(watchProfile); err == nil {
+			baseURL = creds.BaseURL
+		}
+		jobURL := normalizeJobURL(args[0], baseURL)
+		if !strings.HasPrefix(jobURL, "http://") && !strings.HasPrefix(jobURL, "https://") {
+			fmt.Println(ui.RedText("Error: Job URL must start with http:// or https://"))
+			os.Exit(1)
+		}
+		if watchTimeout < 0 {
+			fmt.Println(ui.RedText("Error: --timeout must not be negative"))
+			os.Exit(1)
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(interrupt)
+
+		ctx, cancel := watchContext(watchTimeout)
+		defer cancel()
+		result, err := watchJob(ctx, jobURL, token, watchPollInterval, interrupt)
+		// Let a second Ctrl-C abort the prompt finishWatch may show.
+		signal.Stop(interrupt)
+		if errors.Is(err, errWatchTimeout) {
+			store := config.NewDiskStore()
+			notifier := notify.Notifier(notify.New())
+			if cfg, err := store.Load(); err == nil {
+				notifier = buildNotifier(cfg)
+			}
+			os.Exit(watchTimedOut(os.Stdout, store, notifier, jobURL, watchTimeout))
+		}
+		os.Exit(finishWatch(jobURL, watchProfile, result, err))
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+	watchCmd.Flags().StringVar(&watchProfile, "profile", config.DefaultProfile, "Credential profile used to poll the job")
+	watchCmd.Flags().DurationVar(&watchTimeout, "timeout", 0, "Give up and exit 2 if the build has not finished after this long (e.g. 30m)")
+}
+
+// watchContext returns the context a watch runs under: one that expires after
+// timeout, or that never does if timeout is 0.
+func watchContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// watchJob polls jobURL until the build finishes, showing a spinner, and
+// returns the Jenkins result. It returns errWatchInterrupted if a value
+// arrives on interrupt first, and errWatchTimeout if ctx expires.
+func watchJob(ctx context.Context, jobURL, token string, interval time.Duration, interrupt <-chan os.Signal) (string, error) {
+	name := jobDisplayName(jobURL)
+	spinner := ui.NewSpinner("Building " + name)
+	spinner.Start()
+	defer spinner.Stop()
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, code, err := jenkins.GetJobStatusCtx(ctx, jobURL, token)
+		switch {
+		case ctx.Err() != nil:
+			return "", watchContextErr(ctx)
+		case err != nil && (code == 404 || code == 401 || code == 403):
+			return "", err
+		case err != nil:
+			spinner.SetText(fmt.Sprintf("Building %s (error: %v, retrying)", name, err))
+		case !status.Building:
+			return status.Result, nil
+		default:
+			spinner.SetText(fmt.Sprintf("Building %s (still building… %s)", name, time.Since(start).Round(time.Second)))
+		}
+
+		select {
+		case <-interrupt:
+			return "", errWatchInterrupted
+		case <-ctx.Done():
+			return "", watchContextErr(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchContextErr(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errWatchTimeout
+	}
+	return ctx.Err()
+}
+
+// watchTimedOut reports that jobURL was still building after timeout, sends
+// a "Build timed out" notification unless it is quiet hours and returns
+// exitTimeout.
+func watchTimedOut(w io.Writer, store config.ConfigStore, notifier notify.Notifier, jobURL string, timeout time.Duration) int {
+	name := jobDisplayName(jobURL)
+	fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("%s still building after %s, giving up", name, timeout)))
+	if quietForWatch(w, store) {
+		return exitTimeout
+	}
+	if err := notifier.Send(notify.Notification{
+		Title:   "Build timed out",
+		Message: fmt.Sprintf("Job: %s\nStill building after %s.", name, timeout),
+		URL:     jobURL,
+		JobName: name,
+	}); err != nil {
+		fmt.Fprintln(w, ui.YellowText(fmt.Sprintf("Failed to send notification: %v", err)))
+	}
+	return exitTimeout
+}
+
+// quietForWatch reports whether a foreground watch should hold back its
+// notification because of quiet hours, and says so on w.
+func quietForWatch(w io.Writer, store config.ConfigStore) bool {
+	if !inQuietHours(store, logging.TextLogger(w)) {
+		return false
+	}
+	fmt.Fprintln(w, ui.MutedText("Quiet hours, not sending a notification"))
+	return true
+}
+
+// finishWatch reports the outcome of watchJob and returns the exit code.
+// profile is recorded if the job is handed over to the daemon.
+func finishWatch(jobURL, profile, result string, err error) int {
+	if errors.Is(err, errWatchInterrupted) {
+		fmt.Println()
+		if confirm(os.Stdin, os.Stdout, "Keep monitoring this job in the background?") {
+			if _, err := addJobs(os.Stdout, config.NewDiskStore(), []string{jobURL}, jobOptions{profile: profile}); err != nil {
+				fmt.Println(ui.RedText(fmt.Sprintf("Error saving config: %v", err)))
+				return 1
+			}
+			if signalDaemonReload() {
+				fmt.Println("Daemon signaled to monitor the job.")
+			}
+			return 0
+		}
+		return 130
+	}
+	if err != nil {
+		fmt.Println(ui.RedText(fmt.Sprintf("Error: %v", err)))
+		return 1
+	}
+
+	line := fmt.Sprintf("%s finished: %s", jobDisplayName(jobURL), result)
+	if result == "SUCCESS" {
+		fmt.Println(ui.GreenText(line))
+		return 0
+	}
+	fmt.Println(ui.RedText(line))
+	return 1
+}
+
+// jobDisplayName returns the part of a job URL after the last "/job/".
+func jobDisplayName(jobURL string) string {
+	parts := strings.Split(strings.TrimRight(jobURL, "/"), "/job/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/jenkins"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchJob_UntilFinished(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := jenkins.JobStatus{Building: true}
+		if polls.Add(1) >= 3 {
+			status = jenkins.JobStatus{Building: false, Result: "FAILURE"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(status)
+	}))
+	defer server.Close()
+
+	result, err := watchJob(context.Background(), server.URL+"/job/test/1", "token", 10*time.Millisecond, make(chan os.Signal))
+	require.NoError(t, err)
+	assert.Equal(t, "FAILURE", result)
+	assert.EqualValues(t, 3, polls.Load())
+}
+
+func TestWatchJob_Interrupted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	interrupt := make(chan os.Signal, 1)
+	interrupt <- os.Interrupt
+
+	_, err := watchJob(context.Background(), server.URL+"/job/test/1", "token", time.Hour, interrupt)
+	assert.ErrorIs(t, err, errWatchInterrupted)
+}
+
+func TestWatchJob_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := watchJob(context.Background(), server.URL+"/job/missing/1", "token", 10*time.Millisecond, make(chan os.Signal))
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestFinishWatch_ExitCodes(t *testing.T) {
+	assert.Equal(t, 0, finishWatch("https://jenkins/job/a/1", config.DefaultProfile, "SUCCESS", nil))
+	assert.Equal(t, 1, finishWatch("https://jenkins/job/a/1", config.DefaultProfile, "FAILURE", nil))
+	assert.Equal(t, 1, finishWatch("https://jenkins/job/a/1", config.DefaultProfile, "ABORTED", nil))
+}
+
+func TestWatchJob_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jenkins.JobStatus{Building: true})
+	}))
+	defer server.Close()
+
+	ctx, cancel := watchContext(30 * time.Millisecond)
+	defer cancel()
+	_, err := watchJob(ctx, server.URL+"/job/test/1", "token", time.Hour, make(chan os.Signal))
+	assert.ErrorIs(t, err, errWatchTimeout)
+}
+
+func TestWatchTimedOut_QuietHours(t *testing.T) {
+	store := newMemStore()
+	now := time.Now().UTC()
+	require.NoError(t, store.Update(func(cfg *config.Config) error {
+		cfg.QuietHours = config.QuietHours{
+			Start:    now.Add(-time.Hour).Format("15:04"),
+			End:      now.Add(time.Hour).Format("15:04"),
+			Timezone: "UTC",
+		}
+		return nil
+	}))
+	notifier := &recordingNotifier{}
+	var out bytes.Buffer
+	code := watchTimedOut(&out, store, notifier, "https://jenkins/job/app/1", time.Minute)
+
+	assert.Equal(t, exitTimeout, code)
+	assert.Empty(t, notifier.getCalls())
+	assert.Contains(t, out.String(), "app/1 still building after 1m0s")
+	assert.Contains(t, out.String(), "Quiet hours")
+}
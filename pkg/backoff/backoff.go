@@ -0,0 +1,63 @@
+// Package backoff computes retry delays for transient failures.
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultInitial    = 5 * time.Second
+	defaultMultiplier = 2.0
+	defaultMax        = 5 * time.Minute
+	defaultJitter     = 0.2
+)
+
+// Exponential produces exponentially growing delays with random jitter.
+// It is not safe for concurrent use; each monitor goroutine owns its own.
+type Exponential struct {
+	Initial    time.Duration
+	Multiplier float64
+	Max        time.Duration
+	Jitter     float64 // fraction of the delay, e.g. 0.2 for ±20%
+
+	attempt int
+	rand    func() float64
+}
+
+// NewExponential returns a backoff starting at 5s, doubling up to 5 minutes, with ±20% jitter.
+func NewExponential() *Exponential {
+	return &Exponential{
+		Initial:    defaultInitial,
+		Multiplier: defaultMultiplier,
+		Max:        defaultMax,
+		Jitter:     defaultJitter,
+		rand:       rand.Float64,
+	}
+}
+
+// Next returns the delay before the next retry and advances the sequence.
+func (e *Exponential) Next() time.Duration {
+	delay := float64(e.Initial)
+	for i := 0; i < e.attempt && delay < float64(e.Max); i++ {
+		delay *= e.Multiplier
+	}
+	if delay > float64(e.Max) {
+		delay = float64(e.Max)
+	}
+	e.attempt++
+
+	if e.Jitter > 0 {
+		r := rand.Float64
+		if e.rand != nil {
+			r = e.rand
+		}
+		delay += delay * e.Jitter * (2*r() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Reset starts the sequence over from the initial delay.
+func (e *Exponential) Reset() {
+	e.attempt = 0
+}
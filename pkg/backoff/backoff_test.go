@@ -0,0 +1,55 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponential_StaysWithinBounds(t *testing.T) {
+	b := NewExponential()
+
+	base := 5 * time.Second
+	for i := 0; i < 12; i++ {
+		d := b.Next()
+		low := time.Duration(float64(base) * 0.8)
+		high := time.Duration(float64(base) * 1.2)
+		assert.GreaterOrEqual(t, d, low, "attempt %d below lower bound", i)
+		assert.LessOrEqual(t, d, high, "attempt %d above upper bound", i)
+
+		base *= 2
+		if base > 5*time.Minute {
+			base = 5 * time.Minute
+		}
+	}
+}
+
+func TestExponential_Sequence(t *testing.T) {
+	b := NewExponential()
+	b.rand = func() float64 { return 0.5 } // no jitter
+
+	want := []time.Duration{
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		80 * time.Second,
+		160 * time.Second,
+		5 * time.Minute,
+		5 * time.Minute,
+	}
+	for i, w := range want {
+		assert.Equal(t, w, b.Next(), "attempt %d", i)
+	}
+}
+
+func TestExponential_Reset(t *testing.T) {
+	b := NewExponential()
+	b.rand = func() float64 { return 0.5 }
+
+	b.Next()
+	b.Next()
+	b.Reset()
+	assert.Equal(t, 5*time.Second, b.Next())
+}
@@ -0,0 +1,39 @@
+// Package browser opens URLs in the user's default web browser.
+package browser
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// execCommand wraps exec.Command for testability.
+var execCommand = exec.Command
+
+// Open opens url in the default browser for the current platform.
+func Open(url string) error {
+	name, args, err := command(runtime.GOOS, url)
+	if err != nil {
+		return err
+	}
+	if err := execCommand(name, args...).Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return nil
+}
+
+// command returns the program and arguments that open url on goos.
+func command(goos, url string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "xdg-open", []string{url}, nil
+	case "windows":
+		// The empty argument is the window title; without it start treats a
+		// quoted URL as the title.
+		return "cmd", []string{"/c", "start", "", url}, nil
+	default:
+		return "", nil, fmt.Errorf("opening a browser is not supported on %s", goos)
+	}
+}
@@ -0,0 +1,51 @@
+package browser
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		goos string
+		name string
+		args []string
+	}{
+		{"darwin", "open", []string{"http://j/job/a"}},
+		{"linux", "xdg-open", []string{"http://j/job/a"}},
+		{"windows", "cmd", []string{"/c", "start", "", "http://j/job/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args, err := command(tt.goos, "http://j/job/a")
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+
+	_, _, err := command("plan9", "http://j/job/a")
+	assert.Error(t, err)
+}
+
+func TestOpen(t *testing.T) {
+	var gotName string
+	var gotArgs []string
+	orig := execCommand
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		gotName, gotArgs = name, args
+		return exec.Command("true")
+	}
+	t.Cleanup(func() { execCommand = orig })
+
+	require.NoError(t, Open("http://j/job/a"))
+
+	wantName, wantArgs, _ := command(runtime.GOOS, "http://j/job/a")
+	assert.Equal(t, wantName, gotName)
+	assert.Equal(t, wantArgs, gotArgs)
+}
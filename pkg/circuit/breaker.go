@@ -0,0 +1,123 @@
+// Package circuit implements a circuit breaker that stops requests to a host
+// that keeps failing.
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultThreshold = 5
+	DefaultCooldown  = 60 * time.Second
+)
+
+// ErrBreakerOpen is returned by Allow while the breaker refuses requests.
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// State is the position of a Breaker.
+type State int
+
+const (
+	Closed   State = iota // requests flow normally
+	Open                  // requests are refused until the cool-down ends
+	HalfOpen              // one trial request is in flight
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker opens after Threshold consecutive failures and refuses requests for
+// Cooldown. After that a single trial request is let through: its success
+// closes the breaker, its failure opens it again. It is safe for concurrent
+// use.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// New returns a closed breaker with the default threshold and cool-down.
+func New() *Breaker {
+	return &Breaker{Threshold: DefaultThreshold, Cooldown: DefaultCooldown, now: time.Now}
+}
+
+// Allow reports whether a request may be made, returning ErrBreakerOpen if
+// not. Every allowed request must be followed by Success or Failure.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.clock().Sub(b.openedAt) < b.Cooldown {
+			return ErrBreakerOpen
+		}
+		b.state = HalfOpen
+		return nil
+	case HalfOpen:
+		return ErrBreakerOpen
+	default:
+		return nil
+	}
+}
+
+// Success records a request that reached the host and closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = Closed
+	b.failures = 0
+}
+
+// Failure records a failed request. It reports whether this failure opened a
+// closed breaker; a failed trial re-opens it without reporting.
+func (b *Breaker) Failure() (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case HalfOpen:
+		b.state = Open
+		b.openedAt = b.clock()
+		return false
+	case Open:
+		return false
+	}
+
+	b.failures++
+	if b.failures < b.Threshold {
+		return false
+	}
+	b.state = Open
+	b.openedAt = b.clock()
+	return true
+}
+
+// State returns the breaker's current position.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) clock() time.Time {
+	if b.now == nil {
+		return time.Now()
+	}
+	return b.now()
+}
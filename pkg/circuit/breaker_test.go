@@ -0,0 +1,70 @@
+package circuit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBreaker(threshold int) (*Breaker, *time.Time) {
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New()
+	b.Threshold = threshold
+	b.now = func() time.Time { return clock }
+	return b, &clock
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.Allow())
+		assert.False(t, b.Failure())
+		assert.Equal(t, Closed, b.State())
+	}
+	require.NoError(t, b.Allow())
+	assert.True(t, b.Failure(), "the third failure opens the breaker")
+	assert.Equal(t, Open, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrBreakerOpen)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2)
+
+	b.Failure()
+	b.Success()
+	assert.False(t, b.Failure())
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	b, clock := newTestBreaker(1)
+	require.True(t, b.Failure())
+
+	*clock = clock.Add(DefaultCooldown - time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrBreakerOpen)
+
+	*clock = clock.Add(time.Second)
+	require.NoError(t, b.Allow(), "the first request after the cool-down is a trial")
+	assert.Equal(t, HalfOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrBreakerOpen, "only one trial at a time")
+
+	b.Success()
+	assert.Equal(t, Closed, b.State())
+	assert.NoError(t, b.Allow())
+}
+
+func TestBreaker_FailedTrialReopens(t *testing.T) {
+	b, clock := newTestBreaker(1)
+	require.True(t, b.Failure())
+
+	*clock = clock.Add(DefaultCooldown)
+	require.NoError(t, b.Allow())
+	assert.False(t, b.Failure(), "re-opening after a failed trial is not reported")
+	assert.Equal(t, Open, b.State())
+
+	*clock = clock.Add(DefaultCooldown - time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrBreakerOpen, "the cool-down restarts from the failed trial")
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic_FailedWriteKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, configFileName)
+	original := []byte(`{"jobs":{"https://jenkins/job/a/1":{"url":"https://jenkins/job/a/1"}}}`)
+	require.NoError(t, os.WriteFile(path, original, 0o644))
+
+	errDiskFull := errors.New("disk full")
+	err := writeFileAtomic(path, 0o644, func(w io.Writer) error {
+		if _, err := w.Write([]byte(`{"jobs":{"https://jenk`)); err != nil {
+			return err
+		}
+		return errDiskFull
+	})
+	assert.ErrorIs(t, err, errDiskFull)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data, "a failed write must not touch the config")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the temporary file is cleaned up")
+}
+
+func TestWriteFileAtomic_ReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, configFileName)
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0o600))
+
+	require.NoError(t, writeFileAtomic(path, 0o644, func(w io.Writer) error {
+		_, err := w.Write([]byte("new"))
+		return err
+	}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBackupCount is how many config backups are kept when
+// Settings.BackupCount is unset.
+const DefaultBackupCount = 3
+
+// backupInterval is the least time between backups taken by saves, so that
+// frequent writes such as the daemon's cannot push every good copy out
+// before a bad write is noticed.
+const backupInterval = time.Hour
+
+// Settings holds options for jw itself rather than for notifications or jobs.
+type Settings struct {
+	// BackupCount is how many previous versions of the config file are kept
+	// as monitored_jobs.json.bak.1 (the newest) to .bak.N, taken at most
+	// once per backupInterval. Zero means DefaultBackupCount and a negative
+	// value disables backups.
+	BackupCount int `json:"backup_count,omitempty"`
+}
+
+func (s Settings) backupCount() int {
+	if s.BackupCount == 0 {
+		return DefaultBackupCount
+	}
+	return max(s.BackupCount, 0)
+}
+
+func backupPath(path string, n int) string {
+	return path + ".bak." + strconv.Itoa(n)
+}
+
+// GetBackupPath returns the path of config backup n, where 1 is the newest.
+func GetBackupPath(n int) (string, error) {
+	path, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return backupPath(path, n), nil
+}
+
+// backupDue reports whether a save at now should back up the file at path
+// first: when there is no backup yet or the newest is backupInterval old.
+func backupDue(path string, now time.Time) bool {
+	info, err := os.Stat(backupPath(path, 1))
+	return err != nil || now.Sub(info.ModTime()) >= backupInterval
+}
+
+// rotateBackups copies the file at path to path.bak.1, first shifting the
+// existing backups up by one and dropping any beyond count. A missing file
+// has nothing to back up.
+func rotateBackups(path string, count int) error {
+	matches, err := filepath.Glob(path + ".bak.*")
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, path+".bak."))
+		if err == nil && n >= count {
+			if err := os.Remove(m); err != nil {
+				return err
+			}
+		}
+	}
+	if count <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for n := count - 1; n >= 1; n-- {
+		if err := os.Rename(backupPath(path, n), backupPath(path, n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.WriteFile(backupPath(path, 1), data, 0o644)
+}
+
+// RestoreBackup replaces the config with backup n, backing up the current
+// config first like any other write.
+func RestoreBackup(n int) error {
+	if n < 1 {
+		return fmt.Errorf("backup number must be at least 1, got %d", n)
+	}
+	path, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+
+	return withFileLock(func() error {
+		data, err := os.ReadFile(backupPath(path, n))
+		if err != nil {
+			return fmt.Errorf("reading backup %d: %w", n, err)
+		}
+		var backup Config
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return fmt.Errorf("backup %d is not a valid config: %w", n, err)
+		}
+		if err := rotateBackups(path, backup.Settings.backupCount()); err != nil {
+			return fmt.Errorf("backing up the current config: %w", err)
+		}
+		return writeFileAtomic(path, 0o644, func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	})
+}
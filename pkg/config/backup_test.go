@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateBackups_RespectsCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, rotateBackups(path, 3))
+		require.NoError(t, os.WriteFile(path, []byte(strconv.Itoa(i)), 0o644))
+	}
+
+	// The live file is version 5; the backups are the three before it.
+	for n, want := range map[int]string{1: "4", 2: "3", 3: "2"} {
+		data, err := os.ReadFile(backupPath(path, n))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(data), "backup %d", n)
+	}
+	assert.NoFileExists(t, backupPath(path, 4))
+
+	require.NoError(t, rotateBackups(path, 1))
+	matches, err := filepath.Glob(path + ".bak.*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{backupPath(path, 1)}, matches, "lowering the count drops the extra backups")
+}
+
+func TestRotateBackups_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configFileName)
+	require.NoError(t, os.WriteFile(path, []byte("1"), 0o644))
+
+	require.NoError(t, rotateBackups(path, 0))
+	assert.NoFileExists(t, backupPath(path, 1))
+}
+
+func TestDiskStore_BackupAndRestore(t *testing.T) {
+	path := savedConfigPath(t)
+	store := NewDiskStore()
+	require.NoError(t, store.Update(func(cfg *Config) error {
+		cfg.RemoveJob("https://jenkins/job/a/1")
+		return nil
+	}))
+	assert.FileExists(t, backupPath(path, 1))
+
+	require.NoError(t, RestoreBackup(1))
+	cfg, err := store.Load()
+	require.NoError(t, err, "a restored backup passes the checksum")
+	assert.True(t, cfg.HasJob("https://jenkins/job/a/1"))
+
+	// The config the restore replaced is now the newest backup.
+	require.NoError(t, RestoreBackup(1))
+	cfg, err = store.Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.HasJob("https://jenkins/job/a/1"))
+
+	assert.Error(t, RestoreBackup(DefaultBackupCount+1))
+	assert.Error(t, RestoreBackup(0))
+}
+
+func TestDiskStore_FrequentWritesKeepOldBackup(t *testing.T) {
+	path := savedConfigPath(t)
+	store := NewDiskStore()
+	require.NoError(t, store.Update(func(cfg *Config) error {
+		cfg.SetJobNote("https://jenkins/job/a/1", "first change")
+		return nil
+	}))
+	oldest, err := os.ReadFile(backupPath(path, 1))
+	require.NoError(t, err)
+
+	// Like the daemon recording polls: many small writes in a short time.
+	for i := range 50 {
+		require.NoError(t, store.Update(func(cfg *Config) error {
+			job := cfg.Jobs["https://jenkins/job/a/1"]
+			job.CheckFailureCount = i + 1
+			job.LastPollTime = time.Now()
+			cfg.Jobs["https://jenkins/job/a/1"] = job
+			return nil
+		}))
+	}
+
+	data, err := os.ReadFile(backupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, string(oldest), string(data), "the backup taken before the burst survives it")
+	assert.NoFileExists(t, backupPath(path, 2))
+
+	// Once the newest backup is old enough, the next save backs up again.
+	stale := time.Now().Add(-backupInterval)
+	require.NoError(t, os.Chtimes(backupPath(path, 1), stale, stale))
+	require.NoError(t, store.Update(func(cfg *Config) error {
+		cfg.SetJobNote("https://jenkins/job/a/1", "later change")
+		return nil
+	}))
+	data, err = os.ReadFile(backupPath(path, 2))
+	require.NoError(t, err)
+	assert.Equal(t, string(oldest), string(data))
+	cfg, err := loadBackup(t, backupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.Jobs["https://jenkins/job/a/1"].CheckFailureCount)
+}
+
+func loadBackup(t *testing.T, path string) (*Config, error) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	return &cfg, json.Unmarshal(data, &cfg)
+}
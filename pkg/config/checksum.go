@@ -0,0 +1,69 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrChecksumMismatch is returned when the config file's contents no longer
+// match the checksum jw stored with them.
+var ErrChecksumMismatch = errors.New("config checksum mismatch")
+
+// LoadWarning is told, once each, about problems in a loaded config that jw
+// works around: notification templates that do not parse, in place of which
+// the defaults are used. It prints to stderr unless replaced, as the daemon
+// does to log it instead.
+var LoadWarning = func(err error) {
+	fmt.Fprintln(os.Stderr, "Warning: "+err.Error())
+}
+
+var (
+	warnedMu sync.Mutex
+	warned   = make(map[string]bool)
+)
+
+// warnOnce calls LoadWarning with err unless it was already called for key.
+func warnOnce(key string, err error) {
+	warnedMu.Lock()
+	done := warned[key]
+	warned[key] = true
+	warnedMu.Unlock()
+	if !done {
+		LoadWarning(err)
+	}
+}
+
+// checksum returns the SHA-256 of c's JSON encoding without its Checksum.
+func (c *Config) checksum() (string, error) {
+	unsummed := *c
+	unsummed.Checksum = ""
+	data, err := json.Marshal(&unsummed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// verifyChecksum checks c against the checksum it was loaded with, if any,
+// and clears it. Files written before checksums existed have none and pass.
+func (c *Config) verifyChecksum() error {
+	want := c.Checksum
+	c.Checksum = ""
+	if want == "" {
+		return nil
+	}
+	got, err := c.checksum()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func savedConfigPath(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, NewDiskStore().Update(func(cfg *Config) error {
+		cfg.AddJob("https://jenkins/job/a/1")
+		cfg.SetJobNote("https://jenkins/job/a/1", "release")
+		return nil
+	}))
+	path, err := GetConfigPath()
+	require.NoError(t, err)
+	return path
+}
+
+func TestChecksum_WrittenAndVerified(t *testing.T) {
+	path := savedConfigPath(t)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Len(t, raw["checksum"], 64)
+
+	// Reformatting the file keeps the data, and so the checksum, the same.
+	var compact bytes.Buffer
+	require.NoError(t, json.Compact(&compact, data))
+	require.NoError(t, os.WriteFile(path, compact.Bytes(), 0o644))
+
+	cfg, err := NewDiskStore().Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Checksum, "the checksum is not kept in memory")
+	assert.Equal(t, "release", cfg.Jobs["https://jenkins/job/a/1"].Notes)
+}
+
+func TestChecksum_MutatedFile(t *testing.T) {
+	path := savedConfigPath(t)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, bytes.Replace(data, []byte(`"release"`), []byte(`"relaese"`), 1), 0o644))
+
+	_, err = NewDiskStore().Load()
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "jw clean --repair")
+	assert.ErrorIs(t, NewDiskStore().Update(func(*Config) error { return nil }), ErrChecksumMismatch)
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(after), `"relaese"`, "a failed update leaves the file alone")
+
+	ignoring := &DiskStore{IgnoreChecksum: true}
+	cfg, err := ignoring.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "relaese", cfg.Jobs["https://jenkins/job/a/1"].Notes)
+
+	// Saving through the ignoring store writes a fresh checksum.
+	require.NoError(t, ignoring.Save(cfg))
+	_, err = NewDiskStore().Load()
+	assert.NoError(t, err)
+}
+
+func TestChecksum_MissingIsAccepted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := GetConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{"https://jenkins/job/a/1":{"url":"https://jenkins/job/a/1"}}}`), 0o644))
+
+	cfg, err := NewDiskStore().Load()
+	require.NoError(t, err)
+	assert.Contains(t, cfg.Jobs, "https://jenkins/job/a/1")
+}
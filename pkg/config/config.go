@@ -2,29 +2,126 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"syscall"
 	"time"
+
+	"jenkins-monitor/pkg/paths"
 )
 
 const (
-	configDirName  = ".jw"
 	configFileName = "monitored_jobs.json"
 	lockFileName   = "config.lock"
 )
 
 type Job struct {
-	StartTime       time.Time `json:"start_time"`
-	URL             string    `json:"url"`
-	LastCheckFailed bool      `json:"last_check_failed,omitempty"`
+	StartTime       time.Time     `json:"start_time"`
+	URL             string        `json:"url"`
+	LastCheckFailed bool          `json:"last_check_failed,omitempty"`
+	PollInterval    time.Duration `json:"-"`
+	Profile         string        `json:"profile,omitempty"`
+	// Paused jobs stay in the config but are not polled by the daemon.
+	Paused bool `json:"paused,omitempty"`
+	// MaxDurationMinutes, if set, triggers a one-off alert when the build is
+	// still running this long after the job was added.
+	MaxDurationMinutes int `json:"max_duration_minutes,omitempty"`
+	// MaxMonitorHours, if set, is how long the job may stay monitored before
+	// it is dropped as stale. Zero means forever.
+	MaxMonitorHours float64 `json:"max_monitor_hours,omitempty"`
+	// Parameters are the build parameters, recorded when the daemon first
+	// sees the build running.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// TriggerCause describes who or what started the build, e.g. "John Doe".
+	TriggerCause string `json:"trigger_cause,omitempty"`
+	// BuildStartTimestamp is when Jenkins started the build, recorded on the
+	// first poll that sees it running. StartTime is when jw started watching.
+	BuildStartTimestamp time.Time `json:"build_start_timestamp,omitzero"`
+	// Recurring jobs stay monitored after a build finishes, waiting for the
+	// next one.
+	Recurring bool `json:"recurring,omitempty"`
+	// Notes is the user's reminder of why the job is watched.
+	Notes string `json:"notes,omitempty"`
+	// Labels group jobs, e.g. by project or team.
+	Labels []string `json:"labels,omitempty"`
+	// BuildNumber is the Jenkins build number, recorded once the daemon has
+	// polled the build.
+	BuildNumber int `json:"build_number,omitempty"`
+	// MaxRetries is how many status checks in a row may fail before the job
+	// is marked failed and an alert is sent. Zero means DefaultMaxRetries.
+	MaxRetries int `json:"max_retries,omitempty"`
+	// ConsecutiveFailures counts the failed status checks since the last
+	// successful one.
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+	// CheckFailureCount is the total number of failed status checks of the
+	// current build.
+	CheckFailureCount int `json:"check_failure_count,omitempty"`
+	// LastPollTime is when the daemon last checked the job.
+	LastPollTime time.Time `json:"last_poll_time,omitzero"`
+	// LastReportedBuild is when Jenkins started the last build of a recurring
+	// job that was reported finished, so a restarted daemon does not report
+	// it again.
+	LastReportedBuild time.Time `json:"last_reported_build,omitzero"`
+}
+
+// DefaultMaxRetries is Job.MaxRetries when it is unset.
+const DefaultMaxRetries = 3
+
+// RetryLimit returns how many failed checks in a row mark the job failed.
+func (j Job) RetryLimit() int {
+	if j.MaxRetries > 0 {
+		return j.MaxRetries
+	}
+	return DefaultMaxRetries
+}
+
+// HasLabel reports whether the job carries label.
+func (j Job) HasLabel(label string) bool {
+	return slices.Contains(j.Labels, label)
+}
+
+// TTL returns how long the job may be monitored, or 0 if there is no limit.
+func (j Job) TTL() time.Duration {
+	return time.Duration(j.MaxMonitorHours * float64(time.Hour))
+}
+
+// Expired reports whether the job has outlived its TTL at now.
+func (j Job) Expired(now time.Time) bool {
+	ttl := j.TTL()
+	return ttl > 0 && now.Sub(j.StartTime) > ttl
+}
+
+// jobJSON is the on-disk representation of Job. PollInterval is stored as
+// whole seconds to keep the file readable.
+type jobJSON struct {
+	jobAlias
+	PollIntervalSeconds int64 `json:"poll_interval_seconds,omitempty"`
+}
+
+type jobAlias Job
+
+func (j Job) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jobJSON{
+		jobAlias:            jobAlias(j),
+		PollIntervalSeconds: int64(j.PollInterval / time.Second),
+	})
 }
 
-type UpgradeCheck struct {
-	LastChecked   time.Time `json:"last_checked"`
-	LatestVersion string    `json:"latest_version"`
+func (j *Job) UnmarshalJSON(data []byte) error {
+	var aux jobJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*j = Job(aux.jobAlias)
+	j.PollInterval = time.Duration(aux.PollIntervalSeconds) * time.Second
+	return nil
 }
 
 const maxHistoryEntries = 10
@@ -36,22 +133,60 @@ type HistoryEntry struct {
 	StartTime    time.Time `json:"start_time"`
 }
 
+// defaultMaxCompletionHistory caps each job's CompletionHistory when
+// Config.MaxCompletionHistory is unset.
+const defaultMaxCompletionHistory = 50
+
+// BuildRecord is one finished build of a job.
+type BuildRecord struct {
+	FinishedAt time.Time     `json:"finished_at"`
+	Result     string        `json:"result"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// NotificationConfig holds settings for notification destinations beyond the
+// local desktop notifier.
+type NotificationConfig struct {
+	SlackWebhookURL   string `json:"slack_webhook_url,omitempty"`
+	DiscordWebhookURL string `json:"discord_webhook_url,omitempty"`
+	TelegramBotToken  string `json:"telegram_bot_token,omitempty"`
+	TelegramChatID    string `json:"telegram_chat_id,omitempty"`
+}
+
+// WebhookConfig configures the generic HTTP webhook notification destination.
+type WebhookConfig struct {
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
 type Config struct {
-	Jobs         map[string]Job `json:"jobs"`
-	History      []HistoryEntry `json:"history,omitempty"`
-	UpgradeState UpgradeCheck   `json:"upgrade_check"`
+	Jobs          map[string]Job     `json:"jobs"`
+	History       []HistoryEntry     `json:"history,omitempty"`
+	Notifications NotificationConfig `json:"notifications"`
+	Webhook       WebhookConfig      `json:"webhook,omitzero"`
+	QuietHours    QuietHours         `json:"quiet_hours,omitzero"`
+	// CompletionHistory records finished builds per job, keyed by job URL
+	// without a build number. It lives outside Jobs because monitored entries
+	// are removed once their build finishes.
+	CompletionHistory    map[string][]BuildRecord `json:"completion_history,omitempty"`
+	MaxCompletionHistory int                      `json:"max_completion_history,omitempty"`
+	// NotifyTitleTemplate and NotifyBodyTemplate are text/template sources
+	// for finished-build notifications, executed with NotificationData.
+	NotifyTitleTemplate string   `json:"notify_title_template,omitempty"`
+	NotifyBodyTemplate  string   `json:"notify_body_template,omitempty"`
+	Settings            Settings `json:"settings,omitzero"`
+	// Checksum is the SHA-256 of the other fields, written with the file and
+	// checked when it is read back. It is empty in memory.
+	Checksum string `json:"checksum,omitempty"`
 }
 
-func getConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, configDirName), nil
+// GetConfigDir returns the directory holding jw config, credentials and state.
+func GetConfigDir() (string, error) {
+	return paths.ConfigDir()
 }
 
 func GetConfigPath() (string, error) {
-	configDir, err := getConfigDir()
+	configDir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
@@ -59,7 +194,7 @@ func GetConfigPath() (string, error) {
 }
 
 func getLockPath() (string, error) {
-	configDir, err := getConfigDir()
+	configDir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
@@ -91,8 +226,23 @@ func withFileLock(fn func() error) error {
 	return fn()
 }
 
-// loadFromDisk reads the config file from disk.
-func loadFromDisk() (*Config, error) {
+// Check reports what loading c, as decoded from the config file, would warn
+// about or reject: a checksum that does not match and invalid notification
+// templates.
+func (c *Config) Check() error {
+	summed := *c
+	if err := summed.verifyChecksum(); err != nil {
+		return err
+	}
+	_, _, err := c.NotificationTemplates()
+	return err
+}
+
+// loadFromDisk reads the config file from disk, failing with
+// ErrChecksumMismatch if it was changed behind jw's back unless
+// ignoreChecksum is set. Invalid notification templates are reported to
+// LoadWarning; notifications then use the defaults.
+func loadFromDisk(ignoreChecksum bool) (*Config, error) {
 	path, err := GetConfigPath()
 	if err != nil {
 		return nil, err
@@ -110,6 +260,16 @@ func loadFromDisk() (*Config, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	if err := config.verifyChecksum(); err != nil && !ignoreChecksum {
+		if errors.Is(err, ErrChecksumMismatch) {
+			return nil, fmt.Errorf("%w: %s was changed outside jw or is corrupt; if its contents are right, run 'jw clean --repair'", err, path)
+		}
+		return nil, err
+	}
+
+	if _, _, err := config.NotificationTemplates(); err != nil {
+		warnOnce("template\x00"+config.NotifyTitleTemplate+"\x00"+config.NotifyBodyTemplate, fmt.Errorf("%w; using the default notification templates", err))
+	}
 
 	if config.Jobs == nil {
 		config.Jobs = make(map[string]Job)
@@ -117,23 +277,46 @@ func loadFromDisk() (*Config, error) {
 	return &config, nil
 }
 
-func saveToDisk(config *Config) error {
+// saveToDisk writes config and returns the bytes the file now holds.
+func saveToDisk(config *Config) ([]byte, error) {
 	path, err := GetConfigPath()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	configDir := filepath.Dir(path)
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		return err
+		return nil, err
 	}
 
-	data, err := json.MarshalIndent(config, "", "  ")
+	sum, err := config.checksum()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	summed := *config
+	summed.Checksum = sum
+	data, err := json.MarshalIndent(&summed, "", "  ")
+	if err != nil {
+		return nil, err
 	}
 
-	return os.WriteFile(path, data, 0o644)
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, data) {
+		return data, nil
+	}
+	if backupDue(path, time.Now()) {
+		if err := rotateBackups(path, config.Settings.backupCount()); err != nil {
+			return nil, fmt.Errorf("backing up config: %w", err)
+		}
+	}
+
+	err = writeFileAtomic(path, 0o644, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (c *Config) AddJob(jobURL string) {
@@ -156,11 +339,38 @@ func (c *Config) FinishJob(jobURL string, result string) {
 		return
 	}
 	delete(c.Jobs, jobURL)
+	c.recordHistory(jobURL, job.StartTime, result)
+}
+
+// RecurJob records the finished build of a recurring job like FinishJob, but
+// keeps the job, resetting what it knew about that build so the next one is
+// watched from now.
+func (c *Config) RecurJob(jobURL string, result string, buildStarted time.Time) {
+	job, exists := c.Jobs[jobURL]
+	if !exists {
+		return
+	}
+	if !buildStarted.IsZero() {
+		job.LastReportedBuild = buildStarted
+	}
+	c.recordHistory(jobURL, job.StartTime, result)
+	job.StartTime = time.Now()
+	job.LastCheckFailed = false
+	job.ConsecutiveFailures = 0
+	job.CheckFailureCount = 0
+	job.Parameters = nil
+	job.TriggerCause = ""
+	job.BuildStartTimestamp = time.Time{}
+	job.BuildNumber = 0
+	c.Jobs[jobURL] = job
+}
+
+func (c *Config) recordHistory(jobURL string, started time.Time, result string) {
 	entry := HistoryEntry{
 		URL:          jobURL,
 		Result:       result,
 		FinishedTime: time.Now(),
-		StartTime:    job.StartTime,
+		StartTime:    started,
 	}
 	c.History = append([]HistoryEntry{entry}, c.History...)
 	if len(c.History) > maxHistoryEntries {
@@ -168,15 +378,123 @@ func (c *Config) FinishJob(jobURL string, result string) {
 	}
 }
 
+// RecordCompletion appends rec to the history of jobKey, dropping the oldest
+// records beyond the configured maximum.
+func (c *Config) RecordCompletion(jobKey string, rec BuildRecord) {
+	if c.CompletionHistory == nil {
+		c.CompletionHistory = make(map[string][]BuildRecord)
+	}
+	limit := c.MaxCompletionHistory
+	if limit <= 0 {
+		limit = defaultMaxCompletionHistory
+	}
+	records := append(c.CompletionHistory[jobKey], rec)
+	if len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	c.CompletionHistory[jobKey] = records
+}
+
+// RenameJob moves the job at oldURL to newURL, keeping its settings, and
+// points its history at the new URL. What the daemon learned by polling the
+// old URL is cleared. oldKey and newKey are the jobs' URLs without a build
+// number, under which CompletionHistory is kept.
+func (c *Config) RenameJob(oldURL, newURL, oldKey, newKey string) error {
+	job, exists := c.Jobs[oldURL]
+	if !exists {
+		return fmt.Errorf("job not found in config: %s", oldURL)
+	}
+	if oldURL == newURL {
+		return nil
+	}
+	if c.HasJob(newURL) {
+		return fmt.Errorf("job already monitored: %s", newURL)
+	}
+	delete(c.Jobs, oldURL)
+	job.URL = newURL
+	job.BuildNumber = 0
+	job.LastCheckFailed = false
+	job.ConsecutiveFailures = 0
+	job.CheckFailureCount = 0
+	job.LastPollTime = time.Time{}
+	job.LastReportedBuild = time.Time{}
+	c.Jobs[newURL] = job
+	for i := range c.History {
+		if c.History[i].URL == oldURL {
+			c.History[i].URL = newURL
+		}
+	}
+	if records, ok := c.CompletionHistory[oldKey]; ok && oldKey != newKey {
+		delete(c.CompletionHistory, oldKey)
+		c.CompletionHistory[newKey] = append(c.CompletionHistory[newKey], records...)
+	}
+	return nil
+}
+
+// SetJobPaused pauses or resumes a job. It returns true if the job exists and
+// its state changed.
+func (c *Config) SetJobPaused(jobURL string, paused bool) bool {
+	job, exists := c.Jobs[jobURL]
+	if !exists || job.Paused == paused {
+		return false
+	}
+	job.Paused = paused
+	c.Jobs[jobURL] = job
+	return true
+}
+
+// SetJobParameters records the build parameters of a job unless they are
+// already known. It reports whether the job changed.
+func (c *Config) SetJobParameters(jobURL string, params map[string]string) bool {
+	job, exists := c.Jobs[jobURL]
+	if !exists || job.Parameters != nil || len(params) == 0 {
+		return false
+	}
+	job.Parameters = maps.Clone(params)
+	c.Jobs[jobURL] = job
+	return true
+}
+
+// SetJobNote replaces the notes of the job at jobURL and reports whether the
+// job exists.
+func (c *Config) SetJobNote(jobURL, note string) bool {
+	job, exists := c.Jobs[jobURL]
+	if !exists {
+		return false
+	}
+	job.Notes = note
+	c.Jobs[jobURL] = job
+	return true
+}
+
 func (c *Config) HasJob(jobURL string) bool {
 	_, exists := c.Jobs[jobURL]
 	return exists
 }
 
+// clone returns a copy of c that shares no maps or slices with it.
+func (c *Config) clone() *Config {
+	out := *c
+	out.Jobs = c.GetJobs()
+	out.History = append([]HistoryEntry(nil), c.History...)
+	out.Webhook.Headers = maps.Clone(c.Webhook.Headers)
+	if c.CompletionHistory != nil {
+		out.CompletionHistory = make(map[string][]BuildRecord, len(c.CompletionHistory))
+		for k, records := range c.CompletionHistory {
+			out.CompletionHistory[k] = append([]BuildRecord(nil), records...)
+		}
+	}
+	return &out
+}
+
 func (c *Config) GetJobs() map[string]Job {
 	// Return a copy to prevent concurrent map access
 	jobs := make(map[string]Job, len(c.Jobs))
-	maps.Copy(jobs, c.Jobs)
+	for url, job := range c.Jobs {
+		job.Parameters = maps.Clone(job.Parameters)
+		job.Labels = slices.Clone(job.Labels)
+		jobs[url] = job
+	}
 	return jobs
 }
 
@@ -1,11 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAddJob(t *testing.T) {
@@ -63,6 +66,54 @@ func TestUpdateJobCheckStatus_NonExistentJob(t *testing.T) {
 	assert.Equal(t, 0, len(c.Jobs), "should not create job when updating non-existent")
 }
 
+func TestSetJobPaused(t *testing.T) {
+	c := &Config{Jobs: make(map[string]Job)}
+	url := "http://jenkins/job/test"
+	c.AddJob(url)
+
+	assert.True(t, c.SetJobPaused(url, true))
+	assert.True(t, c.Jobs[url].Paused)
+	assert.False(t, c.SetJobPaused(url, true), "pausing a paused job is a no-op")
+
+	assert.True(t, c.SetJobPaused(url, false))
+	assert.False(t, c.Jobs[url].Paused)
+	assert.False(t, c.SetJobPaused(url, false), "resuming a running job is a no-op")
+
+	assert.False(t, c.SetJobPaused("http://jenkins/job/missing", true))
+	assert.Len(t, c.Jobs, 1)
+}
+
+func TestSetJobParameters(t *testing.T) {
+	c := &Config{Jobs: make(map[string]Job)}
+	url := "http://jenkins/job/test/1"
+	c.AddJob(url)
+
+	params := map[string]string{"BRANCH": "main"}
+	assert.True(t, c.SetJobParameters(url, params))
+	params["BRANCH"] = "changed"
+	assert.Equal(t, map[string]string{"BRANCH": "main"}, c.Jobs[url].Parameters)
+
+	assert.False(t, c.SetJobParameters(url, map[string]string{"BRANCH": "other"}), "parameters are only recorded once")
+	assert.Equal(t, "main", c.Jobs[url].Parameters["BRANCH"])
+	assert.False(t, c.SetJobParameters("http://jenkins/job/missing/1", params))
+
+	jobs := c.GetJobs()
+	jobs[url].Parameters["BRANCH"] = "mutated"
+	assert.Equal(t, "main", c.Jobs[url].Parameters["BRANCH"], "GetJobs must not share parameter maps")
+}
+
+func TestJobExpired(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	job := Job{StartTime: start, MaxMonitorHours: 0.5}
+
+	assert.Equal(t, 30*time.Minute, job.TTL())
+	assert.False(t, job.Expired(start.Add(29*time.Minute)))
+	assert.True(t, job.Expired(start.Add(31*time.Minute)))
+
+	job.MaxMonitorHours = 0
+	assert.False(t, job.Expired(start.Add(1000*time.Hour)), "zero TTL never expires")
+}
+
 func TestFinishJob(t *testing.T) {
 	c := &Config{Jobs: make(map[string]Job)}
 	url := "http://jenkins/job/test"
@@ -84,6 +135,67 @@ func TestFinishJob_NonExistent(t *testing.T) {
 	assert.Empty(t, c.History)
 }
 
+func TestRecurJob(t *testing.T) {
+	url := "http://jenkins/job/test/lastBuild"
+	started := time.Now().Add(-time.Hour)
+	c := &Config{Jobs: map[string]Job{url: {
+		URL:                 url,
+		StartTime:           started,
+		Recurring:           true,
+		LastCheckFailed:     true,
+		Parameters:          map[string]string{"ENV": "prod"},
+		TriggerCause:        "John Doe",
+		BuildStartTimestamp: started,
+	}}}
+
+	buildStarted := time.UnixMilli(1700000000000)
+	c.RecurJob(url, "FAILURE", buildStarted)
+
+	require.True(t, c.HasJob(url), "recurring job should stay monitored")
+	job := c.Jobs[url]
+	assert.True(t, job.Recurring)
+	assert.True(t, job.StartTime.After(started))
+	assert.False(t, job.LastCheckFailed)
+	assert.Nil(t, job.Parameters)
+	assert.Empty(t, job.TriggerCause)
+	assert.True(t, job.BuildStartTimestamp.IsZero())
+	assert.True(t, buildStarted.Equal(job.LastReportedBuild))
+
+	require.Len(t, c.History, 1)
+	assert.Equal(t, "FAILURE", c.History[0].Result)
+	assert.Equal(t, started, c.History[0].StartTime)
+}
+
+func TestSetJobNote(t *testing.T) {
+	url := "http://jenkins/job/test/1"
+	c := &Config{Jobs: make(map[string]Job)}
+	c.AddJob(url)
+
+	assert.True(t, c.SetJobNote(url, "waiting for hotfix deploy"))
+	assert.Equal(t, "waiting for hotfix deploy", c.Jobs[url].Notes)
+
+	assert.True(t, c.SetJobNote(url, ""))
+	assert.Empty(t, c.Jobs[url].Notes)
+
+	assert.False(t, c.SetJobNote("http://jenkins/job/nope/1", "note"))
+	assert.NotContains(t, c.Jobs, "http://jenkins/job/nope/1")
+}
+
+func TestJobNotes_RoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := NewDiskStore()
+	url := "http://jenkins/job/test/1"
+	require.NoError(t, store.Update(func(cfg *Config) error {
+		cfg.AddJob(url)
+		cfg.SetJobNote(url, "waiting for hotfix deploy")
+		return nil
+	}))
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "waiting for hotfix deploy", cfg.Jobs[url].Notes)
+}
+
 func TestFinishJob_TrimsToMax(t *testing.T) {
 	c := &Config{Jobs: make(map[string]Job)}
 
@@ -189,7 +301,7 @@ func TestLoad_ReturnsCachedInstance(t *testing.T) {
 	assert.NoError(t, err, "failed to load config: %v", err)
 	assert.NotSame(t, cfg, cfg2, "Load() should return a fresh instance")
 
-	diskCfg, err := loadFromDisk()
+	diskCfg, err := loadFromDisk(false)
 	assert.NoError(t, err, "failed to load from disk: %v", err)
 	delete(diskCfg.Jobs, url)
 	err = store.Save(diskCfg)
@@ -199,3 +311,83 @@ func TestLoad_ReturnsCachedInstance(t *testing.T) {
 	assert.NoError(t, err, "failed to load config: %v", err)
 	assert.False(t, cachedCfg.HasJob(url), "Load() should return a fresh instance reflecting disk")
 }
+
+func TestJob_PollIntervalJSON(t *testing.T) {
+	job := Job{URL: "http://jenkins/job/test", PollInterval: 90 * time.Second}
+
+	data, err := json.Marshal(job)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"poll_interval_seconds":90`)
+
+	var decoded Job
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 90*time.Second, decoded.PollInterval)
+	assert.Equal(t, job.URL, decoded.URL)
+
+	var legacy Job
+	require.NoError(t, json.Unmarshal([]byte(`{"url":"http://jenkins/job/test"}`), &legacy))
+	assert.Zero(t, legacy.PollInterval)
+}
+
+func TestMemoryStore_IsolatesCopies(t *testing.T) {
+	store := NewMemoryStore()
+	url := "http://jenkins/job/test"
+
+	require.NoError(t, store.Update(func(c *Config) error {
+		c.AddJob(url)
+		return nil
+	}))
+
+	cfg, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.HasJob(url))
+
+	cfg.RemoveJob(url)
+	reloaded, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, reloaded.HasJob(url), "mutating a loaded config must not affect the store")
+}
+
+func TestRecordCompletion_Caps(t *testing.T) {
+	cfg := &Config{MaxCompletionHistory: 3}
+	for i := range 5 {
+		cfg.RecordCompletion("https://j/job/a", BuildRecord{Result: fmt.Sprintf("R%d", i)})
+	}
+
+	records := cfg.CompletionHistory["https://j/job/a"]
+	require.Len(t, records, 3)
+	assert.Equal(t, "R2", records[0].Result)
+	assert.Equal(t, "R4", records[2].Result)
+
+	cfg = &Config{}
+	for range defaultMaxCompletionHistory + 5 {
+		cfg.RecordCompletion("https://j/job/a", BuildRecord{})
+	}
+	assert.Len(t, cfg.CompletionHistory["https://j/job/a"], defaultMaxCompletionHistory)
+}
+
+func TestDiskStoreUpdate_SkipsUnchanged(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := NewDiskStore()
+	require.NoError(t, store.Update(func(c *Config) error {
+		c.AddJob("http://jenkins/job/test")
+		return nil
+	}))
+	path, err := GetConfigPath()
+	require.NoError(t, err)
+	before, err := os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Update(func(c *Config) error { return nil }))
+	after, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(before, after), "an Update that changes nothing does not rewrite the file")
+
+	require.NoError(t, store.Update(func(c *Config) error {
+		c.SetJobNote("http://jenkins/job/test", "changed")
+		return nil
+	}))
+	after, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(before, after))
+}
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 )
 
 // ErrNoCredentials is returned when no Jenkins credentials are configured.
@@ -14,56 +21,201 @@ var ErrNoCredentials = errors.New("Jenkins credentials not set.\n- run 'jw auth'
 
 const credentialsFileName = ".credentials"
 
+// DefaultProfile is the credential profile used when none is specified.
+const DefaultProfile = "default"
+
+// credentialsKeyEnv names the environment variable holding the hex-encoded
+// AES-256 key used to encrypt the credentials file at rest.
+const credentialsKeyEnv = "JW_CREDENTIALS_KEY"
+
+// encryptedPrefix marks an encrypted credentials file. Plain files are JSON
+// and therefore never start with it.
+var encryptedPrefix = []byte("jwenc:v1:")
+
 type Credentials struct {
-	Username string `json:"username,omitempty"`
-	Token    string `json:"token"`
+	Username  string `json:"username,omitempty"`
+	Token     string `json:"token"`
+	TokenUUID string `json:"token_uuid,omitempty"`
+	BaseURL   string `json:"base_url,omitempty"`
 }
 
-// GetCredentials returns the base64-encoded credentials for Jenkins Basic Auth.
-// It supports two modes:
+// EncodedToken returns the value for a Basic Authorization header. Without a
+// username the stored token is assumed to be pre-encoded (legacy).
+func (c *Credentials) EncodedToken() string {
+	if c.Username == "" {
+		return c.Token
+	}
+	return base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Token))
+}
+
+// GetCredentials returns the base64-encoded credentials for Jenkins Basic Auth
+// using the default profile.
+func GetCredentials() (string, error) {
+	return GetProfileCredentials(DefaultProfile)
+}
+
+// GetProfileCredentials returns the base64-encoded credentials for Jenkins
+// Basic Auth. It supports three modes:
 // 1. JENKINS_USER + JENKINS_API_TOKEN: Combined and base64-encoded (like curl -u user:token)
 // 2. JENKINS_TOKEN: Used as-is (legacy, expects pre-encoded value)
-// 3. ~/.jw/.credentials file: Checks for stored credentials if env vars are missing
-func GetCredentials() (string, error) {
+// 3. .credentials in the config directory: The named profile, if env vars are missing
+//
+// The environment variables only apply to the default profile.
+func GetProfileCredentials(profile string) (string, error) {
+	profile = profileName(profile)
+
 	// 1. Check environment variables first (highest priority)
-	user := os.Getenv("JENKINS_USER")
-	apiToken := os.Getenv("JENKINS_API_TOKEN")
+	if profile == DefaultProfile {
+		user := os.Getenv("JENKINS_USER")
+		apiToken := os.Getenv("JENKINS_API_TOKEN")
 
-	if user != "" && apiToken != "" {
-		credentials := user + ":" + apiToken
-		return base64.StdEncoding.EncodeToString([]byte(credentials)), nil
-	}
+		if user != "" && apiToken != "" {
+			credentials := user + ":" + apiToken
+			return base64.StdEncoding.EncodeToString([]byte(credentials)), nil
+		}
 
-	token := os.Getenv("JENKINS_TOKEN")
-	if token != "" {
-		return token, nil
+		token := os.Getenv("JENKINS_TOKEN")
+		if token != "" {
+			return token, nil
+		}
 	}
 
 	// 2. Check credentials file
-	creds, err := LoadCredentials()
+	creds, err := LoadProfile(profile)
 	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return "", fmt.Errorf("loading credentials file: %w", err)
 	}
-	if creds != nil && creds.Username != "" && creds.Token != "" {
-		credentials := creds.Username + ":" + creds.Token
-		return base64.StdEncoding.EncodeToString([]byte(credentials)), nil
-	}
 	if creds != nil && creds.Token != "" {
-		return creds.Token, nil
+		return creds.EncodedToken(), nil
 	}
 
+	if profile != DefaultProfile {
+		return "", fmt.Errorf("no credentials for profile %q: run 'jw auth --profile %s'", profile, profile)
+	}
 	return "", ErrNoCredentials
 }
 
 func GetCredentialsPath() (string, error) {
-	configDir, err := getConfigDir()
+	configDir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(configDir, credentialsFileName), nil
 }
 
+// LoadCredentials returns the credentials of the default profile.
 func LoadCredentials() (*Credentials, error) {
+	return LoadProfile(DefaultProfile)
+}
+
+// LoadProfile returns the credentials stored under profile in the
+// credentials file, or in the system keychain if the file has no such
+// profile. A missing profile yields an error wrapping os.ErrNotExist.
+func LoadProfile(profile string) (*Credentials, error) {
+	profile = profileName(profile)
+	profiles, err := readCredentialsFile()
+	if creds, ok := profiles[profile]; ok {
+		return creds, nil
+	}
+
+	if creds, kerr := loadKeychainCredentials(profile); kerr == nil {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("credential profile %q not found: %w", profile, os.ErrNotExist)
+}
+
+// ListProfiles returns the sorted names of the profiles in the credentials
+// file and the system keychain.
+func ListProfiles() ([]string, error) {
+	profiles, err := readCredentialsFile()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(profiles)+1)
+	for name := range profiles {
+		names = append(names, name)
+	}
+	for _, name := range keychainProfiles() {
+		if _, ok := profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	// The default profile may predate the keychain's list of profiles.
+	if !slices.Contains(names, DefaultProfile) {
+		if _, err := loadKeychainCredentials(DefaultProfile); err == nil {
+			names = append(names, DefaultProfile)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SaveCredentials stores creds as the default profile.
+func SaveCredentials(creds *Credentials) error {
+	return SaveProfile(DefaultProfile, creds)
+}
+
+// SaveProfile stores creds under profile, keeping the other profiles intact.
+func SaveProfile(profile string, creds *Credentials) error {
+	profiles, err := readCredentialsFile()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if profiles == nil {
+		profiles = map[string]*Credentials{}
+	}
+	profiles[profileName(profile)] = creds
+	return writeCredentialsFile(profiles)
+}
+
+// RemoveCredentials deletes the default profile.
+func RemoveCredentials() error {
+	return RemoveProfile(DefaultProfile)
+}
+
+// RemoveProfile deletes profile from the credentials file and the keychain.
+// The file itself is removed once no profiles remain. It is not an error if
+// the profile does not exist.
+func RemoveProfile(profile string) error {
+	profile = profileName(profile)
+	if err := deleteKeychainCredentials(profile); err != nil {
+		return err
+	}
+
+	profiles, err := readCredentialsFile()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles[profile]; !ok {
+		return nil
+	}
+	delete(profiles, profile)
+	return writeCredentialsFile(profiles)
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// credentialsFile is the on-disk layout of the .credentials file.
+type credentialsFile struct {
+	Profiles map[string]*Credentials `json:"profiles"`
+}
+
+// readCredentialsFile returns every profile in the credentials file,
+// decrypting it if needed. A legacy file holding a single set of credentials
+// is read as the default profile.
+func readCredentialsFile() (map[string]*Credentials, error) {
 	path, err := GetCredentialsPath()
 	if err != nil {
 		return nil, err
@@ -74,30 +226,128 @@ func LoadCredentials() (*Credentials, error) {
 		return nil, err
 	}
 
-	var creds Credentials
-	if err := json.Unmarshal(data, &creds); err != nil {
+	if bytes.HasPrefix(data, encryptedPrefix) {
+		key, err := credentialsKey()
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			return nil, fmt.Errorf("credentials file is encrypted: set %s to decrypt it", credentialsKeyEnv)
+		}
+		if data, err = decryptCredentials(key, data[len(encryptedPrefix):]); err != nil {
+			return nil, err
+		}
+	}
+
+	var file credentialsFile
+	if err := json.Unmarshal(data, &file); err != nil {
 		return nil, err
 	}
+	if file.Profiles != nil {
+		return file.Profiles, nil
+	}
 
-	return &creds, nil
+	var legacy Credentials
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+	return map[string]*Credentials{DefaultProfile: &legacy}, nil
 }
 
-func SaveCredentials(creds *Credentials) error {
+// writeCredentialsFile replaces the credentials file with profiles, removing
+// it when profiles is empty.
+func writeCredentialsFile(profiles map[string]*Credentials) error {
 	path, err := GetCredentialsPath()
 	if err != nil {
 		return err
 	}
 
+	if len(profiles) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
 	configDir := filepath.Dir(path)
 	if err := os.MkdirAll(configDir, 0o755); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(creds, "", "  ")
+	data, err := json.MarshalIndent(credentialsFile{Profiles: profiles}, "", "  ")
 	if err != nil {
 		return err
 	}
 
+	key, err := credentialsKey()
+	if err != nil {
+		return err
+	}
+	if key != nil {
+		if data, err = encryptCredentials(key, data); err != nil {
+			return err
+		}
+	}
+
 	// Save with strict permissions, as these are sensitive
 	return os.WriteFile(path, data, 0o600)
 }
+
+// credentialsKey returns the key from JW_CREDENTIALS_KEY, or nil when it is
+// unset and the credentials file should be stored in plain text.
+func credentialsKey() ([]byte, error) {
+	raw := os.Getenv(credentialsKeyEnv)
+	if raw == "" {
+		return nil, nil
+	}
+	key, err := hex.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 64 hex characters (a 32-byte key)", credentialsKeyEnv)
+	}
+	return key, nil
+}
+
+// encryptCredentials seals plaintext with AES-256-GCM and returns the file
+// contents: the prefix followed by base64(nonce || ciphertext).
+func encryptCredentials(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	out := make([]byte, len(encryptedPrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, encryptedPrefix)
+	base64.StdEncoding.Encode(out[len(encryptedPrefix):], sealed)
+	return out, nil
+}
+
+// decryptCredentials reverses encryptCredentials for the data after the prefix.
+func decryptCredentials(key, encoded []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("credentials file is corrupted")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt credentials: wrong %s or corrupted file", credentialsKeyEnv)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestCredentials_EncryptedRoundTrip(t *testing.T) {
+	useFakeKeychain(t)
+	t.Setenv(credentialsKeyEnv, testKey)
+
+	creds := &Credentials{Username: "alice", Token: "s3cret", BaseURL: "https://jenkins.example.com"}
+	require.NoError(t, SaveCredentials(creds))
+
+	path, err := GetCredentialsPath()
+	require.NoError(t, err)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), string(encryptedPrefix)))
+	assert.NotContains(t, string(raw), "s3cret")
+
+	loaded, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, creds, loaded)
+}
+
+func TestCredentials_PlainFileStillLoads(t *testing.T) {
+	useFakeKeychain(t)
+	t.Setenv(credentialsKeyEnv, "")
+	require.NoError(t, SaveCredentials(&Credentials{Username: "alice", Token: "plain"}))
+
+	// Setting a key later must not break an existing plain-text file.
+	t.Setenv(credentialsKeyEnv, testKey)
+	loaded, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "plain", loaded.Token)
+}
+
+func TestCredentials_WrongKey(t *testing.T) {
+	useFakeKeychain(t)
+	t.Setenv(credentialsKeyEnv, testKey)
+	require.NoError(t, SaveCredentials(&Credentials{Username: "alice", Token: "s3cret"}))
+
+	t.Setenv(credentialsKeyEnv, strings.Repeat("ff", 32))
+	_, err := LoadCredentials()
+	assert.ErrorContains(t, err, "wrong JW_CREDENTIALS_KEY")
+
+	t.Setenv(credentialsKeyEnv, "")
+	_, err = LoadCredentials()
+	assert.ErrorContains(t, err, "encrypted")
+}
+
+func TestCredentialsKey_Invalid(t *testing.T) {
+	for _, key := range []string{"not-hex", "0011"} {
+		t.Setenv(credentialsKeyEnv, key)
+		_, err := credentialsKey()
+		assert.Error(t, err, key)
+	}
+}
+
+func TestProfiles_SaveLoadList(t *testing.T) {
+	useFakeKeychain(t)
+	t.Setenv(credentialsKeyEnv, "")
+
+	def := &Credentials{Username: "alice", Token: "d", BaseURL: "https://jenkins.example.com"}
+	staging := &Credentials{Username: "bob", Token: "s", BaseURL: "https://staging.example.com"}
+	require.NoError(t, SaveCredentials(def))
+	require.NoError(t, SaveProfile("staging", staging))
+
+	loaded, err := LoadProfile("staging")
+	require.NoError(t, err)
+	assert.Equal(t, staging, loaded)
+
+	loaded, err = LoadProfile("")
+	require.NoError(t, err)
+	assert.Equal(t, def, loaded)
+
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "staging"}, names)
+
+	_, err = LoadProfile("prod")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = GetProfileCredentials("prod")
+	assert.ErrorContains(t, err, `profile "prod"`)
+
+	token, err := GetProfileCredentials("staging")
+	require.NoError(t, err)
+	assert.Equal(t, staging.EncodedToken(), token)
+}
+
+func TestProfiles_LegacyFileIsDefault(t *testing.T) {
+	useFakeKeychain(t)
+	path, err := GetCredentialsPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"username":"alice","token":"legacy"}`), 0o600))
+
+	loaded, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", loaded.Token)
+
+	// Saving another profile migrates the file and keeps the legacy entry.
+	require.NoError(t, SaveProfile("staging", &Credentials{Username: "bob", Token: "s"}))
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "staging"}, names)
+	loaded, err = LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", loaded.Token)
+}
+
+func TestProfiles_EnvOnlyAppliesToDefault(t *testing.T) {
+	useFakeKeychain(t)
+	t.Setenv("JENKINS_TOKEN", "from-env")
+	require.NoError(t, SaveProfile("staging", &Credentials{Token: "pre-encoded"}))
+
+	token, err := GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", token)
+
+	token, err = GetProfileCredentials("staging")
+	require.NoError(t, err)
+	assert.Equal(t, "pre-encoded", token)
+}
+
+func TestRemoveProfile(t *testing.T) {
+	useFakeKeychain(t)
+	require.NoError(t, SaveCredentials(&Credentials{Token: "d"}))
+	require.NoError(t, SaveProfile("staging", &Credentials{Token: "s"}))
+
+	require.NoError(t, RemoveProfile("staging"))
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default"}, names)
+
+	require.NoError(t, RemoveProfile("default"))
+	path, err := GetCredentialsPath()
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	urlHintsFileName = "url_hints"
+	// legacyHintsFileName is read when url_hints does not exist.
+	legacyHintsFileName = "completion_hints"
+)
+
+// GetCompletionHintsPath returns the path of the file listing Jenkins URL
+// prefixes offered as shell completions, one per line.
+func GetCompletionHintsPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, urlHintsFileName), nil
+}
+
+// LoadCompletionHints reads the hints file, falling back to the older
+// completion_hints file. A missing file yields no hints.
+func LoadCompletionHints() ([]string, error) {
+	path, err := GetCompletionHintsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	hints, err := readHintsFile(path)
+	if os.IsNotExist(err) {
+		hints, err = readHintsFile(filepath.Join(filepath.Dir(path), legacyHintsFileName))
+	}
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return hints, err
+}
+
+func readHintsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hints []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hints = append(hints, line)
+	}
+	return hints, scanner.Err()
+}
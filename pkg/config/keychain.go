@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+)
+
+const (
+	keychainService        = "jw"
+	keychainCredentialsKey = "credentials"
+	// keychainProfilesKey holds the names of the profiles in the keychain,
+	// which cannot be listed otherwise.
+	keychainProfilesKey = "profiles"
+)
+
+var (
+	// ErrCredentialNotFound is returned by a CredentialBackend when nothing is
+	// stored under the requested key.
+	ErrCredentialNotFound = errors.New("credential not found")
+	// ErrKeychainUnavailable is returned when the platform keychain cannot be used.
+	ErrKeychainUnavailable = errors.New("system keychain is not available")
+)
+
+// CredentialBackend stores secret values by key.
+type CredentialBackend interface {
+	Save(key, value string) error
+	Load(key string) (string, error)
+	Delete(key string) error
+}
+
+// KeychainStore is a CredentialBackend backed by the system keychain: the
+// macOS login keychain or libsecret via secret-tool on Linux.
+type KeychainStore struct {
+	Service string
+}
+
+func NewKeychainStore() *KeychainStore {
+	return &KeychainStore{Service: keychainService}
+}
+
+// execCommand, lookPath and keychainBackend are variables for testability.
+var (
+	execCommand                       = exec.Command
+	lookPath                          = exec.LookPath
+	keychainBackend CredentialBackend = NewKeychainStore()
+)
+
+// SaveCredentialsToKeychain stores creds as the default profile in the system
+// keychain.
+func SaveCredentialsToKeychain(creds *Credentials) error {
+	return SaveProfileToKeychain(DefaultProfile, creds)
+}
+
+// SaveProfileToKeychain stores creds under profile in the system keychain and
+// drops the profile from the credentials file so the token is not left on disk.
+func SaveProfileToKeychain(profile string, creds *Credentials) error {
+	profile = profileName(profile)
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	if err := keychainBackend.Save(keychainKey(profile), string(data)); err != nil {
+		return err
+	}
+	if names := keychainProfiles(); !slices.Contains(names, profile) {
+		if err := saveKeychainProfiles(append(names, profile)); err != nil {
+			return err
+		}
+	}
+
+	profiles, err := readCredentialsFile()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles[profile]; !ok {
+		return nil
+	}
+	delete(profiles, profile)
+	return writeCredentialsFile(profiles)
+}
+
+// keychainKey returns the keychain account for profile. The default profile
+// keeps the unsuffixed key.
+func keychainKey(profile string) string {
+	if profile == DefaultProfile {
+		return keychainCredentialsKey
+	}
+	return keychainCredentialsKey + ":" + profile
+}
+
+// loadKeychainCredentials returns credentials stored in the keychain, or an
+// error if there are none or the keychain cannot be read.
+func loadKeychainCredentials(profile string) (*Credentials, error) {
+	value, err := keychainBackend.Load(keychainKey(profile))
+	if err != nil {
+		return nil, err
+	}
+	var creds Credentials
+	if err := json.Unmarshal([]byte(value), &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// deleteKeychainCredentials removes keychain-stored credentials, treating a
+// missing entry or an unavailable keychain as already deleted.
+func deleteKeychainCredentials(profile string) error {
+	err := keychainBackend.Delete(keychainKey(profile))
+	if errors.Is(err, ErrKeychainUnavailable) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, ErrCredentialNotFound) {
+		return err
+	}
+	if names := keychainProfiles(); slices.Contains(names, profile) {
+		return saveKeychainProfiles(slices.DeleteFunc(names, func(name string) bool { return name == profile }))
+	}
+	return nil
+}
+
+// keychainProfiles returns the names of the profiles saved to the keychain,
+// or nil if there are none or the keychain cannot be read.
+func keychainProfiles() []string {
+	value, err := keychainBackend.Load(keychainProfilesKey)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	if json.Unmarshal([]byte(value), &names) != nil {
+		return nil
+	}
+	return names
+}
+
+func saveKeychainProfiles(names []string) error {
+	if len(names) == 0 {
+		err := keychainBackend.Delete(keychainProfilesKey)
+		if errors.Is(err, ErrCredentialNotFound) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	return keychainBackend.Save(keychainProfilesKey, string(data))
+}
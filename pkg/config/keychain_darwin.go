@@ -0,0 +1,43 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityItemNotFound is the exit status of security(1) when no item matches.
+const securityItemNotFound = 44
+
+func (k *KeychainStore) Save(key, value string) error {
+	// -U updates an existing item instead of failing.
+	out, err := execCommand("security", "add-generic-password", "-U", "-s", k.Service, "-a", key, "-w", value).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("saving to keychain: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (k *KeychainStore) Load(key string) (string, error) {
+	out, err := execCommand("security", "find-generic-password", "-s", k.Service, "-a", key, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func (k *KeychainStore) Delete(key string) error {
+	if err := execCommand("security", "delete-generic-password", "-s", k.Service, "-a", key).Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return ErrCredentialNotFound
+	}
+	return fmt.Errorf("keychain: %w", err)
+}
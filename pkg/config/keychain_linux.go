@@ -0,0 +1,51 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func (k *KeychainStore) Save(key, value string) error {
+	if _, err := lookPath("secret-tool"); err != nil {
+		return fmt.Errorf("%w: secret-tool not found (install libsecret-tools)", ErrKeychainUnavailable)
+	}
+	// The secret is read from stdin so it never appears in the process list.
+	cmd := execCommand("secret-tool", "store", "--label=jw Jenkins credentials", "service", k.Service, "account", key)
+	cmd.Stdin = strings.NewReader(value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("saving to keychain: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+func (k *KeychainStore) Load(key string) (string, error) {
+	if _, err := lookPath("secret-tool"); err != nil {
+		return "", ErrKeychainUnavailable
+	}
+	out, err := execCommand("secret-tool", "lookup", "service", k.Service, "account", key).Output()
+	if err != nil {
+		// secret-tool exits non-zero without a message when nothing matches.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+			return "", ErrCredentialNotFound
+		}
+		return "", fmt.Errorf("keychain: %w", err)
+	}
+	if len(out) == 0 {
+		return "", ErrCredentialNotFound
+	}
+	return string(out), nil
+}
+
+func (k *KeychainStore) Delete(key string) error {
+	if _, err := lookPath("secret-tool"); err != nil {
+		return ErrKeychainUnavailable
+	}
+	if err := execCommand("secret-tool", "clear", "service", k.Service, "account", key).Run(); err != nil {
+		return fmt.Errorf("keychain: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !darwin && !linux
+
+package config
+
+func (k *KeychainStore) Save(key, value string) error {
+	return ErrKeychainUnavailable
+}
+
+func (k *KeychainStore) Load(key string) (string, error) {
+	return "", ErrKeychainUnavailable
+}
+
+func (k *KeychainStore) Delete(key string) error {
+	return ErrKeychainUnavailable
+}
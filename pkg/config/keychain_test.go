@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeychain map[string]string
+
+func (f fakeKeychain) Save(key, value string) error {
+	f[key] = value
+	return nil
+}
+
+func (f fakeKeychain) Load(key string) (string, error) {
+	value, ok := f[key]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+	return value, nil
+}
+
+func (f fakeKeychain) Delete(key string) error {
+	if _, ok := f[key]; !ok {
+		return ErrCredentialNotFound
+	}
+	delete(f, key)
+	return nil
+}
+
+// useFakeKeychain isolates a test from the real keychain and home directory.
+func useFakeKeychain(t *testing.T) fakeKeychain {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	fake := fakeKeychain{}
+	orig := keychainBackend
+	keychainBackend = fake
+	t.Cleanup(func() { keychainBackend = orig })
+	return fake
+}
+
+func TestSaveCredentialsToKeychain(t *testing.T) {
+	fake := useFakeKeychain(t)
+
+	// An existing file is replaced by the keychain entry.
+	require.NoError(t, SaveCredentials(&Credentials{Username: "old", Token: "file"}))
+	creds := &Credentials{Username: "alice", Token: "s3cret", BaseURL: "https://jenkins.example.com"}
+	require.NoError(t, SaveCredentialsToKeychain(creds))
+
+	assert.Contains(t, fake, keychainCredentialsKey)
+	path, err := GetCredentialsPath()
+	require.NoError(t, err)
+	assert.NoFileExists(t, path)
+
+	loaded, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, creds, loaded)
+
+	token, err := GetCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, creds.EncodedToken(), token)
+}
+
+// countingKeychain counts the lookups made of a fakeKeychain.
+type countingKeychain struct {
+	fakeKeychain
+	loads int
+}
+
+func (c *countingKeychain) Load(key string) (string, error) {
+	c.loads++
+	return c.fakeKeychain.Load(key)
+}
+
+func TestLoadCredentials_KeychainOnlyWithoutFileEntry(t *testing.T) {
+	fake := useFakeKeychain(t)
+	counting := &countingKeychain{fakeKeychain: fake}
+	keychainBackend = counting
+
+	fake[keychainCredentialsKey] = `{"username":"alice","token":"keychain"}`
+	loaded, err := LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "keychain", loaded.Token)
+	assert.Equal(t, 1, counting.loads)
+
+	require.NoError(t, SaveCredentials(&Credentials{Username: "alice", Token: "file"}))
+	loaded, err = LoadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "file", loaded.Token)
+	assert.Equal(t, 1, counting.loads, "the keychain is not asked when the file has the profile")
+}
+
+func TestListProfiles_IncludesKeychainOnly(t *testing.T) {
+	fake := useFakeKeychain(t)
+	require.NoError(t, SaveProfile("staging", &Credentials{Token: "file"}))
+	require.NoError(t, SaveProfileToKeychain("prod", &Credentials{Token: "keychain"}))
+	require.NoError(t, SaveProfileToKeychain("prod", &Credentials{Token: "rotated"}))
+
+	names, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "staging"}, names)
+
+	// A default profile saved before the list was kept is still found.
+	fake[keychainCredentialsKey] = `{"token":"old"}`
+	names, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "prod", "staging"}, names)
+
+	require.NoError(t, RemoveProfile("prod"))
+	require.NoError(t, RemoveProfile("default"))
+	names, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"staging"}, names)
+	assert.Empty(t, fake)
+}
+
+func TestRemoveCredentials_ClearsKeychain(t *testing.T) {
+	fake := useFakeKeychain(t)
+	require.NoError(t, SaveCredentialsToKeychain(&Credentials{Username: "alice", Token: "s3cret"}))
+
+	require.NoError(t, RemoveCredentials())
+	assert.Empty(t, fake)
+
+	_, err := LoadCredentials()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Removing again is not an error.
+	require.NoError(t, RemoveCredentials())
+}
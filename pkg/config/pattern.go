@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MatchingJobs returns the sorted URLs of jobs matching the glob pattern. The
+// syntax is that of path.Match, except that * also matches "/" so that
+// "*/job/feature-*" matches whole job URLs. An invalid pattern matches nothing;
+// use ValidateJobPattern to report it.
+func (c *Config) MatchingJobs(pattern string) []string {
+	re, err := jobPatternRegexp(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+	for jobURL := range c.Jobs {
+		if re.MatchString(jobURL) {
+			matches = append(matches, jobURL)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// JobsWithLabel returns the sorted URLs of jobs carrying label.
+func (c *Config) JobsWithLabel(label string) []string {
+	var matches []string
+	for jobURL, job := range c.Jobs {
+		if job.HasLabel(label) {
+			matches = append(matches, jobURL)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// ValidateJobPattern reports whether pattern is a valid MatchingJobs glob.
+func ValidateJobPattern(pattern string) error {
+	_, err := jobPatternRegexp(pattern)
+	return err
+}
+
+// jobPatternRegexp converts a glob into an anchored regexp: * matches any run
+// of characters, ? a single character, [...] a character class (negated by a
+// leading ^ or !), and \ escapes the next character.
+func jobPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	bad := fmt.Errorf("invalid pattern %q: %w", pattern, path.ErrBadPattern)
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch ch := pattern[i]; ch {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return nil, bad
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				return nil, bad
+			}
+			class := pattern[i+1 : i+1+end]
+			negate := strings.HasPrefix(class, "^") || strings.HasPrefix(class, "!")
+			if negate {
+				class = class[1:]
+			}
+			if class == "" {
+				return nil, bad
+			}
+			b.WriteString("[")
+			if negate {
+				b.WriteString("^")
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`).Replace(class))
+			b.WriteString("]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, bad
+	}
+	return re, nil
+}
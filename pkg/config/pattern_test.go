@@ -0,0 +1,51 @@
+package config
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchingJobs(t *testing.T) {
+	c := &Config{Jobs: map[string]Job{
+		"https://jenkins/job/feature-a/1":  {},
+		"https://jenkins/job/feature-b/7":  {},
+		"https://jenkins/job/feature-c/12": {},
+		"https://jenkins/job/main/3":       {},
+	}}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*/job/feature-*", []string{"https://jenkins/job/feature-a/1", "https://jenkins/job/feature-b/7", "https://jenkins/job/feature-c/12"}},
+		{"*", []string{"https://jenkins/job/feature-a/1", "https://jenkins/job/feature-b/7", "https://jenkins/job/feature-c/12", "https://jenkins/job/main/3"}},
+		{"https://jenkins/job/main/?", []string{"https://jenkins/job/main/3"}},
+		{"*/job/feature-?/?", []string{"https://jenkins/job/feature-a/1", "https://jenkins/job/feature-b/7"}},
+		{"*/job/feature-[ab]/*", []string{"https://jenkins/job/feature-a/1", "https://jenkins/job/feature-b/7"}},
+		{"*/job/feature-[a-b]/*", []string{"https://jenkins/job/feature-a/1", "https://jenkins/job/feature-b/7"}},
+		{"*/job/feature-[^ab]/*", []string{"https://jenkins/job/feature-c/12"}},
+		{"*/job/feature-[!ab]/*", []string{"https://jenkins/job/feature-c/12"}},
+		{`*/job/main/\3`, []string{"https://jenkins/job/main/3"}},
+		{"*/job/release-*", nil},
+		{"https://jenkins/job/main", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.MatchingJobs(tt.pattern))
+		})
+	}
+}
+
+func TestValidateJobPattern(t *testing.T) {
+	assert.NoError(t, ValidateJobPattern("*/job/feature-[a-z]*"))
+
+	for _, pattern := range []string{"*/job/[feature", "*/job/[]", "*/job/[z-a]", `trailing\`} {
+		err := ValidateJobPattern(pattern)
+		assert.ErrorIs(t, err, path.ErrBadPattern, pattern)
+		assert.Empty(t, (&Config{Jobs: map[string]Job{pattern: {}}}).MatchingJobs(pattern), pattern)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietHours is a daily window during which notifications are suppressed.
+// Start and End are "HH:MM" in Timezone (an IANA name; empty means local
+// time). A window whose End is before its Start spans midnight.
+type QuietHours struct {
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Active reports whether t falls within the quiet hours. Start is inclusive
+// and End exclusive; an unset or empty (Start == End) window is never active.
+func (q QuietHours) Active(t time.Time) (bool, error) {
+	if q.Start == "" || q.End == "" {
+		return false, nil
+	}
+	start, err := ParseClock(q.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := ParseClock(q.End)
+	if err != nil {
+		return false, err
+	}
+	loc := time.Local
+	if q.Timezone != "" {
+		if loc, err = time.LoadLocation(q.Timezone); err != nil {
+			return false, fmt.Errorf("invalid quiet hours timezone: %w", err)
+		}
+	}
+
+	t = t.In(loc)
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start <= end {
+		return now >= start && now < end, nil
+	}
+	return now >= start || now < end, nil
+}
+
+// ParseClock parses an "HH:MM" time of day into the offset from midnight.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
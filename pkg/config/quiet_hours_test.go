@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuietHoursActive(t *testing.T) {
+	at := func(hhmm string) time.Time {
+		clock, err := time.Parse("15:04", hhmm)
+		require.NoError(t, err)
+		return time.Date(2026, 3, 1, clock.Hour(), clock.Minute(), 30, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		now        string
+		want       bool
+	}{
+		{"daytime window, inside", "09:00", "17:00", "12:00", true},
+		{"daytime window, before", "09:00", "17:00", "08:59", false},
+		{"daytime window, after", "09:00", "17:00", "17:01", false},
+		{"daytime window, at start", "09:00", "17:00", "09:00", true},
+		{"daytime window, at end", "09:00", "17:00", "17:00", false},
+		{"overnight, late evening", "22:00", "07:00", "23:30", true},
+		{"overnight, early morning", "22:00", "07:00", "03:00", true},
+		{"overnight, midnight", "22:00", "07:00", "00:00", true},
+		{"overnight, daytime", "22:00", "07:00", "12:00", false},
+		{"overnight, at start", "22:00", "07:00", "22:00", true},
+		{"overnight, at end", "22:00", "07:00", "07:00", false},
+		{"overnight, just before start", "22:00", "07:00", "21:59", false},
+		{"empty window", "08:00", "08:00", "08:00", false},
+		{"unset", "", "", "12:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := QuietHours{Start: tt.start, End: tt.end, Timezone: "UTC"}
+			got, err := q.Active(at(tt.now))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQuietHoursActive_Timezone(t *testing.T) {
+	q := QuietHours{Start: "22:00", End: "07:00", Timezone: "Asia/Tokyo"}
+
+	// 14:00 UTC is 23:00 in Tokyo.
+	got, err := q.Active(time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	// 03:00 UTC is 12:00 in Tokyo.
+	got, err = q.Active(time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, got)
+}
+
+func TestQuietHoursActive_Invalid(t *testing.T) {
+	_, err := QuietHours{Start: "25:00", End: "07:00"}.Active(time.Now())
+	assert.ErrorContains(t, err, "HH:MM")
+
+	_, err = QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}.Active(time.Now())
+	assert.ErrorContains(t, err, "timezone")
+}
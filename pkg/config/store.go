@@ -1,6 +1,9 @@
 package config
 
-import "sync"
+import (
+	"crypto/sha256"
+	"sync"
+)
 
 type ConfigStore interface {
 	Load() (*Config, error)
@@ -10,6 +13,11 @@ type ConfigStore interface {
 
 type DiskStore struct {
 	mu sync.Mutex
+	// IgnoreChecksum loads a config whose checksum does not match instead
+	// of failing with ErrChecksumMismatch. Saving writes a fresh checksum.
+	IgnoreChecksum bool
+
+	written [sha256.Size]byte
 }
 
 func NewDiskStore() *DiskStore {
@@ -20,7 +28,7 @@ func (s *DiskStore) Load() (*Config, error) {
 	var cfg *Config
 	err := s.withLock(func() error {
 		var loadErr error
-		cfg, loadErr = loadFromDisk()
+		cfg, loadErr = loadFromDisk(s.IgnoreChecksum)
 		return loadErr
 	})
 	if err != nil {
@@ -31,25 +39,86 @@ func (s *DiskStore) Load() (*Config, error) {
 
 func (s *DiskStore) Save(cfg *Config) error {
 	return s.withLock(func() error {
-		return saveToDisk(cfg)
+		return s.save(cfg)
 	})
 }
 
+// Update loads the config, applies fn and saves the result, unless fn left it
+// unchanged.
 func (s *DiskStore) Update(fn func(*Config) error) error {
 	return s.withLock(func() error {
-		cfg, err := loadFromDisk()
+		cfg, err := loadFromDisk(s.IgnoreChecksum)
+		if err != nil {
+			return err
+		}
+		before, err := cfg.checksum()
 		if err != nil {
 			return err
 		}
 		if err := fn(cfg); err != nil {
 			return err
 		}
-		return saveToDisk(cfg)
+		if after, err := cfg.checksum(); err == nil && after == before {
+			return nil
+		}
+		return s.save(cfg)
 	})
 }
 
+// WroteLast reports whether data is what this store last wrote to the config
+// file, so a file watcher can tell its own writes from other processes'.
+func (s *DiskStore) WroteLast(data []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.written == sha256.Sum256(data)
+}
+
+func (s *DiskStore) save(cfg *Config) error {
+	data, err := saveToDisk(cfg)
+	if err != nil {
+		return err
+	}
+	s.written = sha256.Sum256(data)
+	return nil
+}
+
 func (s *DiskStore) withLock(fn func() error) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	return withFileLock(fn)
 }
+
+// MemoryStore is a ConfigStore that never touches disk. It is used for
+// foreground commands that track a job without persisting it, and in tests.
+type MemoryStore struct {
+	mu  sync.Mutex
+	cfg *Config
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cfg: &Config{Jobs: make(map[string]Job)}}
+}
+
+func (s *MemoryStore) Load() (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.clone(), nil
+}
+
+func (s *MemoryStore) Save(cfg *Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg.clone()
+	return nil
+}
+
+func (s *MemoryStore) Update(fn func(*Config) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := s.cfg.clone()
+	if err := fn(cfg); err != nil {
+		return err
+	}
+	s.cfg = cfg
+	return nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Defaults for Config.NotifyTitleTemplate and Config.NotifyBodyTemplate. They
+// produce the same notification as before templates were configurable.
+const (
+	DefaultNotifyTitleTemplate = "{{.Title}}"
+	DefaultNotifyBodyTemplate  = "Job: {{.JobName}}{{if .Note}}\nNote: {{.Note}}{{end}}\n{{if .BuildNumber}}Build #{{.BuildNumber}}{{else}}Status{{end}}: {{.Result}}{{if .Duration}}\nCompleted in {{.Duration}}{{end}}{{if .Parameters}}\nParameters: {{.Parameters}}{{end}}{{if .TriggerCause}}\nTriggered by: {{.TriggerCause}}{{end}}{{if .FailedStages}}\nFailed stages: {{.FailedStages}}{{end}}{{if .Tests}}\nTests: {{.Tests}}{{end}}{{if .Changes}}\nChanges:\n{{.Changes}}{{end}}"
+)
+
+// maxNotificationParameters is how many build parameters FormatParameters
+// lists before summarising the rest.
+const maxNotificationParameters = 3
+
+// maxNoteLength is how many characters of a job's notes TruncateNote keeps.
+const maxNoteLength = 80
+
+// NotificationData is the context notification templates are executed with.
+// Title is the title jw would use by default, e.g. "Build Regression", and
+// Duration how long the build ran, e.g. "4m 32s", if known.
+// Parameters is the build parameters as formatted by FormatParameters, and
+// Tests the test results, e.g. "42 passed, 3 failed, 1 skipped". Changes
+// lists the build's commits one per line. Note is the job's notes as
+// shortened by TruncateNote, and BuildNumber the Jenkins build number, or 0
+// if unknown. FailedStages lists the failed pipeline stages, e.g.
+// "Test, Deploy".
+type NotificationData struct {
+	Title        string
+	JobName      string
+	JobURL       string
+	Result       string
+	Duration     string
+	Parameters   string
+	TriggerCause string
+	Tests        string
+	Changes      string
+	Note         string
+	BuildNumber  int
+	FailedStages string
+}
+
+// FormatParameters renders build parameters as "NAME=value" pairs sorted by
+// name, listing at most three and counting the rest.
+func FormatParameters(params map[string]string) string {
+	names := slices.Sorted(maps.Keys(params))
+	shown := names[:min(len(names), maxNotificationParameters)]
+	pairs := make([]string, 0, len(shown))
+	for _, name := range shown {
+		pairs = append(pairs, name+"="+params[name])
+	}
+	out := strings.Join(pairs, ", ")
+	if rest := len(names) - len(shown); rest > 0 {
+		out += fmt.Sprintf(" (+%d more)", rest)
+	}
+	return out
+}
+
+// TruncateNote shortens a job's notes to at most 80 characters for display,
+// ending them with "…" when cut.
+func TruncateNote(note string) string {
+	runes := []rune(note)
+	if len(runes) <= maxNoteLength {
+		return note
+	}
+	return string(runes[:maxNoteLength-1]) + "…"
+}
+
+// NotificationTemplates parses the configured title and body templates,
+// falling back to the defaults for empty ones.
+func (c *Config) NotificationTemplates() (title, body *template.Template, err error) {
+	title, err = parseNotifyTemplate("notify_title_template", c.NotifyTitleTemplate, DefaultNotifyTitleTemplate)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err = parseNotifyTemplate("notify_body_template", c.NotifyBodyTemplate, DefaultNotifyBodyTemplate)
+	if err != nil {
+		return nil, nil, err
+	}
+	return title, body, nil
+}
+
+func parseNotifyTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return tmpl, nil
+}
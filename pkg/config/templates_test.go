@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func render(t *testing.T, c *Config, data NotificationData) (string, string) {
+	t.Helper()
+	title, body, err := c.NotificationTemplates()
+	require.NoError(t, err)
+	var tb, bb strings.Builder
+	require.NoError(t, title.Execute(&tb, data))
+	require.NoError(t, body.Execute(&bb, data))
+	return tb.String(), bb.String()
+}
+
+func TestNotificationTemplates_Defaults(t *testing.T) {
+	title, body := render(t, &Config{}, NotificationData{Title: "Build Regression", JobName: "app/8/", Result: "FAILURE"})
+	assert.Equal(t, "Build Regression", title)
+	assert.Equal(t, "Job: app/8/\nStatus: FAILURE", body)
+}
+
+func TestNotificationTemplates_AllFields(t *testing.T) {
+	c := &Config{
+		NotifyTitleTemplate: "[{{.Result}}] {{.JobName}}",
+		NotifyBodyTemplate:  "{{.Title}}: {{.JobURL}} took {{.Duration}}",
+	}
+	title, body := render(t, c, NotificationData{
+		Title:    "Jenkins Job Completed",
+		JobName:  "app/8/",
+		JobURL:   "https://jenkins/job/app/8/",
+		Result:   "SUCCESS",
+		Duration: "1m30s",
+	})
+	assert.Equal(t, "[SUCCESS] app/8/", title)
+	assert.Equal(t, "Jenkins Job Completed: https://jenkins/job/app/8/ took 1m30s", body)
+}
+
+func TestNotificationTemplates_Invalid(t *testing.T) {
+	_, _, err := (&Config{NotifyBodyTemplate: "{{.Result"}).NotificationTemplates()
+	assert.ErrorContains(t, err, "notify_body_template")
+
+	_, _, err = (&Config{NotifyTitleTemplate: "{{if}}"}).NotificationTemplates()
+	assert.ErrorContains(t, err, "notify_title_template")
+}
+
+func TestLoadFromDisk_WarnsOnInvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path, err := GetConfigPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"jobs":{"https://j/job/a":{}},"notify_title_template":"{{.Title"}`), 0o644))
+
+	var warnings []error
+	orig := LoadWarning
+	LoadWarning = func(err error) { warnings = append(warnings, err) }
+	t.Cleanup(func() { LoadWarning = orig })
+
+	cfg, err := loadFromDisk(false)
+	require.NoError(t, err, "an invalid template must not make every command fail")
+	assert.Len(t, cfg.Jobs, 1)
+	assert.Equal(t, "{{.Title", cfg.NotifyTitleTemplate, "the template is kept so it can be fixed")
+	require.Len(t, warnings, 1)
+	assert.ErrorContains(t, warnings[0], "notify_title_template")
+
+	_, err = loadFromDisk(false)
+	require.NoError(t, err)
+	assert.Len(t, warnings, 1, "the same template is reported once")
+}
+
+func TestFormatParameters(t *testing.T) {
+	assert.Equal(t, "", FormatParameters(nil))
+	assert.Equal(t, "BRANCH=main, ENV=prod", FormatParameters(map[string]string{"ENV": "prod", "BRANCH": "main"}))
+	assert.Equal(t, "A=1, B=2, C=3 (+2 more)", FormatParameters(map[string]string{
+		"E": "5", "D": "4", "C": "3", "B": "2", "A": "1",
+	}))
+}
+
+func TestTruncateNote(t *testing.T) {
+	assert.Equal(t, "", TruncateNote(""))
+	assert.Equal(t, "waiting for hotfix deploy", TruncateNote("waiting for hotfix deploy"))
+
+	exact := strings.Repeat("a", 80)
+	assert.Equal(t, exact, TruncateNote(exact))
+
+	long := strings.Repeat("é", 81)
+	got := TruncateNote(long)
+	assert.Equal(t, 80, len([]rune(got)), "counts characters, not bytes")
+	assert.Equal(t, strings.Repeat("é", 79)+"…", got)
+}
+
+func TestNotificationTemplates_DefaultIncludesNote(t *testing.T) {
+	_, body := render(t, &Config{}, NotificationData{JobName: "app/8/", Result: "SUCCESS", Note: "waiting for hotfix deploy"})
+	assert.Equal(t, "Job: app/8/\nNote: waiting for hotfix deploy\nStatus: SUCCESS", body)
+}
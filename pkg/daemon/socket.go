@@ -0,0 +1,184 @@
+// Package daemon implements the control socket the jw daemon listens on.
+//
+// Clients send one JSON object per line, e.g. {"command":"reload"}, and read
+// one JSON Response line back.
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"jenkins-monitor/pkg/paths"
+)
+
+const socketFileName = "daemon.sock"
+
+// Commands understood by the daemon.
+const (
+	CommandReload = "reload"
+	CommandStatus = "status"
+	CommandAdd    = "add"
+	CommandStop   = "stop"
+)
+
+// clientTimeout bounds how long Send waits for the daemon.
+const clientTimeout = 5 * time.Second
+
+type Request struct {
+	Command string `json:"command"`
+	URL     string `json:"url,omitempty"`
+}
+
+type Response struct {
+	OK    bool     `json:"ok"`
+	Error string   `json:"error,omitempty"`
+	PID   int      `json:"pid,omitempty"`
+	Jobs  []string `json:"jobs,omitempty"`
+}
+
+// Handler answers a single request.
+type Handler func(Request) Response
+
+// SocketPath returns the path of the daemon control socket in the runtime
+// directory.
+func SocketPath() (string, error) {
+	dir, err := paths.RuntimeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, socketFileName), nil
+}
+
+// Server accepts connections on the control socket.
+type Server struct {
+	listener net.Listener
+	path     string
+	handler  Handler
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// Listen creates the control socket at path and serves requests with handler
+// until Close is called. A leftover socket file nobody is listening on is
+// replaced.
+func Listen(path string, handler Handler) (*Server, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("another daemon is listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	s := &Server{listener: ln, path: path, handler: handler, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	enc := json.NewEncoder(conn)
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = enc.Encode(Response{Error: "invalid request: " + err.Error()})
+			continue
+		}
+		if err := enc.Encode(s.handler(req)); err != nil {
+			return
+		}
+	}
+}
+
+// Close stops accepting connections, waits for open ones to finish their
+// current request and removes the socket file. Idle connections are not
+// waited for: reads on them are cut short.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	if rmErr := os.Remove(s.path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) && err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// Send delivers req to the daemon listening on path and returns its response.
+// A response with OK false is returned as an error.
+func Send(path string, req Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", path, clientTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(clientTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("reading daemon response: %w", err)
+	}
+	if !resp.OK {
+		return &resp, fmt.Errorf("daemon: %s", resp.Error)
+	}
+	return &resp, nil
+}
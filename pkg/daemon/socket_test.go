@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socketPath returns a short socket path; t.TempDir can exceed the unix
+// socket path limit on macOS.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "jw")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "d.sock")
+}
+
+func TestSendReceivesHandlerResponse(t *testing.T) {
+	path := socketPath(t)
+	var got []Request
+	srv, err := Listen(path, func(req Request) Response {
+		got = append(got, req)
+		if req.Command == CommandStatus {
+			return Response{OK: true, PID: 42, Jobs: []string{"https://jenkins/job/a/1/"}}
+		}
+		return Response{OK: true}
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	resp, err := Send(path, Request{Command: CommandStatus})
+	require.NoError(t, err)
+	assert.Equal(t, 42, resp.PID)
+	assert.Equal(t, []string{"https://jenkins/job/a/1/"}, resp.Jobs)
+
+	_, err = Send(path, Request{Command: CommandAdd, URL: "https://jenkins/job/b/2/"})
+	require.NoError(t, err)
+	assert.Equal(t, []Request{{Command: CommandStatus}, {Command: CommandAdd, URL: "https://jenkins/job/b/2/"}}, got)
+}
+
+func TestSendReturnsDaemonError(t *testing.T) {
+	path := socketPath(t)
+	srv, err := Listen(path, func(req Request) Response {
+		return Response{Error: "unknown command"}
+	})
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := Send(path, Request{Command: "bogus"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown command")
+	assert.False(t, resp.OK)
+}
+
+func TestServerRejectsInvalidJSON(t *testing.T) {
+	path := socketPath(t)
+	srv, err := Listen(path, func(req Request) Response { return Response{OK: true} })
+	require.NoError(t, err)
+	defer srv.Close()
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("not json\n"))
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, "invalid request")
+}
+
+func TestCloseDoesNotWaitForIdleConnections(t *testing.T) {
+	path := socketPath(t)
+	srv, err := Listen(path, func(req Request) Response { return Response{OK: true} })
+	require.NoError(t, err)
+
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	defer conn.Close()
+	// Make sure the server has picked the connection up.
+	_, err = conn.Write([]byte(`{"command":"status"}` + "\n"))
+	require.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+
+	closed := make(chan error, 1)
+	go func() { closed <- srv.Close() }()
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close waited for an idle client connection")
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	srv, err := Listen(path, func(req Request) Response { return Response{OK: true} })
+	require.NoError(t, err)
+
+	_, err = Listen(path, func(req Request) Response { return Response{OK: true} })
+	assert.Error(t, err, "a live socket must not be replaced")
+
+	require.NoError(t, srv.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "Close should remove the socket file")
+}
+
+func TestSendWithoutDaemon(t *testing.T) {
+	_, err := Send(socketPath(t), Request{Command: CommandReload})
+	assert.Error(t, err)
+}
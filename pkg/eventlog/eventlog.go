@@ -0,0 +1,171 @@
+// Package eventlog keeps a rotating, newline-delimited JSON log of the job
+// events the daemon handled, so they can be listed after the fact.
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"jenkins-monitor/pkg/jenkins"
+	"jenkins-monitor/pkg/monitor"
+	"jenkins-monitor/pkg/paths"
+)
+
+const (
+	fileName = "events.jsonl"
+	// MaxSize is how large the log grows before it is rotated.
+	MaxSize = 1 << 20
+	// MaxFiles is how many files are kept: the log and its rotated copies
+	// events.jsonl.1 (the newest) to events.jsonl.(MaxFiles-1).
+	MaxFiles = 3
+)
+
+// Event is a job event as recorded in the log.
+type Event struct {
+	Time time.Time `json:"time"`
+	monitor.JobEvent
+	// Error replaces JobEvent.Error, which does not survive JSON.
+	Error string `json:"Error,omitempty"`
+}
+
+// EventFilter selects events from the log. Zero fields match everything.
+type EventFilter struct {
+	// JobURL matches events of the job, or any build of it.
+	JobURL string
+	// Result matches finished builds with this result, ignoring case.
+	Result string
+	Since  time.Time
+}
+
+func (f EventFilter) match(e Event) bool {
+	if f.JobURL != "" && jenkins.JobURLFromBuildURL(e.JobURL) != jenkins.JobURLFromBuildURL(f.JobURL) {
+		return false
+	}
+	if f.Result != "" && !strings.EqualFold(e.Result, f.Result) {
+		return false
+	}
+	return f.Since.IsZero() || !e.Time.Before(f.Since)
+}
+
+// Log is an event log file and its rotated copies.
+type Log struct {
+	path string
+	now  func() time.Time
+}
+
+// New returns the event log at path.
+func New(path string) *Log {
+	return &Log{path: path, now: time.Now}
+}
+
+// Default returns the event log in the log directory, beside the daemon log.
+func Default() (*Log, error) {
+	dir, err := paths.LogDir()
+	if err != nil {
+		return nil, err
+	}
+	return New(filepath.Join(dir, fileName)), nil
+}
+
+// Path returns where the log is written.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append records event, rotating the log first if it would grow past
+// MaxSize.
+func (l *Log) Append(event monitor.JobEvent) error {
+	rec := Event{Time: l.now(), JobEvent: event}
+	if event.Error != nil {
+		rec.Error = event.Error.Error()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+	line = append(line, '\n')
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > MaxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("rotating event log: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (l *Log) rotatedPath(n int) string {
+	return l.path + "." + strconv.Itoa(n)
+}
+
+// rotate shifts the rotated copies up by one, dropping the oldest, and moves
+// the log to events.jsonl.1.
+func (l *Log) rotate() error {
+	for n := MaxFiles - 1; n >= 1; n-- {
+		src := l.path
+		if n > 1 {
+			src = l.rotatedPath(n - 1)
+		}
+		if err := os.Rename(src, l.rotatedPath(n)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAll returns the logged events matching filter, oldest first. Lines
+// that cannot be decoded, such as one cut short by a crash, are skipped.
+func (l *Log) ReadAll(filter EventFilter) ([]Event, error) {
+	var events []Event
+	for n := MaxFiles - 1; n >= 0; n-- {
+		path := l.path
+		if n > 0 {
+			path = l.rotatedPath(n)
+		}
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), MaxSize)
+		for scanner.Scan() {
+			var e Event
+			if json.Unmarshal(scanner.Bytes(), &e) != nil {
+				continue
+			}
+			if e.Error != "" {
+				e.JobEvent.Error = errors.New(e.Error)
+			}
+			if filter.match(e) {
+				events = append(events, e)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return events, nil
+}
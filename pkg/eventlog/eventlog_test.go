@@ -0,0 +1,103 @@
+package eventlog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/monitor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLog(t *testing.T) *Log {
+	t.Helper()
+	log := New(filepath.Join(t.TempDir(), "events.jsonl"))
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	n := 0
+	log.now = func() time.Time {
+		n++
+		return start.Add(time.Duration(n) * time.Minute)
+	}
+	return log
+}
+
+func TestAppendAndReadAll(t *testing.T) {
+	log := newTestLog(t)
+	events := []monitor.JobEvent{
+		{JobURL: "https://jenkins/job/a/1", Kind: monitor.EventFinished, Result: "SUCCESS", BuildNumber: 1},
+		{JobURL: "https://jenkins/job/b/4", Kind: monitor.EventFinished, Result: "FAILURE", BuildNumber: 4, FailedStages: []string{"Test"}},
+		{JobURL: "https://jenkins/job/c/2", Kind: monitor.EventNotFound, Error: errors.New("http error: 404")},
+		{JobURL: "https://jenkins/job/a/2", Kind: monitor.EventFinished, Result: "failure", BuildNumber: 2},
+		{JobURL: "https://jenkins/job/b/5", Kind: monitor.EventDurationExceeded, Duration: time.Hour},
+	}
+	for _, e := range events {
+		require.NoError(t, log.Append(e))
+	}
+
+	all, err := log.ReadAll(EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	assert.Equal(t, time.Hour, all[4].Duration)
+	assert.Equal(t, "http error: 404", all[2].Error)
+	assert.EqualError(t, all[2].JobEvent.Error, "http error: 404")
+
+	failed, err := log.ReadAll(EventFilter{Result: "FAILURE"})
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, "https://jenkins/job/b/4", failed[0].JobURL)
+	assert.Equal(t, []string{"Test"}, failed[0].FailedStages)
+	assert.Equal(t, "https://jenkins/job/a/2", failed[1].JobURL)
+
+	jobA, err := log.ReadAll(EventFilter{JobURL: "https://jenkins/job/a/", Since: all[1].Time})
+	require.NoError(t, err)
+	require.Len(t, jobA, 1)
+	assert.Equal(t, 2, jobA[0].BuildNumber)
+}
+
+func TestReadAll_MissingLog(t *testing.T) {
+	events, err := newTestLog(t).ReadAll(EventFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestAppend_Rotates(t *testing.T) {
+	log := newTestLog(t)
+	big := monitor.JobEvent{JobURL: "https://jenkins/job/a/1", Kind: monitor.EventFinished, Result: strings.Repeat("x", MaxSize/3)}
+	for range 8 {
+		require.NoError(t, log.Append(big))
+	}
+	require.NoError(t, log.Append(monitor.JobEvent{JobURL: "https://jenkins/job/a/2", Kind: monitor.EventFinished, Result: "SUCCESS"}))
+
+	for n := 1; n < MaxFiles; n++ {
+		assert.FileExists(t, log.rotatedPath(n))
+	}
+	_, err := os.Stat(log.rotatedPath(MaxFiles))
+	assert.True(t, os.IsNotExist(err))
+	info, err := os.Stat(log.Path())
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(MaxSize))
+
+	events, err := log.ReadAll(EventFilter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 7)
+	assert.Equal(t, "SUCCESS", events[len(events)-1].Result)
+}
+
+func TestReadAll_SkipsTruncatedLine(t *testing.T) {
+	log := newTestLog(t)
+	require.NoError(t, log.Append(monitor.JobEvent{JobURL: "https://jenkins/job/a/1", Kind: monitor.EventFinished, Result: "SUCCESS"}))
+	f, err := os.OpenFile(log.Path(), os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"time":"2026-01-01T12:00:00Z","JobURL":"https://jen`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	events, err := log.ReadAll(EventFilter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
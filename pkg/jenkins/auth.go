@@ -5,53 +5,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
 	"strings"
 	"time"
 )
 
+// APIToken is a Jenkins API token generated for jw.
+type APIToken struct {
+	Value string
+	UUID  string
+}
+
 // AuthenticateAndGenerateToken authenticates with Jenkins Basic Auth and generates a new API Token.
-// It returns the new token value or an error.
-func AuthenticateAndGenerateToken(jenkinsURL, username, password string) (string, error) {
+// It returns the new token or an error.
+func AuthenticateAndGenerateToken(jenkinsURL, username, password string) (*APIToken, error) {
 	// Setup Client with CookieJar
-	jar, _ := cookiejar.New(nil)
-	client := &http.Client{
-		Jar:     jar,
-		Timeout: 30 * time.Second,
-	}
+	client := withCookieJar(DefaultClient)
 
 	// 1. Get Crumb
-	crumbURL := fmt.Sprintf("%s/crumbIssuer/api/xml?xpath=concat(//crumbRequestField,\":\",//crumb)", jenkinsURL)
-	req, err := http.NewRequest("GET", crumbURL, nil)
+	c, statusCode, err := fetchCrumb(client, jenkinsURL, func(req *http.Request) {
+		req.SetBasicAuth(username, password)
+	})
 	if err != nil {
-		return "", fmt.Errorf("error creating crumb request: %w", err)
-	}
-	req.SetBasicAuth(username, password)
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("error fetching crumb: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == 401 || resp.StatusCode == 403 {
-			return "", fmt.Errorf("authentication failed: check your username and password (status: %s)", resp.Status)
+		if statusCode == 401 || statusCode == 403 {
+			return nil, fmt.Errorf("authentication failed: check your username and password (%w)", err)
 		}
-		return "", fmt.Errorf("failed to get crumb (status: %s)", resp.Status)
+		return nil, err
 	}
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	crumbAndField := string(bodyBytes)
-	// Format: CrumbField:CrumbValue
-	parts := strings.SplitN(crumbAndField, ":", 2)
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid crumb response format")
-	}
-	crumbHeader := parts[0]
-	crumbValue := parts[1]
-
 	// 2. Generate API Token
 	tokenName := fmt.Sprintf("jw-cli-%d", time.Now().Unix())
 	generateURL := fmt.Sprintf("%s/me/descriptorByName/jenkins.security.ApiTokenProperty/generateNewToken", jenkinsURL)
@@ -59,22 +40,22 @@ func AuthenticateAndGenerateToken(jenkinsURL, username, password string) (string
 	data := url.Values{}
 	data.Set("newTokenName", tokenName)
 
-	req, err = http.NewRequest("POST", generateURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", generateURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("error creating token generation request: %w", err)
+		return nil, fmt.Errorf("error creating token generation request: %w", err)
 	}
 	req.SetBasicAuth(username, password)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set(crumbHeader, crumbValue)
+	req.Header.Set(c.Header, c.Value)
 
-	resp, err = client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error generating token: %w", err)
+		return nil, fmt.Errorf("error generating token: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to generate token (status: %s)", resp.Status)
+		return nil, fmt.Errorf("failed to generate token (status: %s)", resp.Status)
 	}
 
 	type TokenResponse struct {
@@ -82,17 +63,80 @@ func AuthenticateAndGenerateToken(jenkinsURL, username, password string) (string
 		Data   struct {
 			TokenName  string `json:"tokenName"`
 			TokenValue string `json:"tokenValue"`
+			TokenUUID  string `json:"tokenUuid"`
 		} `json:"data"`
 	}
 
 	var tokenResp TokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("error parsing token response: %w", err)
+		return nil, fmt.Errorf("error parsing token response: %w", err)
 	}
 
 	if tokenResp.Status != "ok" {
-		return "", fmt.Errorf("error from Jenkins: status not ok")
+		return nil, fmt.Errorf("error from Jenkins: status not ok")
+	}
+
+	return &APIToken{Value: tokenResp.Data.TokenValue, UUID: tokenResp.Data.TokenUUID}, nil
+}
+
+// crumb is a Jenkins CSRF protection token, sent back as a request header.
+type crumb struct {
+	Header string
+	Value  string
+}
+
+// fetchCrumb requests a CSRF crumb from the Jenkins root at jenkinsURL.
+// authorize adds credentials to the request. On failure the HTTP status code
+// is returned alongside the error so callers can tailor their message.
+func fetchCrumb(client *http.Client, jenkinsURL string, authorize func(*http.Request)) (*crumb, int, error) {
+	crumbURL := fmt.Sprintf("%s/crumbIssuer/api/xml?xpath=concat(//crumbRequestField,\":\",//crumb)", strings.TrimRight(jenkinsURL, "/"))
+	req, err := http.NewRequest("GET", crumbURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error creating crumb request: %w", err)
 	}
+	authorize(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching crumb: %w", err)
+	}
+	defer resp.Body.Close()
 
-	return tokenResp.Data.TokenValue, nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("failed to get crumb (status: %s)", resp.Status)
+	}
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	// Format: CrumbField:CrumbValue
+	parts := strings.SplitN(string(bodyBytes), ":", 2)
+	if len(parts) != 2 {
+		return nil, resp.StatusCode, fmt.Errorf("invalid crumb response format")
+	}
+	return &crumb{Header: parts[0], Value: parts[1]}, resp.StatusCode, nil
+}
+
+// RevokeToken revokes the API token identified by tokenUUID for the user that
+// token authenticates as. Stapler serves ApiTokenProperty's doRevoke method at
+// the "revoke" path.
+func RevokeToken(jenkinsURL, token, tokenUUID string) error {
+	if tokenUUID == "" {
+		return fmt.Errorf("token ID unknown; revoke the token from your Jenkins user settings")
+	}
+	jenkinsURL = strings.TrimRight(jenkinsURL, "/")
+	form := url.Values{}
+	form.Set("tokenUuid", tokenUUID)
+
+	resp, err := postWithCrumb(jenkinsURL, jenkinsURL+"/me/descriptorByName/jenkins.security.ApiTokenProperty/revoke", token, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("revocation rejected (status: %s)", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to revoke token (status: %s)", resp.Status)
+	}
+	return nil
 }
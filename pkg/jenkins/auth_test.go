@@ -0,0 +1,62 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateAndGenerateToken(t *testing.T) {
+	server := newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/me/descriptorByName/jenkins.security.ApiTokenProperty/generateNewToken": func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "alice", user)
+			assert.Equal(t, "secret", pass)
+			assert.Equal(t, "abc123", r.Header.Get("Jenkins-Crumb"))
+			fmt.Fprint(w, `{"status":"ok","data":{"tokenName":"jw","tokenValue":"11abc","tokenUuid":"uuid-1"}}`)
+		},
+	})
+
+	token, err := AuthenticateAndGenerateToken(server.URL, "alice", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, &APIToken{Value: "11abc", UUID: "uuid-1"}, token)
+}
+
+func TestRevokeToken(t *testing.T) {
+	var gotMethod, gotUUID, gotAuth, gotCrumb string
+	server := newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/me/descriptorByName/jenkins.security.ApiTokenProperty/revoke": func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			gotAuth = r.Header.Get("Authorization")
+			gotCrumb = r.Header.Get("Jenkins-Crumb")
+			require.NoError(t, r.ParseForm())
+			gotUUID = r.PostForm.Get("tokenUuid")
+		},
+	})
+
+	require.NoError(t, RevokeToken(server.URL+"/", "dG9rZW4=", "uuid-1"))
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "Basic dG9rZW4=", gotAuth)
+	assert.Equal(t, "abc123", gotCrumb)
+	assert.Equal(t, "uuid-1", gotUUID)
+}
+
+func TestRevokeToken_Errors(t *testing.T) {
+	server := newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/me/descriptorByName/jenkins.security.ApiTokenProperty/revoke": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		},
+	})
+
+	assert.ErrorContains(t, RevokeToken(server.URL, "token", "uuid-1"), "rejected")
+	assert.ErrorContains(t, RevokeToken(server.URL, "token", ""), "token ID unknown")
+
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	assert.Error(t, RevokeToken(unreachable.URL, "token", "uuid-1"))
+}
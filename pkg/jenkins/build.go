@@ -0,0 +1,194 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var buildNumberSuffix = regexp.MustCompile(`/(\d+|lastBuild)/?$`)
+
+// JobURLFromBuildURL strips a trailing build number or "lastBuild", turning
+// ".../job/foo/42/" into ".../job/foo".
+func JobURLFromBuildURL(buildURL string) string {
+	return strings.TrimRight(buildNumberSuffix.ReplaceAllString(buildURL, ""), "/")
+}
+
+// LastBuildURL returns the URL that always points at the newest build of the
+// job that jobURL, a job or build URL, belongs to.
+func LastBuildURL(jobURL string) string {
+	return JobURLFromBuildURL(jobURL) + "/lastBuild"
+}
+
+// RootURL returns the Jenkins root for a job or build URL: everything before
+// the first "/job/" path segment.
+func RootURL(jobURL string) string {
+	if i := strings.Index(jobURL, "/job/"); i >= 0 {
+		return jobURL[:i]
+	}
+	return strings.TrimRight(jobURL, "/")
+}
+
+// postWithCrumb sends a form POST to target, attaching a CSRF crumb from the
+// Jenkins root when the server issues one.
+func postWithCrumb(root, target, token string, form url.Values) (*http.Response, error) {
+	client := withCookieJar(DefaultClient)
+
+	authorize := func(req *http.Request) {
+		req.Header.Set("Authorization", "Basic "+token)
+	}
+
+	c, statusCode, err := fetchCrumb(client, root, authorize)
+	if err != nil && statusCode != http.StatusNotFound {
+		// 404 means CSRF protection is disabled; anything else is fatal.
+		if statusCode == 401 || statusCode == 403 {
+			return nil, fmt.Errorf("unauthorized (%d): check your credentials", statusCode)
+		}
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	authorize(req)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c != nil {
+		req.Header.Set(c.Header, c.Value)
+	}
+	return client.Do(req)
+}
+
+// TriggerBuild starts a build of the job at jobURL and returns the URL of the
+// queue item Jenkins created. With params it uses /buildWithParameters.
+func TriggerBuild(jobURL, token string, params map[string]string) (string, error) {
+	jobURL = JobURLFromBuildURL(jobURL)
+
+	endpoint := jobURL + "/build"
+	form := url.Values{}
+	if len(params) > 0 {
+		endpoint = jobURL + "/buildWithParameters"
+		for k, v := range params {
+			form.Set(k, v)
+		}
+	}
+
+	resp, err := postWithCrumb(RootURL(jobURL), endpoint, token, form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("job not found (404): %s", jobURL)
+	case resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("forbidden (403): CSRF crumb rejected or missing Build permission")
+	case resp.StatusCode == http.StatusBadRequest && len(params) == 0:
+		return "", fmt.Errorf("bad request (400): the job may require parameters (use --parameter)")
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return "", fmt.Errorf("http error: %s", resp.Status)
+	}
+
+	return resp.Header.Get("Location"), nil
+}
+
+type queueItem struct {
+	Cancelled  bool `json:"cancelled"`
+	Executable *struct {
+		URL string `json:"url"`
+	} `json:"executable"`
+}
+
+// WaitForQueuedBuild polls a queue item until Jenkins assigns it a build and
+// returns the build URL. Failed polls, including a 404 for an item Jenkins
+// has not listed yet or already purged, are retried until timeout.
+func WaitForQueuedBuild(queueURL, token string, interval, timeout time.Duration) (string, error) {
+	if queueURL == "" {
+		return "", fmt.Errorf("jenkins did not return a queue item URL")
+	}
+	apiURL := strings.TrimRight(queueURL, "/") + "/api/json"
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		var item queueItem
+		found, err := getJSON(ctx, apiURL, token, "queue item", &item)
+		if ctx.Err() == nil {
+			switch {
+			case err != nil:
+				lastErr = err
+			case !found:
+				lastErr = fmt.Errorf("queue item not found (404): %s", queueURL)
+			case item.Cancelled:
+				return "", fmt.Errorf("queued build was cancelled")
+			case item.Executable != nil && item.Executable.URL != "":
+				return item.Executable.URL, nil
+			default:
+				lastErr = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return "", fmt.Errorf("build did not leave the queue within %s: %w", timeout, lastErr)
+			}
+			return "", fmt.Errorf("build did not leave the queue within %s", timeout)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// BuildInfo is the subset of a build's API data needed to act on it.
+type BuildInfo struct {
+	URL      string `json:"url"`
+	Building bool   `json:"building"`
+	Result   string `json:"result"`
+}
+
+// GetBuildInfo fetches the canonical URL and state of the build at jobURL.
+func GetBuildInfo(jobURL, token string) (*BuildInfo, error) {
+	return GetBuildInfoCtx(context.Background(), jobURL, token)
+}
+
+// GetBuildInfoCtx is GetBuildInfo with a context that cancels the request.
+func GetBuildInfoCtx(ctx context.Context, jobURL, token string) (*BuildInfo, error) {
+	apiURL := strings.TrimRight(jobURL, "/") + "/api/json?tree=url,building,result"
+	var info BuildInfo
+	found, err := getJSON(ctx, apiURL, token, "build info", &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, buildNotFound(jobURL)
+	}
+	if info.URL == "" {
+		info.URL = jobURL
+	}
+	return &info, nil
+}
+
+// StopBuild asks Jenkins to abort the running build at buildURL.
+func StopBuild(buildURL, token string) error {
+	resp, err := postWithCrumb(RootURL(buildURL), strings.TrimRight(buildURL, "/")+"/stop", token, url.Values{})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("build not found (404): %s", buildURL)
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("forbidden (403): CSRF crumb rejected or missing Cancel permission")
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("http error: %s", resp.Status)
+	}
+	return nil
+}
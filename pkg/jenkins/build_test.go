@@ -0,0 +1,199 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeJenkins serves a crumb issuer plus the given handlers.
+func newFakeJenkins(t *testing.T, handlers map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/crumbIssuer/api/xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "Jenkins-Crumb:abc123")
+	})
+	for pattern, h := range handlers {
+		mux.HandleFunc(pattern, h)
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTriggerBuild(t *testing.T) {
+	var gotAuth, gotCrumb, gotMethod string
+	var server *httptest.Server
+	server = newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/job/test/build": func(w http.ResponseWriter, r *http.Request) {
+			gotMethod = r.Method
+			gotAuth = r.Header.Get("Authorization")
+			gotCrumb = r.Header.Get("Jenkins-Crumb")
+			w.Header().Set("Location", server.URL+"/queue/item/1/")
+			w.WriteHeader(http.StatusCreated)
+		},
+	})
+
+	queueURL, err := TriggerBuild(server.URL+"/job/test", "dG9rZW4=", nil)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/queue/item/1/", queueURL)
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "Basic dG9rZW4=", gotAuth)
+	assert.Equal(t, "abc123", gotCrumb)
+}
+
+func TestTriggerBuild_WithParameters(t *testing.T) {
+	var gotBranch string
+	server := newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/job/test/buildWithParameters": func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			gotBranch = r.PostForm.Get("BRANCH")
+			w.WriteHeader(http.StatusCreated)
+		},
+	})
+
+	// A build URL is accepted and the build number stripped.
+	_, err := TriggerBuild(server.URL+"/job/test/42/", "token", map[string]string{"BRANCH": "main"})
+	require.NoError(t, err)
+	assert.Equal(t, "main", gotBranch)
+}
+
+func TestTriggerBuild_NoCrumbIssuer(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/job/test/build" {
+			called = true
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, err := TriggerBuild(server.URL+"/job/test", "token", nil)
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestTriggerBuild_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{"forbidden", http.StatusForbidden, "CSRF"},
+		{"not found", http.StatusNotFound, "job not found"},
+		{"server error", http.StatusInternalServerError, "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeJenkins(t, map[string]http.HandlerFunc{
+				"/job/test/build": func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+				},
+			})
+
+			_, err := TriggerBuild(server.URL+"/job/test", "token", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestWaitForQueuedBuild(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/queue/item/1/api/json", r.URL.Path)
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		if polls < 2 {
+			fmt.Fprint(w, `{"executable":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"executable":{"url":"http://jenkins/job/test/7/"}}`)
+	}))
+	defer server.Close()
+
+	buildURL, err := WaitForQueuedBuild(server.URL+"/queue/item/1/", "token", time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "http://jenkins/job/test/7/", buildURL)
+	assert.Equal(t, 2, polls)
+}
+
+func TestWaitForQueuedBuild_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"cancelled":true}`)
+	}))
+	defer server.Close()
+
+	_, err := WaitForQueuedBuild(server.URL+"/queue/item/1", "token", time.Millisecond, time.Second)
+	assert.ErrorContains(t, err, "cancelled")
+}
+
+func TestWaitForQueuedBuild_RetriesFailedPolls(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusNotFound)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			fmt.Fprint(w, `{"executable":{"url":"http://jenkins/job/test/7/"}}`)
+		}
+	}))
+	defer server.Close()
+
+	buildURL, err := WaitForQueuedBuild(server.URL+"/queue/item/1/", "token", time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "http://jenkins/job/test/7/", buildURL)
+	assert.Equal(t, 3, polls)
+}
+
+func TestWaitForQueuedBuild_TimeoutReportsLastError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := WaitForQueuedBuild(server.URL+"/queue/item/1/", "token", time.Millisecond, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "did not leave the queue within 50ms")
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestGetBuildInfo(t *testing.T) {
+	server := newFakeJenkins(t, map[string]http.HandlerFunc{
+		"/job/test/7/api/json": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Basic token", r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"building":true,"result":null}`)
+		},
+	})
+
+	info, err := GetBuildInfo(server.URL+"/job/test/7/", "token")
+	require.NoError(t, err)
+	assert.True(t, info.Building)
+	assert.Equal(t, server.URL+"/job/test/7/", info.URL, "the requested URL stands in for a missing one")
+
+	_, err = GetBuildInfo(server.URL+"/job/test/8/", "token")
+	assert.ErrorContains(t, err, "build not found (404)")
+}
+
+func TestJobURLFromBuildURL(t *testing.T) {
+	assert.Equal(t, "http://j/job/foo", JobURLFromBuildURL("http://j/job/foo/42/"))
+	assert.Equal(t, "http://j/job/foo", JobURLFromBuildURL("http://j/job/foo/"))
+	assert.Equal(t, "http://j/job/a/job/b", JobURLFromBuildURL("http://j/job/a/job/b/3"))
+	assert.Equal(t, "http://j/job/foo", JobURLFromBuildURL("http://j/job/foo/lastBuild"))
+}
+
+func TestLastBuildURL(t *testing.T) {
+	assert.Equal(t, "http://j/job/foo/lastBuild", LastBuildURL("http://j/job/foo"))
+	assert.Equal(t, "http://j/job/foo/lastBuild", LastBuildURL("http://j/job/foo/42/"))
+	assert.Equal(t, "http://j/job/foo/lastBuild", LastBuildURL("http://j/job/foo/lastBuild"))
+}
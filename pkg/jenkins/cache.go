@@ -0,0 +1,45 @@
+package jenkins
+
+import "sync"
+
+// ResponseCache remembers the last job status fetched for each job URL along
+// with its ETag and Last-Modified validators, so that a poll answered with
+// 304 Not Modified can reuse it. The zero value is not usable; a nil cache
+// disables conditional requests.
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedStatus
+}
+
+type cachedStatus struct {
+	ETag         string
+	LastModified string
+	Status       JobStatus
+}
+
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{entries: make(map[string]cachedStatus)}
+}
+
+func (c *ResponseCache) get(url string) (cachedStatus, bool) {
+	if c == nil {
+		return cachedStatus{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[url]
+	return entry, ok
+}
+
+func (c *ResponseCache) put(url string, entry cachedStatus) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry.ETag == "" && entry.LastModified == "" {
+		delete(c.entries, url)
+		return
+	}
+	c.entries[url] = entry
+}
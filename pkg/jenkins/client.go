@@ -0,0 +1,167 @@
+package jenkins
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	// proxyEnvVar names an explicit proxy for jw that takes precedence over
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	proxyEnvVar = "JW_PROXY"
+	// skipVerifyEnvVar disables TLS verification when set to "true". Insecure:
+	// only meant for development instances with self-signed certificates.
+	skipVerifyEnvVar = "JW_TLS_SKIP_VERIFY"
+	// caBundleEnvVar points at a PEM file of extra CA certificates to trust.
+	caBundleEnvVar = "JW_CA_BUNDLE"
+)
+
+// Connection pool settings: a daemon polls every job on the same few Jenkins
+// hosts, so idle connections are kept around for the next poll.
+const (
+	maxIdleConnsPerHost = 10
+	idleConnTimeout     = 90 * time.Second
+)
+
+// DefaultClient is shared by every request in this package so connections to
+// a Jenkins host are reused between polls. It is configured from the
+// environment when the program starts.
+var DefaultClient = NewClient()
+
+type clientOptions struct {
+	proxy              string
+	insecureSkipVerify bool
+	caBundle           string
+}
+
+// Option configures a client built by NewClient.
+type Option func(*clientOptions)
+
+// WithProxy routes all requests through proxyURL, overriding the environment.
+func WithProxy(proxyURL string) Option {
+	return func(o *clientOptions) {
+		o.proxy = proxyURL
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification. This is
+// insecure and should only be used against development instances.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(o *clientOptions) {
+		o.insecureSkipVerify = skip
+	}
+}
+
+// WithCABundle trusts the CA certificates in the PEM file at path in addition
+// to the system roots.
+func WithCABundle(path string) Option {
+	return func(o *clientOptions) {
+		o.caBundle = path
+	}
+}
+
+// NewClient returns an HTTP client for talking to Jenkins. Unless WithProxy is
+// given, the proxy comes from JW_PROXY, then HTTP_PROXY/HTTPS_PROXY/NO_PROXY.
+// TLS defaults come from JW_TLS_SKIP_VERIFY and JW_CA_BUNDLE.
+func NewClient(opts ...Option) *http.Client {
+	o := clientOptions{
+		proxy:              os.Getenv(proxyEnvVar),
+		insecureSkipVerify: os.Getenv(skipVerifyEnvVar) == "true",
+		caBundle:           os.Getenv(caBundleEnvVar),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	client := &http.Client{Timeout: httpTimeout}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc(o.proxy)
+	transport.DisableKeepAlives = false
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	transport.IdleConnTimeout = idleConnTimeout
+
+	tlsConfig, err := buildTLSConfig(o)
+	if err != nil {
+		client.Transport = errorTransport{err: err}
+		return client
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	client.Transport = transport
+	return client
+}
+
+// withCookieJar returns a copy of client with its own cookie jar, for flows
+// like CSRF crumbs that are tied to a session. The transport, and so the
+// connection pool, is shared.
+func withCookieJar(client *http.Client) *http.Client {
+	jar, _ := cookiejar.New(nil)
+	c := *client
+	c.Jar = jar
+	return &c
+}
+
+// drainAndClose reads what is left of body before closing it, so the
+// connection can go back to the pool.
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	body.Close()
+}
+
+func buildTLSConfig(o clientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: o.insecureSkipVerify}
+	if o.caBundle == "" {
+		return cfg, nil
+	}
+
+	pem, err := os.ReadFile(o.caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", o.caBundle)
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
+
+// errorTransport fails every request with err, so configuration problems
+// surface from the request that hits them.
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+func proxyFunc(proxy string) func(*http.Request) (*url.URL, error) {
+	if proxy == "" {
+		envProxy := httpproxy.FromEnvironment().ProxyFunc()
+		return func(req *http.Request) (*url.URL, error) {
+			return envProxy(req.URL)
+		}
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", proxy, err)
+		}
+	}
+	return http.ProxyURL(proxyURL)
+}
@@ -0,0 +1,194 @@
+package jenkins
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeProxy returns a plain-HTTP forward proxy that answers every request
+// itself and records the absolute URL it was asked for.
+func newFakeProxy(t *testing.T) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	var requested atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, &requested
+}
+
+func TestNewClient_WithProxy(t *testing.T) {
+	proxy, requested := newFakeProxy(t)
+
+	client := NewClient(WithProxy(proxy.URL))
+	resp, err := client.Get("http://jenkins.test/api/json")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "http://jenkins.test/api/json", requested.Load())
+}
+
+func TestNewClient_JWProxyEnv(t *testing.T) {
+	proxy, requested := newFakeProxy(t)
+	t.Setenv("JW_PROXY", proxy.URL)
+	t.Setenv("HTTP_PROXY", "http://127.0.0.1:1")
+
+	resp, err := NewClient().Get("http://jenkins.test/job/a/api/json")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "http://jenkins.test/job/a/api/json", requested.Load())
+}
+
+func TestNewClient_StandardProxyEnv(t *testing.T) {
+	proxy, requested := newFakeProxy(t)
+	t.Setenv("JW_PROXY", "")
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "skip.test")
+
+	resp, err := NewClient().Get("http://jenkins.test/api/json")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "http://jenkins.test/api/json", requested.Load())
+
+	req, err := http.NewRequest("GET", "http://skip.test/api/json", nil)
+	require.NoError(t, err)
+	proxyURL, err := NewClient().Transport.(*http.Transport).Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL, "NO_PROXY hosts should bypass the proxy")
+}
+
+func TestNewClient_InvalidProxy(t *testing.T) {
+	_, err := NewClient(WithProxy("://bad")).Get("http://jenkins.test/api/json")
+	assert.ErrorContains(t, err, "invalid proxy URL")
+}
+
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o644))
+	return path
+}
+
+func TestNewClient_TLSVerification(t *testing.T) {
+	t.Setenv("JW_TLS_SKIP_VERIFY", "")
+	t.Setenv("JW_CA_BUNDLE", "")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := NewClient().Get(server.URL)
+	assert.Error(t, err, "self-signed certificate should be rejected by default")
+
+	resp, err := NewClient(WithInsecureSkipVerify(true)).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	t.Setenv("JW_TLS_SKIP_VERIFY", "true")
+	resp, err = NewClient().Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestNewClient_CABundle(t *testing.T) {
+	t.Setenv("JW_TLS_SKIP_VERIFY", "")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	trusted := httptest.NewTLSServer(handler)
+	defer trusted.Close()
+
+	// A second server gets its own certificate, which the bundle does not cover.
+	other := httptest.NewUnstartedServer(handler)
+	otherCert, err := tls.X509KeyPair(selfSignedPair(t))
+	require.NoError(t, err)
+	other.TLS = &tls.Config{Certificates: []tls.Certificate{otherCert}}
+	other.StartTLS()
+	defer other.Close()
+
+	bundle := writeServerCA(t, trusted)
+
+	resp, err := NewClient(WithCABundle(bundle)).Get(trusted.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = NewClient(WithCABundle(bundle)).Get(other.URL)
+	assert.Error(t, err, "certificate not signed by the bundle CA should be rejected")
+
+	t.Setenv("JW_CA_BUNDLE", bundle)
+	resp, err = NewClient().Get(trusted.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestNewClient_CABundleMissing(t *testing.T) {
+	_, err := NewClient(WithCABundle(filepath.Join(t.TempDir(), "missing.pem"))).Get("https://jenkins.test")
+	assert.ErrorContains(t, err, "reading CA bundle")
+}
+
+// selfSignedPair generates a throwaway self-signed certificate for 127.0.0.1.
+func selfSignedPair(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "other"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestDefaultClient_ReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"building":true}` + "\n"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	for range 2 {
+		_, _, err := GetJobStatus(server.URL+"/job/app/1", "token")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), conns.Load())
+}
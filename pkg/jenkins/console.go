@@ -0,0 +1,63 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// consolePollInterval is how long StreamConsoleLog waits before asking for
+// more output while the build is still writing its log.
+var consolePollInterval = 2 * time.Second
+
+// StreamConsoleLog copies the console output of the build at buildURL to w
+// as it is produced, returning once Jenkins reports no more data or ctx is
+// cancelled.
+func StreamConsoleLog(ctx context.Context, buildURL, token string, w io.Writer) error {
+	base := strings.TrimRight(buildURL, "/") + "/logText/progressiveText"
+	client := DefaultClient
+	start := int64(0)
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?start=%d", base, start), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Basic "+token)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusNotFound {
+				return fmt.Errorf("build not found (404): %s", buildURL)
+			}
+			return fmt.Errorf("http error: %s", resp.Status)
+		}
+
+		_, err = io.Copy(w, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if size, err := strconv.ParseInt(resp.Header.Get("X-Text-Size"), 10, 64); err == nil {
+			start = size
+		}
+		if resp.Header.Get("X-More-Data") != "true" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(consolePollInterval):
+		}
+	}
+}
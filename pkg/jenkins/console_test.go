@@ -0,0 +1,75 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamConsoleLog(t *testing.T) {
+	pages := []string{"Started by user\n", "Building...\n", "Finished: SUCCESS\n"}
+	var starts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/job/app/7/logText/progressiveText", r.URL.Path)
+		assert.Equal(t, "Basic token", r.Header.Get("Authorization"))
+		start := r.URL.Query().Get("start")
+		starts = append(starts, start)
+
+		// Serve the page beginning at the requested offset.
+		offset, _ := strconv.Atoi(start)
+		served, page := 0, -1
+		for i, p := range pages {
+			if served == offset {
+				page = i
+				break
+			}
+			served += len(p)
+		}
+		require.NotEqual(t, -1, page, "unexpected offset %d", offset)
+
+		w.Header().Set("X-Text-Size", strconv.Itoa(offset+len(pages[page])))
+		if page < len(pages)-1 {
+			w.Header().Set("X-More-Data", "true")
+		}
+		_, _ = w.Write([]byte(pages[page]))
+	}))
+	defer server.Close()
+
+	orig := consolePollInterval
+	consolePollInterval = time.Millisecond
+	t.Cleanup(func() { consolePollInterval = orig })
+
+	var out bytes.Buffer
+	require.NoError(t, StreamConsoleLog(context.Background(), server.URL+"/job/app/7/", "token", &out))
+	assert.Equal(t, "Started by user\nBuilding...\nFinished: SUCCESS\n", out.String())
+	assert.Equal(t, []string{"0", "16", "28"}, starts)
+}
+
+func TestStreamConsoleLog_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Text-Size", "0")
+		w.Header().Set("X-More-Data", "true")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := StreamConsoleLog(ctx, server.URL+"/job/app/7", "token", &bytes.Buffer{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStreamConsoleLog_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := StreamConsoleLog(context.Background(), server.URL+"/job/app/7", "token", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "404")
+}
@@ -1,9 +1,11 @@
 package jenkins
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
@@ -22,26 +24,66 @@ type JobStatus struct {
 	Building  bool   `json:"building"`
 	Result    string `json:"result"`
 	Timestamp int64  `json:"timestamp"`
+	Duration  int64  `json:"duration"`
+	Number    int    `json:"number"`
+}
+
+// BuildDuration returns how long the build took, falling back to the time
+// since it started when Jenkins has not reported a duration yet.
+func (s *JobStatus) BuildDuration() time.Duration {
+	if s.Duration > 0 {
+		return time.Duration(s.Duration) * time.Millisecond
+	}
+	if s.Timestamp > 0 {
+		return time.Since(time.UnixMilli(s.Timestamp))
+	}
+	return 0
 }
 
 // GetJobStatus fetches the status of a Jenkins job, and returns the JobStatus
 // struct, http status code, and error if any.
 func GetJobStatus(jenkinsURL, token string) (*JobStatus, int, error) {
-	apiURL := jenkinsURL + "/api/json?tree=building,result,timestamp"
+	return GetJobStatusCtx(context.Background(), jenkinsURL, token)
+}
+
+// GetJobStatusCtx is GetJobStatus with a context that cancels the request.
+func GetJobStatusCtx(ctx context.Context, jenkinsURL, token string) (*JobStatus, int, error) {
+	return GetJobStatusCached(ctx, jenkinsURL, token, nil)
+}
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+// GetJobStatusCached is GetJobStatusCtx with conditional requests: the
+// validators of the previous response in cache are sent along, and a 304 Not
+// Modified answer returns the cached status with http.StatusNotModified.
+func GetJobStatusCached(ctx context.Context, jenkinsURL, token string, cache *ResponseCache) (*JobStatus, int, error) {
+	apiURL := jenkinsURL + "/api/json?tree=building,result,timestamp,duration,number"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	req.Header.Set("Authorization", "Basic "+token)
+	cached, hasCached := cache.get(jenkinsURL)
+	if hasCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
-	client := &http.Client{Timeout: httpTimeout}
+	client := DefaultClient
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		status := cached.Status
+		return &status, resp.StatusCode, nil
+	}
 
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, resp.StatusCode, fmt.Errorf("job not found (404)")
@@ -60,5 +102,279 @@ func GetJobStatus(jenkinsURL, token string) (*JobStatus, int, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
 		return nil, resp.StatusCode, err
 	}
+	cache.put(jenkinsURL, cachedStatus{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Status:       status,
+	})
 	return &status, resp.StatusCode, nil
 }
+
+// VerifyCredentials checks that token is accepted by the Jenkins instance at
+// baseURL by requesting its root API endpoint.
+func VerifyCredentials(baseURL, token string) error {
+	req, err := http.NewRequest("GET", strings.TrimRight(baseURL, "/")+"/api/json", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Basic "+token)
+
+	client := DefaultClient
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("authentication failed (status: %s)", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http error: %s", resp.Status)
+	}
+	return nil
+}
+
+// getJSON fetches apiURL and decodes the JSON answer into v, with numbers in
+// untyped fields kept as json.Number. It reports false, without an error, if
+// Jenkins answers 404; what names the data in decoding errors.
+func getJSON(ctx context.Context, apiURL, token, what string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Basic "+token)
+
+	resp, err := DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer drainAndClose(resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("http error: %s", resp.Status)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return false, fmt.Errorf("error parsing %s: %w", what, err)
+	}
+	return true, nil
+}
+
+func buildNotFound(buildURL string) error {
+	return fmt.Errorf("build not found (404): %s", buildURL)
+}
+
+type buildParameters struct {
+	Actions []struct {
+		Parameters []struct {
+			Name  string `json:"name"`
+			Value any    `json:"value"`
+		} `json:"parameters"`
+	} `json:"actions"`
+}
+
+// GetBuildParameters returns the parameters the build at buildURL was started
+// with. Non-string values are formatted with fmt.Sprint.
+func GetBuildParameters(buildURL, token string) (map[string]string, error) {
+	return GetBuildParametersCtx(context.Background(), buildURL, token)
+}
+
+// GetBuildParametersCtx is GetBuildParameters with a context that cancels the request.
+func GetBuildParametersCtx(ctx context.Context, buildURL, token string) (map[string]string, error) {
+	apiURL := strings.TrimRight(buildURL, "/") + "/api/json?tree=actions[parameters[name,value]]"
+	var body buildParameters
+	found, err := getJSON(ctx, apiURL, token, "build parameters", &body)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, buildNotFound(buildURL)
+	}
+
+	params := make(map[string]string)
+	for _, action := range body.Actions {
+		for _, p := range action.Parameters {
+			if p.Name == "" {
+				continue
+			}
+			switch v := p.Value.(type) {
+			case nil:
+				params[p.Name] = ""
+			case string:
+				params[p.Name] = v
+			default:
+				params[p.Name] = fmt.Sprint(v)
+			}
+		}
+	}
+	return params, nil
+}
+
+type buildCauses struct {
+	Actions []struct {
+		Causes []struct {
+			ShortDescription string `json:"shortDescription"`
+		} `json:"causes"`
+	} `json:"actions"`
+}
+
+// GetBuildCause describes who or what started the build at buildURL, e.g.
+// "John Doe" for "Started by user John Doe" or "timer" for "Started by
+// timer". Multiple causes are joined with "; ".
+func GetBuildCause(buildURL, token string) (string, error) {
+	return GetBuildCauseCtx(context.Background(), buildURL, token)
+}
+
+// GetBuildCauseCtx is GetBuildCause with a context that cancels the request.
+func GetBuildCauseCtx(ctx context.Context, buildURL, token string) (string, error) {
+	apiURL := strings.TrimRight(buildURL, "/") + "/api/json?tree=actions[causes[shortDescription]]"
+	var body buildCauses
+	found, err := getJSON(ctx, apiURL, token, "build cause", &body)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", buildNotFound(buildURL)
+	}
+
+	var causes []string
+	for _, action := range body.Actions {
+		for _, c := range action.Causes {
+			desc := strings.TrimPrefix(c.ShortDescription, "Started by ")
+			desc = strings.TrimPrefix(desc, "user ")
+			if desc != "" && !slices.Contains(causes, desc) {
+				causes = append(causes, desc)
+			}
+		}
+	}
+	return strings.Join(causes, "; "), nil
+}
+
+// TestSummary counts the test results Jenkins recorded for a build.
+type TestSummary struct {
+	Total   int `json:"totalCount"`
+	Failed  int `json:"failCount"`
+	Skipped int `json:"skipCount"`
+}
+
+// Passed is the number of tests that neither failed nor were skipped.
+func (s *TestSummary) Passed() int {
+	return s.Total - s.Failed - s.Skipped
+}
+
+// GetTestSummary fetches the test results of the build at buildURL. It returns
+// nil without an error if the build has no test report.
+func GetTestSummary(buildURL, token string) (*TestSummary, error) {
+	return GetTestSummaryCtx(context.Background(), buildURL, token)
+}
+
+// GetTestSummaryCtx is GetTestSummary with a context that cancels the request.
+func GetTestSummaryCtx(ctx context.Context, buildURL, token string) (*TestSummary, error) {
+	apiURL := strings.TrimRight(buildURL, "/") + "/testReport/api/json?tree=totalCount,failCount,skipCount"
+	var summary TestSummary
+	found, err := getJSON(ctx, apiURL, token, "test report", &summary)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// ChangeEntry is one SCM commit included in a build.
+type ChangeEntry struct {
+	CommitID string
+	Message  string
+	Author   string
+}
+
+type changeSet struct {
+	Items []struct {
+		CommitID string `json:"commitId"`
+		Msg      string `json:"msg"`
+		Author   struct {
+			FullName string `json:"fullName"`
+		} `json:"author"`
+	} `json:"items"`
+}
+
+// buildChangeSets holds a build's changes: freestyle builds report one
+// changeSet, pipeline builds a changeSets list with one per checkout.
+type buildChangeSets struct {
+	ChangeSet  *changeSet  `json:"changeSet"`
+	ChangeSets []changeSet `json:"changeSets"`
+}
+
+const changeSetTree = "items[commitId,msg,author[fullName]]"
+
+// GetSCMChanges lists the commits included in the build at buildURL. It
+// returns nil if the job has no SCM configured.
+func GetSCMChanges(buildURL, token string) ([]ChangeEntry, error) {
+	return GetSCMChangesCtx(context.Background(), buildURL, token)
+}
+
+// GetSCMChangesCtx is GetSCMChanges with a context that cancels the request.
+func GetSCMChangesCtx(ctx context.Context, buildURL, token string) ([]ChangeEntry, error) {
+	apiURL := strings.TrimRight(buildURL, "/") + "/api/json?tree=changeSet[" + changeSetTree + "],changeSets[" + changeSetTree + "]"
+	var body buildChangeSets
+	found, err := getJSON(ctx, apiURL, token, "changes", &body)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, buildNotFound(buildURL)
+	}
+
+	sets := body.ChangeSets
+	if body.ChangeSet != nil {
+		sets = append([]changeSet{*body.ChangeSet}, sets...)
+	}
+	var changes []ChangeEntry
+	for _, set := range sets {
+		for _, item := range set.Items {
+			changes = append(changes, ChangeEntry{
+				CommitID: item.CommitID,
+				Message:  item.Msg,
+				Author:   item.Author.FullName,
+			})
+		}
+	}
+	return changes, nil
+}
+
+// StageStatus is one stage of a pipeline build, as reported by the Pipeline
+// Stage View plugin. Status is e.g. SUCCESS, FAILED, IN_PROGRESS or
+// NOT_EXECUTED.
+type StageStatus struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	DurationMillis int64  `json:"durationMillis"`
+}
+
+// Failed reports whether the stage failed.
+func (s StageStatus) Failed() bool {
+	return s.Status == "FAILED"
+}
+
+// GetPipelineStages fetches the stages of the pipeline build at buildURL. It
+// returns nil without an error if the build is not a pipeline.
+func GetPipelineStages(buildURL, token string) ([]StageStatus, error) {
+	return GetPipelineStagesCtx(context.Background(), buildURL, token)
+}
+
+// GetPipelineStagesCtx is GetPipelineStages with a context that cancels the
+// request.
+func GetPipelineStagesCtx(ctx context.Context, buildURL, token string) ([]StageStatus, error) {
+	apiURL := strings.TrimRight(buildURL, "/") + "/wfapi/describe"
+	var body struct {
+		Stages []StageStatus `json:"stages"`
+	}
+	if _, err := getJSON(ctx, apiURL, token, "pipeline stages", &body); err != nil {
+		return nil, err
+	}
+	return body.Stages, nil
+}
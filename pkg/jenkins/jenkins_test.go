@@ -1,12 +1,15 @@
 package jenkins
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetJobStatus(t *testing.T) {
@@ -80,3 +83,293 @@ func TestGetJobStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/json", r.URL.Path)
+		if r.Header.Get("Authorization") != "Basic good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	assert.NoError(t, VerifyCredentials(server.URL+"/", "good-token"))
+
+	err := VerifyCredentials(server.URL, "bad-token")
+	assert.ErrorContains(t, err, "authentication failed")
+}
+
+func TestGetBuildParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/job/app/42/api/json", r.URL.Path)
+		assert.Equal(t, "actions[parameters[name,value]]", r.URL.Query().Get("tree"))
+		assert.Equal(t, "Basic token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"_class": "hudson.model.FreeStyleBuild",
+			"actions": [
+				{"_class": "hudson.model.CauseAction"},
+				{
+					"_class": "hudson.model.ParametersAction",
+					"parameters": [
+						{"_class": "hudson.model.StringParameterValue", "name": "BRANCH", "value": "main"},
+						{"_class": "hudson.model.BooleanParameterValue", "name": "DEPLOY", "value": true},
+						{"_class": "hudson.model.StringParameterValue", "name": "RETRIES", "value": 3},
+						{"_class": "hudson.model.PasswordParameterValue", "name": "SECRET"}
+					]
+				},
+				{}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	params, err := GetBuildParameters(server.URL+"/job/app/42/", "token")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BRANCH":  "main",
+		"DEPLOY":  "true",
+		"RETRIES": "3",
+		"SECRET":  "",
+	}, params)
+}
+
+func TestGetBuildParameters_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := GetBuildParameters(server.URL+"/job/app/42", "token")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestGetBuildCause(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "user",
+			body: `{"actions":[{"_class":"hudson.model.CauseAction","causes":[{"_class":"hudson.model.Cause$UserIdCause","shortDescription":"Started by user John Doe"}]},{}]}`,
+			want: "John Doe",
+		},
+		{
+			name: "several causes",
+			body: `{"actions":[{"causes":[{"shortDescription":"Started by timer"},{"shortDescription":"Started by an SCM change"},{"shortDescription":"Started by timer"}]}]}`,
+			want: "timer; an SCM change",
+		},
+		{
+			name: "no causes",
+			body: `{"actions":[{}]}`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/job/app/7/api/json", r.URL.Path)
+				assert.Equal(t, "actions[causes[shortDescription]]", r.URL.Query().Get("tree"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cause, err := GetBuildCause(server.URL+"/job/app/7/", "token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cause)
+		})
+	}
+}
+
+func TestGetTestSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/job/app/5/testReport/api/json", r.URL.Path)
+		assert.Equal(t, "totalCount,failCount,skipCount", r.URL.Query().Get("tree"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"_class":"hudson.tasks.junit.TestResult","failCount":3,"skipCount":1,"totalCount":46}`))
+	}))
+	defer server.Close()
+
+	summary, err := GetTestSummary(server.URL+"/job/app/5/", "token")
+	require.NoError(t, err)
+	assert.Equal(t, &TestSummary{Total: 46, Failed: 3, Skipped: 1}, summary)
+	assert.Equal(t, 42, summary.Passed())
+}
+
+func TestGetTestSummary_NoTestReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	summary, err := GetTestSummary(server.URL+"/job/app/5", "token")
+	require.NoError(t, err)
+	assert.Nil(t, summary)
+}
+
+func TestGetSCMChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []ChangeEntry
+	}{
+		{
+			name: "commits",
+			body: `{"_class":"hudson.model.FreeStyleBuild","changeSet":{"_class":"hudson.plugins.git.GitChangeSetList","items":[
+				{"commitId":"a1b2c3","msg":"Fix login redirect","author":{"fullName":"Jane Roe"}},
+				{"commitId":"d4e5f6","msg":"Bump deps","author":{"fullName":"John Doe"}}
+			]}}`,
+			want: []ChangeEntry{
+				{CommitID: "a1b2c3", Message: "Fix login redirect", Author: "Jane Roe"},
+				{CommitID: "d4e5f6", Message: "Bump deps", Author: "John Doe"},
+			},
+		},
+		{
+			name: "no SCM",
+			body: `{"_class":"hudson.model.FreeStyleBuild","changeSet":null}`,
+		},
+		{
+			name: "no changes",
+			body: `{"changeSet":{"items":[]}}`,
+		},
+		{
+			name: "pipeline with several checkouts",
+			body: `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowRun","changeSets":[
+				{"items":[{"commitId":"a1b2c3","msg":"Fix login redirect","author":{"fullName":"Jane Roe"}}]},
+				{"items":[{"commitId":"0f9e8d","msg":"Update shared library","author":{"fullName":"John Doe"}}]}
+			]}`,
+			want: []ChangeEntry{
+				{CommitID: "a1b2c3", Message: "Fix login redirect", Author: "Jane Roe"},
+				{CommitID: "0f9e8d", Message: "Update shared library", Author: "John Doe"},
+			},
+		},
+		{
+			name: "pipeline without changes",
+			body: `{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowRun","changeSets":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/job/app/3/api/json", r.URL.Path)
+				assert.Equal(t, "changeSet[items[commitId,msg,author[fullName]]],changeSets[items[commitId,msg,author[fullName]]]", r.URL.Query().Get("tree"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			changes, err := GetSCMChanges(server.URL+"/job/app/3", "token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, changes)
+		})
+	}
+}
+
+func TestGetJobStatusCached_NotModified(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			assert.Equal(t, `"v1"`, r.Header.Get("If-None-Match"))
+			assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", r.Header.Get("If-Modified-Since"))
+			// No body and no JSON content type: decoding would fail.
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte(`{"building":true,"timestamp":1700000000000}`))
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache()
+	first, code, err := GetJobStatusCached(context.Background(), server.URL+"/job/app/1", "token", cache)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	second, code, err := GetJobStatusCached(context.Background(), server.URL+"/job/app/1", "token", cache)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, code)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 2, requests)
+}
+
+func TestGetJobStatusCached_NoValidators(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		assert.Empty(t, r.Header.Get("If-Modified-Since"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"building":true}`))
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache()
+	for range 2 {
+		_, _, err := GetJobStatusCached(context.Background(), server.URL+"/job/app/1", "token", cache)
+		require.NoError(t, err)
+	}
+}
+
+func TestGetJobStatusCtx_Cancelled(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	start := time.Now()
+	_, _, err := GetJobStatusCtx(ctx, server.URL+"/job/app/1", "token")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), httpTimeout)
+}
+
+func TestGetPipelineStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/job/app/5/wfapi/describe", r.URL.Path)
+		assert.Equal(t, "Basic token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"_links":{"self":{"href":"/job/app/5/wfapi/describe"}},"id":"5","name":"#5","status":"FAILED","durationMillis":95000,"stages":[
+			{"_links":{},"id":"6","name":"Checkout","execNode":"","status":"SUCCESS","startTimeMillis":1700000000000,"durationMillis":3200,"pauseDurationMillis":0},
+			{"_links":{},"id":"12","name":"Test","execNode":"","status":"FAILED","startTimeMillis":1700000003200,"durationMillis":91000,"pauseDurationMillis":0},
+			{"_links":{},"id":"30","name":"Deploy","execNode":"","status":"NOT_EXECUTED","startTimeMillis":0,"durationMillis":0,"pauseDurationMillis":0}
+		]}`))
+	}))
+	defer server.Close()
+
+	stages, err := GetPipelineStages(server.URL+"/job/app/5/", "token")
+	require.NoError(t, err)
+	assert.Equal(t, []StageStatus{
+		{Name: "Checkout", Status: "SUCCESS", DurationMillis: 3200},
+		{Name: "Test", Status: "FAILED", DurationMillis: 91000},
+		{Name: "Deploy", Status: "NOT_EXECUTED"},
+	}, stages)
+	assert.True(t, stages[1].Failed())
+	assert.False(t, stages[2].Failed())
+}
+
+func TestGetPipelineStages_NotAPipeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	stages, err := GetPipelineStages(server.URL+"/job/app/5", "token")
+	require.NoError(t, err)
+	assert.Nil(t, stages)
+}
@@ -0,0 +1,33 @@
+package jenkins
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// NormalizeJobURL returns the canonical form of a job or build URL so that
+// near-duplicates compare equal: surrounding whitespace, any query string and
+// fragment, a trailing "/api/json" and trailing slashes are removed. The URL
+// must be absolute http or https.
+func NormalizeJobURL(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid job URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("job URL must start with http:// or https://: %s", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("job URL has no host: %s", raw)
+	}
+
+	u.RawQuery = ""
+	u.Fragment = ""
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	path = strings.TrimSuffix(path, "/api/json")
+	u.RawPath = strings.TrimRight(path, "/")
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return u.String(), nil
+}
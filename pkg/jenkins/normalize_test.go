@@ -0,0 +1,46 @@
+package jenkins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeJobURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr string
+	}{
+		{name: "canonical", raw: "https://jenkins/job/myjob/42", want: "https://jenkins/job/myjob/42"},
+		{name: "trailing slash", raw: "https://jenkins/job/myjob/42/", want: "https://jenkins/job/myjob/42"},
+		{name: "several trailing slashes", raw: "https://jenkins/job/myjob/42///", want: "https://jenkins/job/myjob/42"},
+		{name: "api json", raw: "https://jenkins/job/myjob/42/api/json", want: "https://jenkins/job/myjob/42"},
+		{name: "api json with slash", raw: "https://jenkins/job/myjob/42/api/json/", want: "https://jenkins/job/myjob/42"},
+		{name: "api json with query", raw: "https://jenkins/job/myjob/42/api/json?pretty=true", want: "https://jenkins/job/myjob/42"},
+		{name: "query", raw: "https://jenkins/job/myjob/42/?delay=0sec", want: "https://jenkins/job/myjob/42"},
+		{name: "fragment", raw: "https://jenkins/job/myjob/42/#footer", want: "https://jenkins/job/myjob/42"},
+		{name: "whitespace", raw: "  http://jenkins:8080/job/myjob/\n", want: "http://jenkins:8080/job/myjob"},
+		{name: "folder job", raw: "https://jenkins/job/team/job/app/job/main/7/", want: "https://jenkins/job/team/job/app/job/main/7"},
+		{name: "escaped branch", raw: "https://jenkins/job/app/job/feature%2Flogin/3/", want: "https://jenkins/job/app/job/feature%2Flogin/3"},
+		{name: "root", raw: "https://jenkins/", want: "https://jenkins"},
+		{name: "no scheme", raw: "jenkins/job/myjob/42", wantErr: "must start with http:// or https://"},
+		{name: "other scheme", raw: "ftp://jenkins/job/myjob", wantErr: "must start with http:// or https://"},
+		{name: "no host", raw: "https:///job/myjob", wantErr: "no host"},
+		{name: "unparsable", raw: "https://jen kins/%zz", wantErr: "invalid job URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeJobURL(tt.raw)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
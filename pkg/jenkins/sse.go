@@ -0,0 +1,256 @@
+package jenkins
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The sse-gateway plugin's endpoints: connect registers a client and starts
+// its session, configure sets what it is subscribed to and listen streams
+// the events.
+const (
+	sseConnectPath   = "/sse-gateway/connect"
+	sseConfigurePath = "/sse-gateway/configure"
+	sseListenPath    = "/sse-gateway/listen/"
+)
+
+// ErrSSEUnavailable is returned by SubscribeBuildEvents when the Jenkins
+// instance does not run the sse-gateway plugin.
+var ErrSSEUnavailable = errors.New("sse-gateway plugin not available")
+
+type sseConnectResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		JSessionID string `json:"jsessionid"`
+	} `json:"data"`
+}
+
+type sseSubscription struct {
+	Channel string `json:"jenkins_channel"`
+}
+
+type sseConfigureRequest struct {
+	DispatcherID string            `json:"dispatcherId"`
+	Subscribe    []sseSubscription `json:"subscribe"`
+	Unsubscribe  []sseSubscription `json:"unsubscribe"`
+}
+
+// BuildEvent is a job event pushed by the sse-gateway plugin, such as
+// "job_run_started" or "job_run_ended".
+type BuildEvent struct {
+	Type        string
+	JobName     string
+	URL         string // build URL relative to the Jenkins root, e.g. "job/app/12/"
+	BuildNumber int
+	Result      string // set on "job_run_ended"
+}
+
+type sseJobMessage struct {
+	Event    string `json:"jenkins_event"`
+	JobName  string `json:"job_name"`
+	URL      string `json:"jenkins_object_url"`
+	ObjectID string `json:"jenkins_object_id"`
+	Result   string `json:"job_run_status"`
+}
+
+// ForJob reports whether the event is about the job, or a build of the job,
+// at jobURL.
+func (e BuildEvent) ForJob(jobURL string) bool {
+	if e.URL == "" {
+		return false
+	}
+	rel := strings.Trim(strings.TrimPrefix(jobURL, RootURL(jobURL)), "/")
+	eventJob := JobURLFromBuildURL(strings.Trim(e.URL, "/"))
+	return rel == eventJob || strings.HasPrefix(rel, eventJob+"/")
+}
+
+// SubscribeBuildEvents streams job events from the sse-gateway plugin at
+// baseURL: it connects as a new client, subscribes it to the job channel and
+// listens. The channel is closed when the stream ends, fails or ctx is
+// cancelled; callers should fall back to polling then.
+func SubscribeBuildEvents(ctx context.Context, baseURL, token string) (<-chan BuildEvent, error) {
+	base := strings.TrimRight(baseURL, "/")
+	// The client's dispatcher lives in the session connect starts.
+	client := withCookieJar(DefaultClient)
+	authorize := func(req *http.Request) {
+		req.Header.Set("Authorization", "Basic "+token)
+	}
+
+	clientID, err := newSSEClientID()
+	if err != nil {
+		return nil, err
+	}
+	session, err := sseConnect(ctx, client, base, clientID, authorize)
+	if err != nil {
+		return nil, err
+	}
+	if err := sseConfigure(ctx, client, base, clientID, authorize); err != nil {
+		return nil, err
+	}
+
+	listenURL := base + sseListenPath + url.PathEscape(clientID)
+	if session != "" {
+		listenURL += ";jsessionid=" + session
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", listenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	authorize(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so the shared client's timeout
+	// cannot apply; ctx ends it instead.
+	streaming := *client
+	streaming.Timeout = 0
+	resp, err := streaming.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		drainAndClose(resp.Body)
+		return nil, fmt.Errorf("http error: %s", resp.Status)
+	}
+	if !isEventStream(resp) {
+		drainAndClose(resp.Body)
+		return nil, fmt.Errorf("expected an event stream but got %q", resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan BuildEvent)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		var data []string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line != "" {
+				if v, ok := strings.CutPrefix(line, "data:"); ok {
+					data = append(data, strings.TrimPrefix(v, " "))
+				}
+				continue
+			}
+			// A blank line ends the event.
+			event, ok := decodeBuildEvent(strings.Join(data, "\n"))
+			data = data[:0]
+			if !ok {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// sseConnect registers clientID with the gateway and returns its session ID.
+// A Jenkins without the plugin answers with a 404 or an HTML page, reported
+// as ErrSSEUnavailable.
+func sseConnect(ctx context.Context, client *http.Client, base, clientID string, authorize func(*http.Request)) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", base+sseConnectPath+"?clientId="+url.QueryEscape(clientID), nil)
+	if err != nil {
+		return "", err
+	}
+	authorize(req)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer drainAndClose(resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSSEUnavailable
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("http error: %s", resp.Status)
+	}
+	var connected sseConnectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&connected); err != nil {
+		return "", fmt.Errorf("%w: unexpected connect response: %v", ErrSSEUnavailable, err)
+	}
+	if connected.Status != "ok" {
+		return "", fmt.Errorf("connect failed with status %q", connected.Status)
+	}
+	return connected.Data.JSessionID, nil
+}
+
+// sseConfigure subscribes clientID to the job channel, which carries the
+// job_run_* events.
+func sseConfigure(ctx context.Context, client *http.Client, base, clientID string, authorize func(*http.Request)) error {
+	body, err := json.Marshal(sseConfigureRequest{
+		DispatcherID: clientID,
+		Subscribe:    []sseSubscription{{Channel: "job"}},
+		Unsubscribe:  []sseSubscription{},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", base+sseConfigurePath+"?batchId=1", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	authorize(req)
+	req.Header.Set("Content-Type", "application/json")
+	// API tokens are exempt from CSRF protection, but a crumb does no harm
+	// where Jenkins asks for one anyway.
+	if c, _, err := fetchCrumb(client, base, authorize); err == nil {
+		req.Header.Set(c.Header, c.Value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("subscribing to build events: http error: %s", resp.Status)
+	}
+	return nil
+}
+
+// newSSEClientID returns a random ID naming this subscriber to the gateway.
+func newSSEClientID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "jw-" + hex.EncodeToString(b), nil
+}
+
+// decodeBuildEvent parses the data of one SSE message, skipping heartbeats
+// and messages that are not about a job.
+func decodeBuildEvent(data string) (BuildEvent, bool) {
+	var msg sseJobMessage
+	if data == "" || json.Unmarshal([]byte(data), &msg) != nil || msg.Event == "" || msg.URL == "" {
+		return BuildEvent{}, false
+	}
+	number, _ := strconv.Atoi(msg.ObjectID)
+	return BuildEvent{
+		Type:        msg.Event,
+		JobName:     msg.JobName,
+		URL:         msg.URL,
+		BuildNumber: number,
+		Result:      msg.Result,
+	}, true
+}
+
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
@@ -0,0 +1,122 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSSEGateway serves the sse-gateway handshake, checking that each step
+// comes in order and in the session connect started, then streams stream.
+func fakeSSEGateway(t *testing.T, stream string) *httptest.Server {
+	t.Helper()
+	var clientID string
+	var configured bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Basic token", r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Path == sseConnectPath:
+			clientID = r.URL.Query().Get("clientId")
+			assert.NotEmpty(t, clientID)
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "s1"})
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"status":"ok","data":{"jsessionid":"s1","cookieName":"JSESSIONID"}}`)
+		case r.URL.Path == sseConfigurePath:
+			assert.Equal(t, "POST", r.Method)
+			cookie, err := r.Cookie("JSESSIONID")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "s1", cookie.Value)
+			}
+			var body sseConfigureRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, clientID, body.DispatcherID)
+			assert.Equal(t, []sseSubscription{{Channel: "job"}}, body.Subscribe)
+			configured = true
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case strings.HasPrefix(r.URL.Path, sseListenPath):
+			assert.True(t, configured, "listen before configure")
+			assert.Equal(t, sseListenPath+clientID+";jsessionid=s1", r.URL.Path)
+			w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+			fmt.Fprint(w, stream)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSubscribeBuildEvents(t *testing.T) {
+	server := fakeSSEGateway(t, ": heartbeat\n\n"+
+		"event: job\n"+
+		`data: {"jenkins_channel":"job","jenkins_event":"job_run_started","job_name":"team/app",`+"\n"+
+		`data: "jenkins_object_url":"job/team/job/app/12/","jenkins_object_id":"12"}`+"\n\n"+
+		"data: not json\n\n"+
+		`data: {"jenkins_event":"job_run_ended","job_name":"team/app","jenkins_object_url":"job/team/job/app/12/","jenkins_object_id":"12","job_run_status":"FAILURE"}`+"\n\n")
+
+	events, err := SubscribeBuildEvents(context.Background(), server.URL+"/", "token")
+	require.NoError(t, err)
+
+	var got []BuildEvent
+	for e := range events {
+		got = append(got, e)
+	}
+	assert.Equal(t, []BuildEvent{
+		{Type: "job_run_started", JobName: "team/app", URL: "job/team/job/app/12/", BuildNumber: 12},
+		{Type: "job_run_ended", JobName: "team/app", URL: "job/team/job/app/12/", BuildNumber: 12, Result: "FAILURE"},
+	}, got)
+}
+
+func TestSubscribeBuildEvents_NotAnEventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == sseConnectPath:
+			fmt.Fprint(w, `{"status":"ok","data":{}}`)
+		case r.URL.Path == sseConfigurePath:
+			fmt.Fprint(w, `{"status":"ok"}`)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html></html>")
+		}
+	}))
+	defer server.Close()
+
+	_, err := SubscribeBuildEvents(context.Background(), server.URL, "token")
+	assert.ErrorContains(t, err, "text/html")
+}
+
+func TestSubscribeBuildEvents_Unavailable(t *testing.T) {
+	// Jenkins without the plugin answers with its 404 page, or with a login
+	// or error page some proxies serve with a 200.
+	without := httptest.NewServer(http.NotFoundHandler())
+	defer without.Close()
+	_, err := SubscribeBuildEvents(context.Background(), without.URL, "token")
+	assert.ErrorIs(t, err, ErrSSEUnavailable)
+
+	html := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html></html>")
+	}))
+	defer html.Close()
+	_, err = SubscribeBuildEvents(context.Background(), html.URL, "token")
+	assert.ErrorIs(t, err, ErrSSEUnavailable)
+}
+
+func TestBuildEvent_ForJob(t *testing.T) {
+	event := BuildEvent{URL: "job/team/job/app/12/"}
+	assert.True(t, event.ForJob("https://jenkins.example.com/job/team/job/app/12"))
+	assert.True(t, event.ForJob("https://jenkins.example.com/job/team/job/app/lastBuild"))
+	assert.True(t, event.ForJob("https://jenkins.example.com/job/team/job/app"))
+	assert.False(t, event.ForJob("https://jenkins.example.com/job/team/job/application/12"))
+	assert.False(t, event.ForJob("https://jenkins.example.com/job/other/12"))
+
+	// Jenkins served under a context path.
+	assert.True(t, BuildEvent{URL: "job/app/3/"}.ForJob("https://example.com/jenkins/job/app/3/"))
+}
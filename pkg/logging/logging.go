@@ -1,20 +1,39 @@
 package logging
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"jenkins-monitor/pkg/paths"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
 func GetLogFilePath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := paths.LogDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".jw", "jenkins_monitor.log"), nil
+	return filepath.Join(dir, "jenkins_monitor.log"), nil
 }
 
-func SetupLogger() (*log.Logger, error) {
+// SetupLogger opens the daemon log file and returns a logger writing to it in
+// the given format (FormatText or FormatJSON).
+func SetupLogger(format string) (*slog.Logger, error) {
+	if format != FormatText && format != FormatJSON {
+		return nil, fmt.Errorf("unknown log format %q (want %s or %s)", format, FormatText, FormatJSON)
+	}
+
 	path, err := GetLogFilePath()
 	if err != nil {
 		return nil, err
@@ -29,5 +48,63 @@ func SetupLogger() (*log.Logger, error) {
 		return nil, err
 	}
 
-	return log.New(file, "", log.LstdFlags), nil
+	if format == FormatJSON {
+		return JSONLogger(file), nil
+	}
+	return TextLogger(file), nil
+}
+
+// JSONLogger returns a logger emitting one JSON object per line, e.g.
+// {"time":"…","level":"INFO","msg":"…","job":"…"}.
+func JSONLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// TextLogger returns a logger in the classic "2006/01/02 15:04:05 message"
+// format, with any attributes appended as key=value pairs.
+func TextLogger(w io.Writer) *slog.Logger {
+	return slog.New(&textHandler{w: w, mu: &sync.Mutex{}})
+}
+
+type textHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	attrs []slog.Attr
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	b.WriteString(t.Format("2006/01/02 15:04:05 "))
+	b.WriteString(r.Message)
+
+	writeAttr := func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &textHandler{w: h.w, mu: h.mu, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h *textHandler) WithGroup(string) slog.Handler {
+	return h
 }
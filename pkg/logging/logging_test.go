@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogger_Parseable(t *testing.T) {
+	var buf bytes.Buffer
+	logger := JSONLogger(&buf).With("job", "https://jenkins/job/test/1")
+
+	logger.Info("Started monitoring: test/1")
+	logger.Error("Error getting status", "err", "timeout")
+
+	scanner := bufio.NewScanner(&buf)
+	var entries []map[string]any
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "line should be valid JSON: %s", scanner.Text())
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "INFO", entries[0]["level"])
+	assert.Equal(t, "Started monitoring: test/1", entries[0]["msg"])
+	assert.Equal(t, "https://jenkins/job/test/1", entries[0]["job"])
+	assert.Contains(t, entries[0], "time")
+
+	assert.Equal(t, "ERROR", entries[1]["level"])
+	assert.Equal(t, "https://jenkins/job/test/1", entries[1]["job"])
+}
+
+func TestTextLogger_Format(t *testing.T) {
+	var buf bytes.Buffer
+	TextLogger(&buf).With("job", "https://jenkins/job/test/1").Info("Started monitoring: test/1")
+
+	assert.Regexp(t,
+		regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} Started monitoring: test/1 job=https://jenkins/job/test/1\n$`),
+		buf.String())
+}
+
+func TestSetupLogger_UnknownFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	_, err := SetupLogger("xml")
+	assert.ErrorContains(t, err, "unknown log format")
+}
@@ -0,0 +1,114 @@
+// Package metrics exposes daemon health as Prometheus metrics.
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the daemon's collectors. All recording methods are safe to
+// call on a nil *Metrics, so callers need not check whether metrics are enabled.
+type Metrics struct {
+	registry          *prometheus.Registry
+	buildsCompleted   *prometheus.CounterVec
+	notificationsSent *prometheus.CounterVec
+	pollErrors        prometheus.Counter
+	activeJobs        prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		buildsCompleted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jw_builds_completed_total",
+			Help: "Builds seen finishing, by Jenkins result.",
+		}, []string{"result"}),
+		notificationsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jw_notifications_sent_total",
+			Help: "Notifications sent successfully, by type.",
+		}, []string{"type"}),
+		pollErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "jw_poll_errors_total",
+			Help: "Failed job status polls.",
+		}),
+		activeJobs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "jw_active_jobs",
+			Help: "Jobs currently being monitored.",
+		}),
+	}
+	m.registry.MustRegister(m.buildsCompleted, m.notificationsSent, m.pollErrors, m.activeJobs)
+
+	// Export the common results from the start so they show up before the
+	// first build finishes.
+	m.buildsCompleted.WithLabelValues("SUCCESS")
+	m.buildsCompleted.WithLabelValues("FAILURE")
+	return m
+}
+
+func (m *Metrics) BuildCompleted(result string) {
+	if m != nil {
+		m.buildsCompleted.WithLabelValues(result).Inc()
+	}
+}
+
+func (m *Metrics) NotificationSent(kind string) {
+	if m != nil {
+		m.notificationsSent.WithLabelValues(kind).Inc()
+	}
+}
+
+func (m *Metrics) PollError() {
+	if m != nil {
+		m.pollErrors.Inc()
+	}
+}
+
+func (m *Metrics) SetActiveJobs(n int) {
+	if m != nil {
+		m.activeJobs.Set(float64(n))
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Server is a running metrics HTTP server.
+type Server struct {
+	srv      *http.Server
+	listener net.Listener
+}
+
+// Serve starts an HTTP server on addr exposing /metrics. Listening happens
+// before Serve returns, so a busy port is reported immediately.
+func (m *Metrics) Serve(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	s := &Server{
+		srv:      &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		listener: ln,
+	}
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops the server, waiting for in-flight scrapes until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
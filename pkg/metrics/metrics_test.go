@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe_ExposesMetrics(t *testing.T) {
+	m := New()
+	m.BuildCompleted("SUCCESS")
+	m.NotificationSent("finished")
+	m.PollError()
+	m.SetActiveJobs(3)
+
+	srv, err := m.Serve("127.0.0.1:0")
+	require.NoError(t, err)
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	text := string(body)
+	assert.Contains(t, text, `jw_builds_completed_total{result="SUCCESS"} 1`)
+	assert.Contains(t, text, `jw_builds_completed_total{result="FAILURE"} 0`)
+	assert.Contains(t, text, `jw_notifications_sent_total{type="finished"} 1`)
+	assert.Contains(t, text, "jw_poll_errors_total 1")
+	assert.Contains(t, text, "jw_active_jobs 3")
+}
+
+func TestServe_Shutdown(t *testing.T) {
+	srv, err := New().Serve("127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, srv.Shutdown(context.Background()))
+
+	_, err = http.Get("http://" + srv.Addr() + "/metrics")
+	assert.Error(t, err)
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.BuildCompleted("SUCCESS")
+		m.NotificationSent("finished")
+		m.PollError()
+		m.SetActiveJobs(1)
+	})
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+
+	"jenkins-monitor/pkg/jenkins"
+)
+
+// subscribeBuildEvents is jenkins.SubscribeBuildEvents, replaceable in tests.
+var subscribeBuildEvents = jenkins.SubscribeBuildEvents
+
+// eventBuffer is how many events a job may fall behind by before further
+// ones are dropped for it. Each event only triggers a check, so one queued
+// event is as good as several.
+const eventBuffer = 16
+
+// buildEvents is one job's share of its Jenkins root's event stream.
+type buildEvents struct {
+	// C is closed when the stream ends or could not be set up.
+	C <-chan jenkins.BuildEvent
+	// Connected is closed once the stream is up.
+	Connected <-chan struct{}
+	// Err is why the stream could not be set up, valid once C is closed.
+	Err error
+
+	c         chan jenkins.BuildEvent
+	connected chan struct{}
+}
+
+// eventHub fans one sse-gateway subscription out to every job monitored on
+// a Jenkins root.
+type eventHub struct {
+	subs      map[*buildEvents]struct{}
+	connected bool
+	cancel    context.CancelFunc
+}
+
+var (
+	hubsMu sync.Mutex
+	hubs   = make(map[string]*eventHub)
+)
+
+// watchBuildEvents subscribes to the build events of jobURL's Jenkins root,
+// connecting in the background if no other job is subscribed yet. The
+// returned function unsubscribes; the last job to leave closes the stream.
+func watchBuildEvents(jobURL, token string) (*buildEvents, func()) {
+	root := jenkins.RootURL(jobURL)
+	key := root + "\x00" + token
+	sub := &buildEvents{c: make(chan jenkins.BuildEvent, eventBuffer), connected: make(chan struct{})}
+	sub.C, sub.Connected = sub.c, sub.connected
+
+	hubsMu.Lock()
+	hub, ok := hubs[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		hub = &eventHub{subs: make(map[*buildEvents]struct{}), cancel: cancel}
+		hubs[key] = hub
+		go hub.run(ctx, key, root, token)
+	}
+	hub.subs[sub] = struct{}{}
+	if hub.connected {
+		close(sub.connected)
+	}
+	hubsMu.Unlock()
+
+	return sub, func() {
+		hubsMu.Lock()
+		defer hubsMu.Unlock()
+		if _, ok := hub.subs[sub]; !ok {
+			return
+		}
+		delete(hub.subs, sub)
+		if len(hub.subs) == 0 {
+			hub.cancel()
+			if hubs[key] == hub {
+				delete(hubs, key)
+			}
+		}
+	}
+}
+
+func (h *eventHub) run(ctx context.Context, key, root, token string) {
+	events, err := subscribeBuildEvents(ctx, root, token)
+	if err == nil {
+		hubsMu.Lock()
+		h.connected = true
+		for sub := range h.subs {
+			close(sub.connected)
+		}
+		hubsMu.Unlock()
+
+		for event := range events {
+			hubsMu.Lock()
+			for sub := range h.subs {
+				select {
+				case sub.c <- event:
+				default:
+				}
+			}
+			hubsMu.Unlock()
+		}
+	}
+
+	hubsMu.Lock()
+	defer hubsMu.Unlock()
+	for sub := range h.subs {
+		sub.Err = err
+		close(sub.c)
+	}
+	h.subs = nil
+	h.cancel()
+	if hubs[key] == h {
+		delete(hubs, key)
+	}
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+
+	"jenkins-monitor/pkg/jenkins"
+
+	"golang.org/x/time/rate"
+)
+
+// jobClient makes the Jenkins requests for one monitored job, waiting on its
+// host's rate limiter before each one. A nil limiter does not limit.
+type jobClient struct {
+	ctx     context.Context
+	url     string
+	token   string
+	limiter *rate.Limiter
+	cache   *jenkins.ResponseCache
+}
+
+func (c *jobClient) wait() error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(c.ctx)
+}
+
+func (c *jobClient) status() (*jenkins.JobStatus, int, error) {
+	if err := c.wait(); err != nil {
+		return nil, 0, err
+	}
+	return jenkins.GetJobStatusCached(c.ctx, c.url, c.token, c.cache)
+}
+
+func (c *jobClient) parameters() (map[string]string, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return jenkins.GetBuildParametersCtx(c.ctx, c.url, c.token)
+}
+
+func (c *jobClient) testSummary() (*jenkins.TestSummary, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return jenkins.GetTestSummaryCtx(c.ctx, c.url, c.token)
+}
+
+func (c *jobClient) changes() ([]jenkins.ChangeEntry, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return jenkins.GetSCMChangesCtx(c.ctx, c.url, c.token)
+}
+
+func (c *jobClient) stages() ([]jenkins.StageStatus, error) {
+	if err := c.wait(); err != nil {
+		return nil, err
+	}
+	return jenkins.GetPipelineStagesCtx(c.ctx, c.url, c.token)
+}
@@ -1,106 +1,425 @@
 package monitor
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"jenkins-monitor/pkg/backoff"
+	"jenkins-monitor/pkg/circuit"
 	"jenkins-monitor/pkg/jenkins"
+
+	"golang.org/x/time/rate"
 )
 
-const pollingInterval = 30 * time.Second
+// MinPollInterval is the shortest interval a job may be polled at, so a
+// typo such as 500ms cannot flood Jenkins with requests.
+const MinPollInterval = 5 * time.Second
+
+const (
+	pollingInterval = 30 * time.Second
+	// pollIntervalEnvVar overrides pollingInterval for jobs without their own
+	// interval.
+	pollIntervalEnvVar = "JW_POLL_INTERVAL"
+	// pushPollInterval is the slowest a job is polled while its Jenkins host
+	// pushes build events, in case one of them is missed.
+	pushPollInterval = 5 * time.Minute
+)
 
 // EventKind describes what happened during a monitoring check.
 type EventKind int
 
 const (
-	EventStatusChecked EventKind = iota // routine status update
-	EventFinished                       // job completed (SUCCESS/FAILURE/ABORTED)
-	EventNotFound                       // job returned 404
-	EventUnauthorized                   // job returned 401
-	EventClientError                    // other non-transient 4xx
-	EventDNSError                       // DNS resolution failed (invalid host)
-	EventError                          // transient error polling
+	EventStatusChecked    EventKind = iota // routine status update
+	EventFinished                          // job completed (SUCCESS/FAILURE/ABORTED)
+	EventNotFound                          // job returned 404
+	EventUnauthorized                      // job returned 401
+	EventClientError                       // other non-transient 4xx
+	EventDNSError                          // DNS resolution failed (invalid host)
+	EventError                             // transient error polling
+	EventDurationExceeded                  // build still running past its maximum duration
+	EventTTLExpired                        // job monitored longer than its TTL
+	EventParameters                        // build parameters fetched once the build is seen running
+	EventCircuitOpen                       // the job's Jenkins host kept failing; polls to it are paused
+)
+
+var eventKindNames = [...]string{
+	EventStatusChecked:    "status_checked",
+	EventFinished:         "finished",
+	EventNotFound:         "not_found",
+	EventUnauthorized:     "unauthorized",
+	EventClientError:      "client_error",
+	EventDNSError:         "dns_error",
+	EventError:            "error",
+	EventDurationExceeded: "duration_exceeded",
+	EventTTLExpired:       "ttl_expired",
+	EventParameters:       "parameters",
+	EventCircuitOpen:      "circuit_open",
+}
+
+func (k EventKind) String() string {
+	if k >= 0 && int(k) < len(eventKindNames) {
+		return eventKindNames[k]
+	}
+	return fmt.Sprintf("EventKind(%d)", int(k))
+}
+
+// now is time.Now, replaceable in tests.
+var now = time.Now
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuit.Breaker)
 )
 
+// breakerFor returns the circuit breaker shared by every job on jobURL's
+// Jenkins host.
+func breakerFor(jobURL string) *circuit.Breaker {
+	host := jobHost(jobURL)
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[host]
+	if !ok {
+		b = circuit.New()
+		breakers[host] = b
+	}
+	return b
+}
+
+// DurationAlert configures the "build taking too long" alert: it fires once
+// when a build is still running more than Max after Since. A zero Max
+// disables it.
+type DurationAlert struct {
+	Since time.Time
+	Max   time.Duration
+}
+
+// Recurrence configures a recurring job, which keeps being monitored after
+// its build finishes. LastReported is when Jenkins started the build last
+// reported finished; that build is not reported again.
+type Recurrence struct {
+	Enabled      bool
+	LastReported time.Time
+}
+
 // JobEvent is emitted by MonitorJob to report status changes.
 type JobEvent struct {
-	JobURL  string
-	JobName string
-	Kind    EventKind
-	Result  string // Jenkins result (SUCCESS, FAILURE, ABORTED) — set on EventFinished
-	Failed  bool   // whether the last check failed (for config tracking)
-	Error   error  // set on EventError/EventNotFound
+	JobURL   string
+	JobName  string
+	Kind     EventKind
+	Result   string        // Jenkins result (SUCCESS, FAILURE, ABORTED) — set on EventFinished
+	Duration time.Duration // build duration on EventFinished; elapsed time on EventDurationExceeded; TTL on EventTTLExpired; cool-down on EventCircuitOpen
+	Failed   bool          // whether the last check failed (for config tracking)
+	// BuildStarted is when Jenkins started the build, on EventStatusChecked
+	// and EventFinished.
+	BuildStarted time.Time
+	// BuildNumber is the Jenkins build number on EventStatusChecked and
+	// EventFinished, if Jenkins reported one.
+	BuildNumber int
+	Error       error // set on EventError/EventNotFound
+
+	Parameters map[string]string     // build parameters on EventParameters
+	Tests      *jenkins.TestSummary  // test results on EventFinished, if the build has a test report
+	Changes    []jenkins.ChangeEntry // SCM changes in the build on EventFinished
+	// FailedStages names the pipeline stages that failed, on EventFinished
+	// for a failed pipeline build.
+	FailedStages []string
 }
 
-// MonitorJob polls a Jenkins job for its status and emits events on the provided channel.
-func MonitorJob(jobURL, token string, logger *log.Logger, events chan<- JobEvent, pollInterval time.Duration, stop <-chan struct{}) {
-	if pollInterval <= 0 {
-		pollInterval = pollingInterval
+// PollIntervalFromEnv reads the daemon default poll interval from
+// JW_POLL_INTERVAL, e.g. "10s" or "2m". It returns 0, meaning the package
+// default, if the variable is unset, and reports an invalid value alongside 0.
+func PollIntervalFromEnv() (time.Duration, error) {
+	v := os.Getenv(pollIntervalEnvVar)
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < MinPollInterval {
+		return 0, fmt.Errorf("invalid %s %q: want a duration of at least %s such as 10s or 2m", pollIntervalEnvVar, v, MinPollInterval)
+	}
+	return d, nil
+}
+
+// ResolvePollInterval picks the interval a job should be polled at. A job's own
+// interval wins, then the daemon default (JW_POLL_INTERVAL), then the package
+// default of 30s.
+func ResolvePollInterval(jobInterval, daemonDefault time.Duration) time.Duration {
+	if jobInterval > 0 {
+		return jobInterval
 	}
+	if daemonDefault > 0 {
+		return daemonDefault
+	}
+	return pollingInterval
+}
+
+// recurringBuilds tracks a recurring job, which keeps being polled after its
+// build finishes so the next build is reported too.
+type recurringBuilds struct {
+	reported int64 // Jenkins timestamp of the last build reported finished
+	idle     bool  // the last check found no build newer than reported
+}
+
+// MonitorJob polls a Jenkins job for its status and emits events on the provided channel.
+// Every request waits on limiter first; a nil limiter does not limit. A
+// recurring job is not stopped when its build finishes: monitoring continues
+// and each later build's completion is reported once. When the Jenkins host
+// has the sse-gateway plugin, the job is checked as soon as an event about it
+// arrives and otherwise only polled every pushPollInterval; if the event
+// stream ends, regular polling resumes.
+func MonitorJob(jobURL, token string, logger *slog.Logger, events chan<- JobEvent, pollInterval time.Duration, alert DurationAlert, recurrence Recurrence, limiter *rate.Limiter, stop <-chan struct{}) {
+	pollInterval = ResolvePollInterval(pollInterval, 0)
 
 	jobName := strings.Split(jobURL, "/job/")
 	jobNameSafe := jobName[len(jobName)-1]
 
-	logger.Printf("Started monitoring: %s", jobNameSafe)
-	defer logger.Printf("Stopped monitoring: %s", jobNameSafe)
+	logger = logger.With("job", jobURL)
+	logger.Info("Started monitoring: " + jobNameSafe)
+	defer logger.Info("Stopped monitoring: " + jobNameSafe)
 
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
-	// Perform the first check immediately.
-	if checkJobStatus(jobURL, token, jobNameSafe, logger, events) {
-		return
+	client := &jobClient{ctx: ctx, url: jobURL, token: token, limiter: limiter, cache: jenkins.NewResponseCache()}
+	breaker := breakerFor(jobURL)
+	retry := backoff.NewExponential()
+	timer := time.NewTimer(0) // first check runs immediately
+	defer timer.Stop()
+
+	sub, unsubscribe := watchBuildEvents(jobURL, token)
+	defer unsubscribe()
+	push, connected := sub.C, sub.Connected
+	pushing := false
+	interval := func() time.Duration {
+		if pushing {
+			return max(pollInterval, pushPollInterval)
+		}
+		return pollInterval
+	}
+
+	alreadyAlertedDuration := false
+	fetchedParameters := false
+	var builds *recurringBuilds
+	if recurrence.Enabled {
+		builds = &recurringBuilds{}
+		if !recurrence.LastReported.IsZero() {
+			builds.reported = recurrence.LastReported.UnixMilli()
+		}
 	}
 
 	for {
 		select {
 		case <-stop:
 			return
-		case <-ticker.C:
-			if checkJobStatus(jobURL, token, jobNameSafe, logger, events) {
+		case <-connected:
+			connected = nil
+			pushing = true
+			logger.Info("Receiving build events for " + jobNameSafe)
+		case event, ok := <-push:
+			if !ok {
+				push, connected = nil, nil
+				if sub.Err != nil {
+					logger.Info(fmt.Sprintf("Build events unavailable for %s, polling: %v", jobNameSafe, sub.Err))
+				} else {
+					logger.Info("Build event stream closed, polling " + jobNameSafe)
+				}
+				if pushing {
+					pushing = false
+					timer.Reset(pollInterval)
+				}
+			} else if event.ForJob(jobURL) {
+				timer.Reset(0)
+			}
+		case <-timer.C:
+			shouldStop, transient := checkWithBreaker(breaker, jobURL, jobNameSafe, logger, events, func() (bool, bool) {
+				return checkJobStatus(client, jobNameSafe, logger, events, builds)
+			})
+			if shouldStop {
 				return
 			}
+			if !transient && builds != nil && builds.idle {
+				// Between builds: start afresh with the next one.
+				fetchedParameters = false
+				alreadyAlertedDuration = false
+				alert.Since = now()
+				timer.Reset(interval())
+				continue
+			}
+			if !transient && !fetchedParameters {
+				fetchedParameters = true
+				fetchBuildParameters(client, jobNameSafe, logger, events)
+			}
+			if !transient && !alreadyAlertedDuration && alert.Max > 0 {
+				if elapsed := now().Sub(alert.Since); elapsed > alert.Max {
+					alreadyAlertedDuration = true
+					logger.Warn(fmt.Sprintf("Build %s still running after %s", jobNameSafe, elapsed.Round(time.Second)))
+					events <- JobEvent{
+						JobURL:   jobURL,
+						JobName:  jobNameSafe,
+						Kind:     EventDurationExceeded,
+						Duration: elapsed,
+					}
+				}
+			}
+			delay := interval()
+			if transient {
+				delay = retry.Next()
+				logger.Info(fmt.Sprintf("Retrying %s in %s", jobNameSafe, delay.Round(time.Second)))
+			} else {
+				retry.Reset()
+			}
+			timer.Reset(delay)
+		}
+	}
+}
+
+// checkWithBreaker runs check unless breaker refuses it, and feeds the
+// outcome back: transient errors count as failures of the Jenkins host. The
+// job whose failure opens the breaker emits EventCircuitOpen.
+func checkWithBreaker(breaker *circuit.Breaker, jobURL, jobNameSafe string, logger *slog.Logger, events chan<- JobEvent, check func() (shouldStop, transient bool)) (shouldStop, transient bool) {
+	if err := breaker.Allow(); err != nil {
+		logger.Info(fmt.Sprintf("Skipping check of %s: %v", jobNameSafe, err))
+		return false, true
+	}
+	shouldStop, transient = check()
+	if !transient {
+		breaker.Success()
+		return shouldStop, transient
+	}
+	if breaker.Failure() {
+		logger.Warn(fmt.Sprintf("Jenkins host of %s keeps failing; pausing checks for %s", jobNameSafe, breaker.Cooldown))
+		events <- JobEvent{
+			JobURL:   jobURL,
+			JobName:  jobNameSafe,
+			Kind:     EventCircuitOpen,
+			Duration: breaker.Cooldown,
 		}
 	}
+	return shouldStop, transient
+}
+
+// fetchBuildParameters emits the build's parameters, if it has any. Failing
+// to fetch them is not worth retrying, so the error is only logged.
+func fetchBuildParameters(client *jobClient, jobNameSafe string, logger *slog.Logger, events chan<- JobEvent) {
+	params, err := client.parameters()
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Error getting parameters for %s: %v", jobNameSafe, err))
+		return
+	}
+	if len(params) == 0 {
+		return
+	}
+	events <- JobEvent{
+		JobURL:     client.url,
+		JobName:    jobNameSafe,
+		Kind:       EventParameters,
+		Parameters: params,
+	}
 }
 
-// checkJobStatus checks a Jenkins job's status and returns true if monitoring should stop.
-func checkJobStatus(jobURL, token, jobNameSafe string, logger *log.Logger, events chan<- JobEvent) (shouldStop bool) {
-	status, statusCode, err := jenkins.GetJobStatus(jobURL, token)
+// checkJobStatus checks a Jenkins job's status and reports whether monitoring
+// should stop and whether the check hit a transient error worth backing off on.
+// builds is nil unless the job is recurring.
+func checkJobStatus(client *jobClient, jobNameSafe string, logger *slog.Logger, events chan<- JobEvent, builds *recurringBuilds) (shouldStop, transient bool) {
+	jobURL := client.url
+	status, statusCode, err := client.status()
+	if errors.Is(err, context.Canceled) {
+		return true, false
+	}
 	if err != nil {
-		return handleJobStatusError(err, statusCode, jobURL, jobNameSafe, logger, events)
+		shouldStop = handleJobStatusError(err, statusCode, jobURL, jobNameSafe, logger, events)
+		return shouldStop, !shouldStop
+	}
+
+	logger.Info(fmt.Sprintf("Received status for %s: Building=%v, Result=%s", jobNameSafe, status.Building, status.Result))
+
+	if builds != nil {
+		builds.idle = !status.Building && status.Timestamp == builds.reported
+		if builds.idle {
+			return false, false
+		}
 	}
 
-	logger.Printf("Received status for %s: Building=%v, Result=%s", jobNameSafe, status.Building, status.Result)
+	var started time.Time
+	if status.Timestamp > 0 {
+		started = time.UnixMilli(status.Timestamp)
+	}
 
 	if !status.Building {
-		logger.Printf("Build finished: %s - Status: %s", jobNameSafe, status.Result)
+		logger.Info(fmt.Sprintf("Build finished: %s - Status: %s", jobNameSafe, status.Result))
+		var tests *jenkins.TestSummary
+		if status.Result == "SUCCESS" || status.Result == "FAILURE" {
+			tests, err = client.testSummary()
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Error getting test results for %s: %v", jobNameSafe, err))
+			}
+		}
+		changes, err := client.changes()
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Error getting changes for %s: %v", jobNameSafe, err))
+		}
+		var failedStages []string
+		if status.Result == "FAILURE" {
+			stages, err := client.stages()
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Error getting pipeline stages for %s: %v", jobNameSafe, err))
+			}
+			for _, stage := range stages {
+				if stage.Failed() {
+					failedStages = append(failedStages, stage.Name)
+				}
+			}
+		}
 		events <- JobEvent{
-			JobURL:  jobURL,
-			JobName: jobNameSafe,
-			Kind:    EventFinished,
-			Result:  status.Result,
-			Failed:  false,
+			JobURL:       jobURL,
+			JobName:      jobNameSafe,
+			Kind:         EventFinished,
+			Result:       status.Result,
+			Duration:     status.BuildDuration(),
+			Failed:       false,
+			BuildStarted: started,
+			BuildNumber:  status.Number,
+			Tests:        tests,
+			Changes:      changes,
+			FailedStages: failedStages,
 		}
-		return true
+		if builds != nil {
+			builds.reported = status.Timestamp
+			builds.idle = true
+			return false, false
+		}
+		return true, false
 	}
 
 	events <- JobEvent{
-		JobURL:  jobURL,
-		JobName: jobNameSafe,
-		Kind:    EventStatusChecked,
-		Failed:  status.Result == "FAILURE",
+		JobURL:       jobURL,
+		JobName:      jobNameSafe,
+		Kind:         EventStatusChecked,
+		Failed:       status.Result == "FAILURE",
+		BuildStarted: started,
+		BuildNumber:  status.Number,
 	}
-	return false
+	return false, false
 }
 
 // handleJobStatusError handles errors from getting job status and returns true if monitoring should stop.
-func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string, logger *log.Logger, events chan<- JobEvent) (shouldStop bool) {
+func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string, logger *slog.Logger, events chan<- JobEvent) (shouldStop bool) {
 	if statusCode == 404 {
-		logger.Printf("Job '%s' not found (404). Removing.", jobNameSafe)
+		logger.Warn(fmt.Sprintf("Job '%s' not found (404). Removing.", jobNameSafe))
 		events <- JobEvent{
 			JobURL:  jobURL,
 			JobName: jobNameSafe,
@@ -112,7 +431,7 @@ func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string,
 	}
 
 	if statusCode == 401 || statusCode == 403 {
-		logger.Printf("Unauthorized for job '%s' (%d). Removing.", jobNameSafe, statusCode)
+		logger.Warn(fmt.Sprintf("Unauthorized for job '%s' (%d). Removing.", jobNameSafe, statusCode))
 		events <- JobEvent{
 			JobURL:  jobURL,
 			JobName: jobNameSafe,
@@ -124,7 +443,7 @@ func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string,
 	}
 
 	if statusCode >= 400 && statusCode < 500 && statusCode != 429 {
-		logger.Printf("Client error for job '%s' (%d). Removing.", jobNameSafe, statusCode)
+		logger.Warn(fmt.Sprintf("Client error for job '%s' (%d). Removing.", jobNameSafe, statusCode))
 		events <- JobEvent{
 			JobURL:  jobURL,
 			JobName: jobNameSafe,
@@ -138,7 +457,7 @@ func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string,
 	// Non-JSON response means the URL is not a Jenkins endpoint — no point retrying.
 	var ctErr *jenkins.ContentTypeError
 	if errors.As(err, &ctErr) {
-		logger.Printf("Non-Jenkins URL for job '%s': %v. Removing.", jobNameSafe, err)
+		logger.Warn(fmt.Sprintf("Non-Jenkins URL for job '%s': %v. Removing.", jobNameSafe, err))
 		events <- JobEvent{
 			JobURL:  jobURL,
 			JobName: jobNameSafe,
@@ -152,7 +471,7 @@ func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string,
 	// DNS resolution failure means the host doesn't exist — no point retrying.
 	var dnsErr *net.DNSError
 	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
-		logger.Printf("DNS lookup failed for job '%s': %v. Removing.", jobNameSafe, err)
+		logger.Warn(fmt.Sprintf("DNS lookup failed for job '%s': %v. Removing.", jobNameSafe, err))
 		events <- JobEvent{
 			JobURL:  jobURL,
 			JobName: jobNameSafe,
@@ -163,7 +482,7 @@ func handleJobStatusError(err error, statusCode int, jobURL, jobNameSafe string,
 		return true
 	}
 
-	logger.Printf("Error getting status for %s: %v. Will retry.", jobNameSafe, err)
+	logger.Warn(fmt.Sprintf("Error getting status for %s: %v. Will retry with backoff.", jobNameSafe, err))
 	events <- JobEvent{
 		JobURL:  jobURL,
 		JobName: jobNameSafe,
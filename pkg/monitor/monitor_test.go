@@ -0,0 +1,529 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/circuit"
+	"jenkins-monitor/pkg/jenkins"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePollInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		jobInterval   time.Duration
+		daemonDefault time.Duration
+		want          time.Duration
+	}{
+		{
+			name:          "job interval wins",
+			jobInterval:   10 * time.Second,
+			daemonDefault: time.Minute,
+			want:          10 * time.Second,
+		},
+		{
+			name:          "zero job interval falls back to daemon default",
+			jobInterval:   0,
+			daemonDefault: time.Minute,
+			want:          time.Minute,
+		},
+		{
+			name:          "negative job interval falls back to daemon default",
+			jobInterval:   -5 * time.Second,
+			daemonDefault: time.Minute,
+			want:          time.Minute,
+		},
+		{
+			name:          "no intervals falls back to package default",
+			jobInterval:   0,
+			daemonDefault: 0,
+			want:          pollingInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ResolvePollInterval(tt.jobInterval, tt.daemonDefault))
+		})
+	}
+}
+
+func TestPollIntervalFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "10s", want: 10 * time.Second},
+		{value: "2m", want: 2 * time.Minute},
+		{value: "10", wantErr: true},
+		{value: "-5s", wantErr: true},
+		{value: "500ms", wantErr: true},
+		{value: "5s", want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(pollIntervalEnvVar, tt.value)
+			got, err := PollIntervalFromEnv()
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, pollIntervalEnvVar)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMonitorJob_DurationAlertFiresOnce(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"building":true}`)
+	}))
+	defer server.Close()
+
+	// Every check happens ten simulated minutes after the previous one.
+	start := time.Now()
+	var mu sync.Mutex
+	clock := start
+	origNow := now
+	now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		clock = clock.Add(10 * time.Minute)
+		return clock
+	}
+	t.Cleanup(func() { now = origNow })
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	alert := DurationAlert{Since: start, Max: 25 * time.Minute}
+	go func() {
+		MonitorJob(server.URL+"/job/slow/1", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Millisecond, alert, Recurrence{}, nil, stop)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return polls.Load() >= 6 }, 5*time.Second, time.Millisecond)
+	close(stop)
+	<-done
+	close(events)
+
+	var exceeded []JobEvent
+	for e := range events {
+		if e.Kind == EventDurationExceeded {
+			exceeded = append(exceeded, e)
+		}
+	}
+	require.Len(t, exceeded, 1)
+	assert.Equal(t, 30*time.Minute, exceeded[0].Duration)
+}
+
+func TestMonitorJob_NoDurationAlertWhenDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"building":true}`)
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		MonitorJob(server.URL+"/job/slow/1", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Millisecond, DurationAlert{Since: time.Now().Add(-time.Hour)}, Recurrence{}, nil, stop)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return len(events) >= 3 }, 5*time.Second, time.Millisecond)
+	close(stop)
+	<-done
+	close(events)
+	for e := range events {
+		assert.NotEqual(t, EventDurationExceeded, e.Kind)
+	}
+}
+
+func TestMonitorJob_RecurringReportsEachBuild(t *testing.T) {
+	statuses := []string{
+		`{"building":false,"result":"SUCCESS","timestamp":1000}`,
+		`{"building":false,"result":"SUCCESS","timestamp":1000}`,
+		`{"building":true,"timestamp":2000}`,
+		`{"building":false,"result":"FAILURE","timestamp":2000}`,
+	}
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "testReport") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(r.URL.Query().Get("tree"), "building") {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		n := int(polls.Add(1)) - 1
+		fmt.Fprint(w, statuses[min(n, len(statuses)-1)])
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		MonitorJob(server.URL+"/job/nightly/lastBuild", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Millisecond, DurationAlert{}, Recurrence{Enabled: true}, nil, stop)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return polls.Load() >= 8 }, 5*time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("recurring monitor stopped after a build finished")
+	default:
+	}
+	close(stop)
+	<-done
+	close(events)
+
+	var results []string
+	for e := range events {
+		if e.Kind == EventFinished {
+			results = append(results, e.Result)
+		}
+	}
+	assert.Equal(t, []string{"SUCCESS", "FAILURE"}, results, "each build is reported once")
+}
+
+func TestMonitorJob_RecurringSkipsBuildReportedBeforeRestart(t *testing.T) {
+	statuses := []string{
+		`{"building":false,"result":"SUCCESS","timestamp":1000}`,
+		`{"building":true,"timestamp":2000}`,
+		`{"building":false,"result":"FAILURE","timestamp":2000}`,
+	}
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "testReport") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(r.URL.Query().Get("tree"), "building") {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		n := int(polls.Add(1)) - 1
+		fmt.Fprint(w, statuses[min(n, len(statuses)-1)])
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	recurrence := Recurrence{Enabled: true, LastReported: time.UnixMilli(1000)}
+	go func() {
+		MonitorJob(server.URL+"/job/nightly/lastBuild", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Millisecond, DurationAlert{}, recurrence, nil, stop)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return polls.Load() >= 5 }, 5*time.Second, time.Millisecond)
+	close(stop)
+	<-done
+	close(events)
+
+	var finished []JobEvent
+	for e := range events {
+		if e.Kind == EventFinished {
+			finished = append(finished, e)
+		}
+	}
+	require.Len(t, finished, 1, "the build reported before the restart is not reported again")
+	assert.Equal(t, "FAILURE", finished[0].Result)
+	assert.True(t, time.UnixMilli(2000).Equal(finished[0].BuildStarted))
+}
+
+func TestCheckJobStatus_FinishedIncludesTestSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     string
+		testReport bool
+		want       *jenkins.TestSummary
+	}{
+		{name: "with test report", result: "FAILURE", testReport: true, want: &jenkins.TestSummary{Total: 46, Failed: 3, Skipped: 1}},
+		{name: "without test report", result: "SUCCESS"},
+		{name: "aborted builds skip the lookup", result: "ABORTED", testReport: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/job/app/1/api/json":
+					fmt.Fprintf(w, `{"building":false,"result":%q}`, tt.result)
+				case "/job/app/1/testReport/api/json":
+					if !tt.testReport {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, `{"failCount":3,"skipCount":1,"totalCount":46}`)
+				case "/job/app/1/wfapi/describe":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			events := make(chan JobEvent, 1)
+			stop, transient := checkJobStatus(&jobClient{ctx: context.Background(), url: server.URL + "/job/app/1", token: "token"}, "app/1", slog.New(slog.NewTextHandler(io.Discard, nil)), events, nil)
+			assert.True(t, stop)
+			assert.False(t, transient)
+
+			event := <-events
+			assert.Equal(t, EventFinished, event.Kind)
+			assert.Equal(t, tt.want, event.Tests)
+		})
+	}
+}
+
+func TestCheckJobStatus_FailedPipelineStages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/job/app/1/api/json":
+			fmt.Fprint(w, `{"building":false,"result":"FAILURE"}`)
+		case "/job/app/1/wfapi/describe":
+			fmt.Fprint(w, `{"stages":[{"name":"Build","status":"SUCCESS"},{"name":"Test","status":"FAILED"},{"name":"Lint","status":"FAILED"},{"name":"Deploy","status":"NOT_EXECUTED"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 1)
+	checkJobStatus(&jobClient{ctx: context.Background(), url: server.URL + "/job/app/1", token: "token"}, "app/1", slog.New(slog.NewTextHandler(io.Discard, nil)), events, nil)
+	event := <-events
+	assert.Equal(t, EventFinished, event.Kind)
+	assert.Equal(t, []string{"Test", "Lint"}, event.FailedStages)
+}
+
+func TestCheckJobStatus_ReportsBuildNumberAndDuration(t *testing.T) {
+	building := true
+	started := time.Now().Add(-90 * time.Second).UnixMilli()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/job/app/lastBuild/api/json" || !strings.Contains(r.URL.Query().Get("tree"), "number") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if building {
+			fmt.Fprint(w, `{"building":true,"number":123}`)
+		} else {
+			// Jenkins can report a finished build before filling in its duration.
+			fmt.Fprintf(w, `{"building":false,"result":"SUCCESS","number":123,"duration":0,"timestamp":%d}`, started)
+		}
+	}))
+	defer server.Close()
+
+	client := &jobClient{ctx: context.Background(), url: server.URL + "/job/app/lastBuild", token: "token"}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	events := make(chan JobEvent, 1)
+
+	checkJobStatus(client, "app", logger, events, nil)
+	event := <-events
+	assert.Equal(t, EventStatusChecked, event.Kind)
+	assert.Equal(t, 123, event.BuildNumber)
+
+	building = false
+	checkJobStatus(client, "app", logger, events, nil)
+	event = <-events
+	assert.Equal(t, EventFinished, event.Kind)
+	assert.Equal(t, 123, event.BuildNumber)
+	assert.InDelta(t, 90*time.Second, event.Duration, float64(5*time.Second), "duration falls back to the start timestamp")
+}
+
+func TestCheckWithBreaker_OpensOnceForHost(t *testing.T) {
+	breaker := circuit.New()
+	breaker.Threshold = 2
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	events := make(chan JobEvent, 10)
+
+	var checks int
+	failing := func() (bool, bool) {
+		checks++
+		return false, true
+	}
+
+	for _, job := range []string{"a/1", "b/1", "a/1", "b/1"} {
+		stop, transient := checkWithBreaker(breaker, "https://jenkins/job/"+job, job, logger, events, failing)
+		assert.False(t, stop)
+		assert.True(t, transient)
+	}
+	close(events)
+
+	assert.Equal(t, 2, checks, "checks are skipped once the breaker opens")
+	var opened []JobEvent
+	for e := range events {
+		opened = append(opened, e)
+	}
+	require.Len(t, opened, 1)
+	assert.Equal(t, EventCircuitOpen, opened[0].Kind)
+	assert.Equal(t, "https://jenkins/job/b/1", opened[0].JobURL)
+	assert.Equal(t, circuit.DefaultCooldown, opened[0].Duration)
+}
+
+func TestBreakerFor_SharedPerHost(t *testing.T) {
+	assert.Same(t, breakerFor("https://ci.example.com/job/a/1"), breakerFor("https://ci.example.com/job/b/2"))
+	assert.NotSame(t, breakerFor("https://ci.example.com/job/a/1"), breakerFor("https://other.example.com/job/a/1"))
+}
+
+func TestMonitorJob_StopCancelsInFlightRequest(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 10)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		MonitorJob(server.URL+"/job/hang/1", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Minute, DurationAlert{}, Recurrence{}, nil, stop)
+		close(done)
+	}()
+
+	<-started
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("MonitorJob did not return after stop")
+	}
+	close(events)
+	for e := range events {
+		assert.NotEqual(t, EventError, e.Kind, "a cancelled request is not a poll error")
+	}
+}
+
+// fakeBuildEvents replaces the sse-gateway subscription with push, counting
+// the subscriptions made.
+func fakeBuildEvents(t *testing.T, push <-chan jenkins.BuildEvent) *atomic.Int32 {
+	t.Helper()
+	var subscriptions atomic.Int32
+	orig := subscribeBuildEvents
+	subscribeBuildEvents = func(ctx context.Context, baseURL, token string) (<-chan jenkins.BuildEvent, error) {
+		subscriptions.Add(1)
+		events := make(chan jenkins.BuildEvent)
+		go func() {
+			defer close(events)
+			for {
+				select {
+				case e := <-push:
+					events <- e
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return events, nil
+	}
+	t.Cleanup(func() { subscribeBuildEvents = orig })
+	return &subscriptions
+}
+
+func TestMonitorJob_BuildEventTriggersCheck(t *testing.T) {
+	push := make(chan jenkins.BuildEvent)
+	fakeBuildEvents(t, push)
+	var finished atomic.Bool
+	checked := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/job/app/5/api/json":
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasPrefix(r.URL.Query().Get("tree"), "building") {
+				if finished.Load() {
+					fmt.Fprint(w, `{"building":false,"result":"SUCCESS","number":5}`)
+				} else {
+					fmt.Fprint(w, `{"building":true,"number":5}`)
+					checked <- struct{}{}
+				}
+				return
+			}
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	defer close(stop)
+	// With an hour between polls, only the pushed event can finish the job.
+	go MonitorJob(server.URL+"/job/app/5", "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Hour, DurationAlert{}, Recurrence{}, nil, stop)
+
+	<-checked // the first poll sees the build running
+	finished.Store(true)
+	push <- jenkins.BuildEvent{Type: "job_run_ended", URL: "job/app/5/", BuildNumber: 5, Result: "SUCCESS"}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e.Kind == EventFinished {
+				assert.Equal(t, "SUCCESS", e.Result)
+				return
+			}
+		case <-timeout:
+			t.Fatal("build event did not trigger a status check")
+		}
+	}
+}
+
+func TestMonitorJob_SharesBuildEventsPerRoot(t *testing.T) {
+	push := make(chan jenkins.BuildEvent)
+	subscriptions := fakeBuildEvents(t, push)
+	checks := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Query().Get("tree"), "building") {
+			checks <- r.URL.Path
+		}
+		fmt.Fprint(w, `{"building":true,"number":1}`)
+	}))
+	defer server.Close()
+
+	events := make(chan JobEvent, 100)
+	stop := make(chan struct{})
+	defer close(stop)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	go MonitorJob(server.URL+"/job/a/1", "token", logger, events, time.Hour, DurationAlert{}, Recurrence{}, nil, stop)
+	go MonitorJob(server.URL+"/job/b/1", "token", logger, events, time.Hour, DurationAlert{}, Recurrence{}, nil, stop)
+	<-checks
+	<-checks
+
+	// Both jobs hear an event about either of them from the one stream.
+	push <- jenkins.BuildEvent{Type: "job_run_ended", URL: "job/b/1/", BuildNumber: 1}
+	select {
+	case path := <-checks:
+		assert.Equal(t, "/job/b/1/api/json", path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("build event did not trigger a status check")
+	}
+	assert.Equal(t, int32(1), subscriptions.Load())
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitEnvVar overrides the requests per second allowed to each
+	// Jenkins host.
+	rateLimitEnvVar  = "JW_RATE_LIMIT"
+	defaultRateLimit = 2.0
+)
+
+// Limiters hands out one rate limiter per Jenkins host, so every job on the
+// same instance shares a request budget. A nil *Limiters does not limit.
+type Limiters struct {
+	limit rate.Limit
+	burst int
+
+	mu    sync.Mutex
+	hosts map[string]*rate.Limiter
+}
+
+// NewLimiters allows perSecond requests per second to each host.
+func NewLimiters(perSecond float64) *Limiters {
+	return &Limiters{
+		limit: rate.Limit(perSecond),
+		burst: max(1, int(perSecond)),
+		hosts: make(map[string]*rate.Limiter),
+	}
+}
+
+// LimitersFromEnv reads the per-host limit from JW_RATE_LIMIT, defaulting to
+// 2 requests per second. An invalid value is reported alongside limiters
+// using the default.
+func LimitersFromEnv() (*Limiters, error) {
+	v := os.Getenv(rateLimitEnvVar)
+	if v == "" {
+		return NewLimiters(defaultRateLimit), nil
+	}
+	perSecond, err := strconv.ParseFloat(v, 64)
+	if err != nil || perSecond <= 0 {
+		return NewLimiters(defaultRateLimit), fmt.Errorf("invalid %s %q: want a positive number of requests per second", rateLimitEnvVar, v)
+	}
+	return NewLimiters(perSecond), nil
+}
+
+// For returns the limiter for jobURL's host.
+func (l *Limiters) For(jobURL string) *rate.Limiter {
+	if l == nil {
+		return nil
+	}
+	host := jobHost(jobURL)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limiter, ok := l.hosts[host]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.hosts[host] = limiter
+	}
+	return limiter
+}
+
+// jobHost returns the host:port of jobURL, or jobURL itself if it does not
+// parse.
+func jobHost(jobURL string) string {
+	if u, err := url.Parse(jobURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return jobURL
+}
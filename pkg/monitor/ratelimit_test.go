@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorJob_RateLimitedPerHost(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"building":true}`)
+	}))
+	defer server.Close()
+
+	limiters := NewLimiters(4)
+	events := make(chan JobEvent, 1000)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range 10 {
+		jobURL := fmt.Sprintf("%s/job/app%d/1", server.URL, i)
+		wg.Go(func() {
+			MonitorJob(jobURL, "token", slog.New(slog.NewTextHandler(io.Discard, nil)), events, time.Millisecond, DurationAlert{}, Recurrence{}, limiters.For(jobURL), stop)
+		})
+	}
+
+	window := 500 * time.Millisecond
+	time.Sleep(window)
+	got := requests.Load()
+	close(stop)
+	wg.Wait()
+
+	// A burst of 4, then 4 per second: at most 6 in half a second, plus one
+	// for timing slack.
+	assert.Positive(t, got)
+	assert.LessOrEqual(t, got, int32(7))
+}
+
+func TestLimitersFromEnv(t *testing.T) {
+	t.Setenv(rateLimitEnvVar, "")
+	l, err := LimitersFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, float64(l.For("https://ci/job/a").Limit()))
+
+	t.Setenv(rateLimitEnvVar, "0.5")
+	l, err = LimitersFromEnv()
+	assert.NoError(t, err)
+	limiter := l.For("https://ci/job/a")
+	assert.Equal(t, 0.5, float64(limiter.Limit()))
+	assert.Equal(t, 1, limiter.Burst())
+	assert.Same(t, limiter, l.For("https://ci/job/b/3"))
+
+	t.Setenv(rateLimitEnvVar, "fast")
+	l, err = LimitersFromEnv()
+	assert.ErrorContains(t, err, "JW_RATE_LIMIT")
+	assert.Equal(t, 2.0, float64(l.For("https://ci/job/a").Limit()))
+}
+
+func TestLimiters_NilDoesNotLimit(t *testing.T) {
+	var l *Limiters
+	assert.Nil(t, l.For("https://ci/job/a"))
+}
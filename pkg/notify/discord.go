@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const discordTimeout = 10 * time.Second
+
+// Discord embed colours, as decimal RGB.
+const (
+	discordGreen  = 0x2ECC71
+	discordRed    = 0xE74C3C
+	discordYellow = 0xF1C40F
+)
+
+// DiscordNotifier posts notifications to a Discord channel webhook.
+type DiscordNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+type discordPayload struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+func (d *DiscordNotifier) Send(n Notification) error {
+	payload := discordPayload{
+		Content: n.Title,
+		Embeds: []discordEmbed{{
+			Title:       n.JobName,
+			URL:         n.URL,
+			Description: n.Message,
+			Color:       discordColor(n.Result),
+		}},
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding discord payload: %w", err)
+	}
+
+	client := d.Client
+	if client == nil {
+		client = &http.Client{Timeout: discordTimeout}
+	}
+
+	resp, err := client.Post(d.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("posting to discord: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// discordColor maps a Jenkins result to an embed colour.
+func discordColor(result string) int {
+	switch result {
+	case "SUCCESS":
+		return discordGreen
+	case "FAILURE":
+		return discordRed
+	default:
+		return discordYellow
+	}
+}
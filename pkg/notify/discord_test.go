@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordNotifier_Send(t *testing.T) {
+	tests := []struct {
+		result string
+		color  int
+	}{
+		{"SUCCESS", discordGreen},
+		{"FAILURE", discordRed},
+		{"ABORTED", discordYellow},
+		{"UNSTABLE", discordYellow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.result, func(t *testing.T) {
+			var payload discordPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			n := &DiscordNotifier{WebhookURL: server.URL}
+			err := n.Send(Notification{
+				Title:   "Jenkins Job Completed",
+				Message: "Job: my-job/42\nStatus: " + tt.result,
+				URL:     "https://jenkins/job/my-job/42",
+				JobName: "my-job/42",
+				Result:  tt.result,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "Jenkins Job Completed", payload.Content)
+			require.Len(t, payload.Embeds, 1)
+			assert.Equal(t, tt.color, payload.Embeds[0].Color)
+			assert.Equal(t, "my-job/42", payload.Embeds[0].Title)
+			assert.Equal(t, "https://jenkins/job/my-job/42", payload.Embeds[0].URL)
+		})
+	}
+}
+
+func TestDiscordNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := (&DiscordNotifier{WebhookURL: server.URL}).Send(Notification{Title: "title", Message: "Job: x"})
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+)
+
+type LinuxNotifier struct {
+	once             sync.Once
+	notifySendExists bool
+}
+
+func NewLinuxNotifier() *LinuxNotifier {
+	return &LinuxNotifier{}
+}
+
+func (l *LinuxNotifier) checkNotifier() {
+	l.once.Do(func() {
+		if _, err := lookPath("notify-send"); err != nil {
+			log.Println("notify-send not found in PATH")
+			l.notifySendExists = false
+		} else {
+			l.notifySendExists = true
+		}
+	})
+}
+
+func (l *LinuxNotifier) Send(n Notification) error {
+	l.checkNotifier()
+
+	body := n.Message
+	if n.URL != "" {
+		body = n.Message + "\n" + n.URL
+	}
+
+	var result *exec.Cmd
+	if !l.notifySendExists {
+		log.Println("Using zenity fallback (notify-send not found in PATH)")
+		result = execCommand("zenity", "--notification", "--text", n.Title+"\n"+body)
+	} else {
+		log.Println("Using notify-send")
+		result = execCommand("notify-send", "--app-name", "jw", n.Title, body)
+	}
+
+	output, err := result.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w (output: %s)", err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MultiNotifier sends every notification to all of its Notifiers in parallel.
+// A failing notifier does not prevent the others from being called.
+type MultiNotifier struct {
+	Notifiers []Notifier
+}
+
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{Notifiers: notifiers}
+}
+
+func (m *MultiNotifier) Send(n Notification) error {
+	errs := make([]error, len(m.Notifiers))
+
+	var wg sync.WaitGroup
+	for i, notifier := range m.Notifiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := notifier.Send(n); err != nil {
+				errs[i] = fmt.Errorf("%T: %w", notifier, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
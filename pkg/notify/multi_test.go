@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingNotifier struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (c *countingNotifier) Send(n Notification) error {
+	c.calls.Add(1)
+	return c.err
+}
+
+type failingNotifier struct{ countingNotifier }
+
+func TestMultiNotifier_CallsAll(t *testing.T) {
+	a, b := &countingNotifier{}, &countingNotifier{}
+
+	err := NewMultiNotifier(a, b).Send(Notification{Title: "title", Message: "message"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, a.calls.Load())
+	assert.EqualValues(t, 1, b.calls.Load())
+}
+
+func TestMultiNotifier_ErrorsDoNotSuppressOthers(t *testing.T) {
+	ok := &countingNotifier{}
+	bad := &countingNotifier{err: errors.New("desktop down")}
+	worse := &failingNotifier{countingNotifier{err: errors.New("webhook down")}}
+
+	err := NewMultiNotifier(bad, ok, worse).Send(Notification{Title: "title", Message: "message"})
+	require.Error(t, err)
+
+	assert.EqualValues(t, 1, ok.calls.Load())
+	assert.EqualValues(t, 1, bad.calls.Load())
+	assert.EqualValues(t, 1, worse.calls.Load())
+
+	assert.Contains(t, err.Error(), "*notify.countingNotifier: desktop down")
+	assert.Contains(t, err.Error(), "*notify.failingNotifier: webhook down")
+}
@@ -4,12 +4,40 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 )
 
+// execCommand and lookPath wrap the os/exec functions for testability.
+var (
+	execCommand = exec.Command
+	lookPath    = exec.LookPath
+)
+
+// Notification is one message to deliver, along with what it is about so
+// notifiers that can style it need not parse the rendered text.
+type Notification struct {
+	Title   string
+	Message string
+	// URL is the job or build the notification is about.
+	URL     string
+	JobName string
+	// Result is the Jenkins result (SUCCESS, FAILURE, ...) of a finished
+	// build, or "".
+	Result string
+}
+
 type Notifier interface {
-	Send(title, message, url string) error
+	Send(n Notification) error
+}
+
+// New returns the desktop Notifier for the current platform.
+func New() Notifier {
+	if runtime.GOOS == "linux" {
+		return NewLinuxNotifier()
+	}
+	return &MacNotifier{}
 }
 
 type MacNotifier struct {
@@ -19,7 +47,7 @@ type MacNotifier struct {
 
 func (m *MacNotifier) checkNotifier() {
 	m.once.Do(func() {
-		if _, err := exec.LookPath("terminal-notifier"); err != nil {
+		if _, err := lookPath("terminal-notifier"); err != nil {
 			log.Println("terminal-notifier not found in PATH")
 			m.notifierExists = false
 		} else {
@@ -28,29 +56,29 @@ func (m *MacNotifier) checkNotifier() {
 	})
 }
 
-func (m *MacNotifier) Send(title, message, url string) error {
+func (m *MacNotifier) Send(n Notification) error {
 	m.checkNotifier()
 
 	var result *exec.Cmd
 	if !m.notifierExists {
 		script := fmt.Sprintf(
 			`display notification (do shell script "echo %s") with title (do shell script "echo %s")`,
-			shellQuote(message), shellQuote(title),
+			shellQuote(n.Message), shellQuote(n.Title),
 		)
-		result = exec.Command("osascript", "-e", script)
+		result = execCommand("osascript", "-e", script)
 		log.Println("Using osascript fallback (terminal-notifier not found in PATH)")
 	} else {
 		log.Println("Using terminal-notifier")
 		args := []string{
-			"-message", message,
-			"-title", title,
+			"-message", n.Message,
+			"-title", n.Title,
 			"-sound", "ping",
 			"-group", "jenkins_monitor",
 		}
-		if url != "" {
-			args = append(args, "-open", url)
+		if n.URL != "" {
+			args = append(args, "-open", n.URL)
 		}
-		result = exec.Command("terminal-notifier", args...)
+		result = execCommand("terminal-notifier", args...)
 	}
 
 	output, err := result.CombinedOutput()
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type execCall struct {
+	Name string
+	Args []string
+}
+
+func stubExec(t *testing.T, available map[string]bool) *[]execCall {
+	t.Helper()
+	var calls []execCall
+
+	origCommand, origLookPath := execCommand, lookPath
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, execCall{Name: name, Args: args})
+		return exec.Command("true")
+	}
+	lookPath = func(file string) (string, error) {
+		if available[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() {
+		execCommand, lookPath = origCommand, origLookPath
+	})
+	return &calls
+}
+
+func TestLinuxNotifier_NotifySend(t *testing.T) {
+	calls := stubExec(t, map[string]bool{"notify-send": true})
+
+	n := NewLinuxNotifier()
+	require.NoError(t, n.Send(Notification{Title: "Jenkins Job Completed", Message: "Job: test\nStatus: SUCCESS", URL: "https://jenkins/job/test/1"}))
+
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "notify-send", (*calls)[0].Name)
+	assert.Equal(t, []string{
+		"--app-name", "jw",
+		"Jenkins Job Completed",
+		"Job: test\nStatus: SUCCESS\nhttps://jenkins/job/test/1",
+	}, (*calls)[0].Args)
+}
+
+func TestLinuxNotifier_ZenityFallback(t *testing.T) {
+	calls := stubExec(t, map[string]bool{})
+
+	n := NewLinuxNotifier()
+	require.NoError(t, n.Send(Notification{Title: "Jenkins Job Failed", Message: "Job: test"}))
+
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "zenity", (*calls)[0].Name)
+	assert.Equal(t, []string{"--notification", "--text", "Jenkins Job Failed\nJob: test"}, (*calls)[0].Args)
+}
+
+func TestMacNotifier_TerminalNotifier(t *testing.T) {
+	calls := stubExec(t, map[string]bool{"terminal-notifier": true})
+
+	n := &MacNotifier{}
+	require.NoError(t, n.Send(Notification{Title: "title", Message: "message", URL: "https://jenkins/job/test/1"}))
+
+	require.Len(t, *calls, 1)
+	assert.Equal(t, "terminal-notifier", (*calls)[0].Name)
+	assert.Equal(t, []string{
+		"-message", "message",
+		"-title", "title",
+		"-sound", "ping",
+		"-group", "jenkins_monitor",
+		"-open", "https://jenkins/job/test/1",
+	}, (*calls)[0].Args)
+}
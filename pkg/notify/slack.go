@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const slackTimeout = 10 * time.Second
+
+// SlackNotifier posts notifications to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color     string `json:"color"`
+	Title     string `json:"title"`
+	TitleLink string `json:"title_link,omitempty"`
+	Text      string `json:"text"`
+}
+
+func (s *SlackNotifier) Send(n Notification) error {
+	payload := slackPayload{
+		Text: n.Title,
+		Attachments: []slackAttachment{{
+			Color:     slackColor(n.Result),
+			Title:     n.JobName,
+			TitleLink: n.URL,
+			Text:      n.Message,
+		}},
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding slack payload: %w", err)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: slackTimeout}
+	}
+
+	resp, err := client.Post(s.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("posting to slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// slackColor maps a Jenkins result to a Slack attachment colour.
+func slackColor(result string) string {
+	switch result {
+	case "SUCCESS":
+		return "good"
+	case "FAILURE":
+		return "danger"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackNotifier_Send(t *testing.T) {
+	var payload slackPayload
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := &SlackNotifier{WebhookURL: server.URL}
+	err := n.Send(Notification{
+		Title:   "Jenkins Job Failed",
+		Message: "Job: my-job/42\nStatus: FAILURE",
+		URL:     "https://jenkins/job/my-job/42",
+		JobName: "my-job/42",
+		Result:  "FAILURE",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "Jenkins Job Failed", payload.Text)
+	require.Len(t, payload.Attachments, 1)
+	assert.Equal(t, "danger", payload.Attachments[0].Color)
+	assert.Equal(t, "my-job/42", payload.Attachments[0].Title)
+	assert.Equal(t, "https://jenkins/job/my-job/42", payload.Attachments[0].TitleLink)
+	assert.Equal(t, "Job: my-job/42\nStatus: FAILURE", payload.Attachments[0].Text)
+}
+
+func TestSlackNotifier_Colors(t *testing.T) {
+	assert.Equal(t, "good", slackColor("SUCCESS"))
+	assert.Equal(t, "danger", slackColor("FAILURE"))
+	assert.Equal(t, "warning", slackColor("ABORTED"))
+	assert.Equal(t, "warning", slackColor(""))
+}
+
+func TestSlackNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := &SlackNotifier{WebhookURL: server.URL}
+	err := n.Send(Notification{Title: "title", Message: "Job: x"})
+	assert.ErrorContains(t, err, "403")
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	telegramTimeout = 10 * time.Second
+	telegramAPIURL  = "https://api.telegram.org"
+)
+
+// TelegramNotifier sends notifications through a Telegram bot.
+type TelegramNotifier struct {
+	BotToken string
+	// ChatID is a user or group ID, a channel ID (negative, e.g.
+	// -1001234567890) or a public channel username such as @jw_builds.
+	ChatID string
+	// APIURL overrides the Bot API endpoint; it defaults to api.telegram.org.
+	APIURL string
+	Client *http.Client
+}
+
+type telegramMessage struct {
+	ChatID      any                   `json:"chat_id"`
+	Text        string                `json:"text"`
+	ParseMode   string                `json:"parse_mode"`
+	ReplyMarkup *telegramInlineMarkup `json:"reply_markup,omitempty"`
+}
+
+type telegramInlineMarkup struct {
+	InlineKeyboard [][]telegramButton `json:"inline_keyboard"`
+}
+
+type telegramButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (t *TelegramNotifier) Send(n Notification) error {
+	msg := telegramMessage{
+		ChatID:    telegramChatID(t.ChatID),
+		Text:      "*" + escapeTelegramMarkdown(n.Title) + "*\n" + escapeTelegramMarkdown(n.Message),
+		ParseMode: "Markdown",
+	}
+	if n.URL != "" {
+		msg.ReplyMarkup = &telegramInlineMarkup{
+			InlineKeyboard: [][]telegramButton{{{Text: "View Build", URL: n.URL}}},
+		}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encoding telegram message: %w", err)
+	}
+
+	client := t.Client
+	if client == nil {
+		client = &http.Client{Timeout: telegramTimeout}
+	}
+	apiURL := t.APIURL
+	if apiURL == "" {
+		apiURL = telegramAPIURL
+	}
+
+	resp, err := client.Post(apiURL+"/bot"+t.BotToken+"/sendMessage", "application/json", bytes.NewReader(data))
+	if err != nil {
+		// The request URL contains the bot token; keep it out of the error.
+		return fmt.Errorf("posting to telegram: %w", redactURLError(err))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || !result.OK {
+		if result.Description != "" {
+			return fmt.Errorf("telegram returned %s: %s", resp.Status, result.Description)
+		}
+		return fmt.Errorf("telegram returned %s", resp.Status)
+	}
+	return nil
+}
+
+// telegramChatID sends numeric IDs, including negative channel IDs, as
+// numbers and anything else (e.g. @channel) as a string.
+func telegramChatID(id string) any {
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return n
+	}
+	return id
+}
+
+// escapeTelegramMarkdown escapes the characters legacy Markdown mode treats
+// as formatting.
+func escapeTelegramMarkdown(s string) string {
+	return strings.NewReplacer("_", `\_`, "*", `\*`, "`", "\\`", "[", `\[`).Replace(s)
+}
+
+// redactURLError drops the request URL from an *url.Error.
+func redactURLError(err error) error {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramNotifier_Send(t *testing.T) {
+	tests := []struct {
+		name   string
+		chatID string
+		want   any
+	}{
+		{"user", "123456789", float64(123456789)},
+		{"channel", "-1001234567890", float64(-1001234567890)},
+		{"public channel", "@jw_builds", "@jw_builds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+			}))
+			defer server.Close()
+
+			n := &TelegramNotifier{BotToken: "42:ABC", ChatID: tt.chatID, APIURL: server.URL}
+			err := n.Send(Notification{Title: "Jenkins Job Failed", Message: "Job: my_job/42\nStatus: FAILURE", URL: "https://jenkins/job/my_job/42"})
+			require.NoError(t, err)
+
+			assert.Equal(t, "/bot42:ABC/sendMessage", path)
+			assert.Equal(t, tt.want, body["chat_id"])
+			assert.Equal(t, "Markdown", body["parse_mode"])
+			assert.Equal(t, "*Jenkins Job Failed*\nJob: my\\_job/42\nStatus: FAILURE", body["text"])
+			assert.Equal(t, map[string]any{
+				"inline_keyboard": []any{[]any{map[string]any{"text": "View Build", "url": "https://jenkins/job/my_job/42"}}},
+			}, body["reply_markup"])
+		})
+	}
+}
+
+func TestTelegramNotifier_NoButtonWithoutURL(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	n := &TelegramNotifier{BotToken: "t", ChatID: "1", APIURL: server.URL}
+	require.NoError(t, n.Send(Notification{Title: "title", Message: "message"}))
+	assert.NotContains(t, body, "reply_markup")
+}
+
+func TestTelegramNotifier_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+	}))
+	defer server.Close()
+
+	n := &TelegramNotifier{BotToken: "t", ChatID: "1", APIURL: server.URL}
+	err := n.Send(Notification{Title: "title", Message: "message"})
+	assert.ErrorContains(t, err, "chat not found")
+}
+
+func TestTelegramNotifier_ErrorHidesToken(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	n := &TelegramNotifier{BotToken: "secret-token", ChatID: "1", APIURL: "http://" + addr}
+	err = n.Send(Notification{Title: "title", Message: "message"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	webhookTimeout  = 10 * time.Second
+	webhookAttempts = 3
+)
+
+// webhookRetryDelay is the pause between attempts; tests shorten it.
+var webhookRetryDelay = time.Second
+
+// WebhookNotifier POSTs notifications as JSON to an arbitrary HTTP endpoint.
+type WebhookNotifier struct {
+	URL string
+	// Headers are added to every request, e.g. for Authorization.
+	Headers map[string]string
+	Client  *http.Client
+}
+
+type webhookPayload struct {
+	Title     string `json:"title"`
+	Message   string `json:"message"`
+	URL       string `json:"url"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Send posts the notification, retrying connection errors and 5xx responses
+// up to three times in total.
+func (w *WebhookNotifier) Send(n Notification) error {
+	data, err := json.Marshal(webhookPayload{
+		Title:     n.Title,
+		Message:   n.Message,
+		URL:       n.URL,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(client, data)
+		if err == nil || !retry || attempt == webhookAttempts {
+			return err
+		}
+		time.Sleep(webhookRetryDelay)
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (w *WebhookNotifier) post(client *http.Client, data []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(data))
+	if err != nil {
+		return false, fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return false, nil
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noRetryDelay(t *testing.T) {
+	old := webhookRetryDelay
+	webhookRetryDelay = time.Millisecond
+	t.Cleanup(func() { webhookRetryDelay = old })
+}
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	var payload map[string]string
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	n := &WebhookNotifier{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer s3cret", "X-Team": "ci"},
+	}
+	require.NoError(t, n.Send(Notification{Title: "Jenkins Job Failed", Message: "Job: my-job/42\nStatus: FAILURE", URL: "https://jenkins/job/my-job/42"}))
+
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "Bearer s3cret", header.Get("Authorization"))
+	assert.Equal(t, "ci", header.Get("X-Team"))
+
+	assert.Len(t, payload, 4)
+	assert.Equal(t, "Jenkins Job Failed", payload["title"])
+	assert.Equal(t, "Job: my-job/42\nStatus: FAILURE", payload["message"])
+	assert.Equal(t, "https://jenkins/job/my-job/42", payload["url"])
+	ts, err := time.Parse(time.RFC3339, payload["timestamp"])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+}
+
+func TestWebhookNotifier_RetriesServerErrors(t *testing.T) {
+	noRetryDelay(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, (&WebhookNotifier{URL: server.URL}).Send(Notification{Title: "t", Message: "m"}))
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestWebhookNotifier_GivesUpAfterThreeAttempts(t *testing.T) {
+	noRetryDelay(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := (&WebhookNotifier{URL: server.URL}).Send(Notification{Title: "t", Message: "m"})
+	assert.ErrorContains(t, err, "503")
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestWebhookNotifier_DoesNotRetryClientErrors(t *testing.T) {
+	noRetryDelay(t)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := (&WebhookNotifier{URL: server.URL}).Send(Notification{Title: "t", Message: "m"})
+	assert.ErrorContains(t, err, "401")
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestWebhookNotifier_RetriesConnectionRefused(t *testing.T) {
+	noRetryDelay(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	err = (&WebhookNotifier{URL: "http://" + addr}).Send(Notification{Title: "t", Message: "m"})
+	assert.ErrorContains(t, err, "posting to webhook")
+}
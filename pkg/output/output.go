@@ -0,0 +1,94 @@
+// Package output renders command results as a table, JSON or plain text.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Formats accepted by New.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatPlain = "plain"
+)
+
+// Records is a command's result: Columns and Rows for the table and plain
+// formats, and Data, usually the same records as structs, for JSON.
+type Records struct {
+	Columns []string
+	Rows    [][]string
+	Data    any
+}
+
+// Renderer writes Records in one format.
+type Renderer interface {
+	Render(records Records) error
+}
+
+// TableRenderer writes Records as aligned columns under a header line.
+type TableRenderer struct {
+	W io.Writer
+}
+
+func (r TableRenderer) Render(records Records) error {
+	tw := tabwriter.NewWriter(r.W, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(records.Columns, "\t")))
+	for _, row := range records.Rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// JSONRenderer writes Records.Data as indented JSON. A nil Data is written as
+// an empty array so that scripts can always iterate over the result.
+type JSONRenderer struct {
+	W io.Writer
+}
+
+func (r JSONRenderer) Render(records Records) error {
+	data := records.Data
+	if data == nil {
+		data = []any{}
+	}
+	enc := json.NewEncoder(r.W)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// PlainRenderer writes one tab-separated line per row without a header, for
+// piping into other tools.
+type PlainRenderer struct {
+	W io.Writer
+}
+
+func (r PlainRenderer) Render(records Records) error {
+	for _, row := range records.Rows {
+		if _, err := fmt.Fprintln(r.W, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// New returns the Renderer for format, writing to w.
+func New(format string, w io.Writer) (Renderer, error) {
+	switch format {
+	case FormatTable:
+		return TableRenderer{W: w}, nil
+	case FormatJSON:
+		return JSONRenderer{W: w}, nil
+	case FormatPlain:
+		return PlainRenderer{W: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want json, table or plain)", format)
+	}
+}
+
+// Render writes records with renderer.
+func Render(records Records, renderer Renderer) error {
+	return renderer.Render(records)
+}
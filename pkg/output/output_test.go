@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type job struct {
+	URL    string `json:"url"`
+	Result string `json:"result"`
+}
+
+func sampleRecords() Records {
+	jobs := []job{
+		{URL: "https://jenkins/job/a/1", Result: "SUCCESS"},
+		{URL: "https://jenkins/job/long-name/22", Result: "FAILURE"},
+	}
+	records := Records{Columns: []string{"Job", "Result"}, Data: jobs}
+	for _, j := range jobs {
+		records.Rows = append(records.Rows, []string{j.URL, j.Result})
+	}
+	return records
+}
+
+func TestTableRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(sampleRecords(), TableRenderer{W: &buf}))
+	assert.Equal(t, ""+
+		"JOB                               RESULT\n"+
+		"https://jenkins/job/a/1           SUCCESS\n"+
+		"https://jenkins/job/long-name/22  FAILURE\n", buf.String())
+}
+
+func TestTableRenderer_HeaderOnlyWhenEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(Records{Columns: []string{"Job", "Result"}}, TableRenderer{W: &buf}))
+	assert.Equal(t, "JOB  RESULT\n", buf.String())
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(sampleRecords(), JSONRenderer{W: &buf}))
+
+	var decoded []job
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, sampleRecords().Data, decoded)
+	assert.Contains(t, buf.String(), "\n  {", "indented")
+}
+
+func TestJSONRenderer_NilDataIsEmptyArray(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(Records{}, JSONRenderer{W: &buf}))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestPlainRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Render(sampleRecords(), PlainRenderer{W: &buf}))
+	assert.Equal(t, "https://jenkins/job/a/1\tSUCCESS\nhttps://jenkins/job/long-name/22\tFAILURE\n", buf.String())
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	for format, want := range map[string]Renderer{
+		FormatTable: TableRenderer{W: &buf},
+		FormatJSON:  JSONRenderer{W: &buf},
+		FormatPlain: PlainRenderer{W: &buf},
+	} {
+		got, err := New(format, &buf)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, format)
+	}
+
+	_, err := New("yaml", &buf)
+	assert.ErrorContains(t, err, `unknown output format "yaml"`)
+}
@@ -0,0 +1,133 @@
+// Package paths locates the directories jw keeps its config, logs and
+// runtime files in.
+//
+// JW_CONFIG_DIR, if set, holds everything. Otherwise an existing ~/.jw
+// directory is used so upgrades keep working with their current files, and
+// fresh installs follow the platform conventions: the XDG Base Directory
+// variables on Linux and ~/Library/Application Support/jw on macOS.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	appName       = "jw"
+	legacyDirName = ".jw"
+	// overrideEnvVar names a directory to keep all of jw's files in, for
+	// running independent instances or sharing a config.
+	overrideEnvVar = "JW_CONFIG_DIR"
+)
+
+// goos is overridable in tests.
+var goos = runtime.GOOS
+
+// ConfigDir returns the directory holding the config file and credentials.
+func ConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return ConfigDirIn(home), nil
+}
+
+// ConfigDirIn is ConfigDir for an explicit home directory.
+func ConfigDirIn(home string) string {
+	return dir(home, "XDG_CONFIG_HOME", ".config")
+}
+
+// LogDir returns the directory holding the daemon log.
+func LogDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return dir(home, "XDG_STATE_HOME", filepath.Join(".local", "state")), nil
+}
+
+// RuntimeDir returns the directory holding the PID file and control socket.
+// On Linux it is under XDG_RUNTIME_DIR when that is set, and shares LogDir
+// otherwise.
+func RuntimeDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if override := OverrideDir(); override != "" {
+		return override, nil
+	}
+	if legacy, ok := legacyDir(home); ok {
+		return legacy, nil
+	}
+	if base := xdgBase("XDG_RUNTIME_DIR"); base != "" && goos == "linux" {
+		return filepath.Join(base, appName), nil
+	}
+	return LogDir()
+}
+
+// SystemdUserDir returns the directory systemd reads user units from:
+// XDG_CONFIG_HOME/systemd/user, or ~/.config/systemd/user.
+func SystemdUserDir(home string) string {
+	return filepath.Join(xdgHome(home, "XDG_CONFIG_HOME", ".config"), "systemd", "user")
+}
+
+// dir resolves a jw directory under home: JW_CONFIG_DIR if set, ~/.jw if it
+// exists, otherwise the platform location, where on Linux xdgVar overrides
+// the default home-relative base.
+func dir(home, xdgVar, linuxDefault string) string {
+	if override := OverrideDir(); override != "" {
+		return override
+	}
+	legacy, ok := legacyDir(home)
+	if ok {
+		return legacy
+	}
+
+	switch goos {
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", appName)
+	case "linux":
+		return filepath.Join(xdgHome(home, xdgVar, linuxDefault), appName)
+	default:
+		return legacy
+	}
+}
+
+// OverrideDir returns JW_CONFIG_DIR made absolute, or "" if it is unset.
+func OverrideDir() string {
+	v := os.Getenv(overrideEnvVar)
+	if v == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(v); err == nil {
+		return abs
+	}
+	return v
+}
+
+// legacyDir returns ~/.jw and whether it already exists.
+func legacyDir(home string) (string, bool) {
+	path := filepath.Join(home, legacyDirName)
+	info, err := os.Stat(path)
+	return path, err == nil && info.IsDir()
+}
+
+// xdgHome returns the XDG base directory named by xdgVar, or fallback under
+// home if it is not usable.
+func xdgHome(home, xdgVar, fallback string) string {
+	if base := xdgBase(xdgVar); base != "" {
+		return base
+	}
+	return filepath.Join(home, fallback)
+}
+
+// xdgBase returns the value of an XDG base directory variable, or "" if it is
+// unset or relative, which the specification says to ignore.
+func xdgBase(name string) string {
+	if v := os.Getenv(name); filepath.IsAbs(v) {
+		return v
+	}
+	return ""
+}
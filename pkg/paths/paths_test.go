@@ -0,0 +1,103 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setPlatform(t *testing.T, platform string) string {
+	t.Helper()
+	orig := goos
+	goos = platform
+	t.Cleanup(func() { goos = orig })
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	t.Setenv(overrideEnvVar, "")
+	return home
+}
+
+func dirs(t *testing.T) []string {
+	t.Helper()
+	var out []string
+	for _, fn := range []func() (string, error){ConfigDir, LogDir, RuntimeDir} {
+		dir, err := fn()
+		require.NoError(t, err)
+		out = append(out, dir)
+	}
+	return out
+}
+
+func TestDirs_LinuxDefaults(t *testing.T) {
+	home := setPlatform(t, "linux")
+	state := filepath.Join(home, ".local", "state", "jw")
+	assert.Equal(t, []string{filepath.Join(home, ".config", "jw"), state, state}, dirs(t))
+}
+
+func TestDirs_LinuxXDG(t *testing.T) {
+	setPlatform(t, "linux")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+	assert.Equal(t, []string{"/xdg/config/jw", "/xdg/state/jw", "/run/user/1000/jw"}, dirs(t))
+}
+
+func TestDirs_LinuxIgnoresRelativeXDG(t *testing.T) {
+	home := setPlatform(t, "linux")
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+	dir, err := ConfigDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".config", "jw"), dir)
+}
+
+func TestDirs_Darwin(t *testing.T) {
+	home := setPlatform(t, "darwin")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	support := filepath.Join(home, "Library", "Application Support", "jw")
+	assert.Equal(t, []string{support, support, support}, dirs(t))
+}
+
+func TestDirs_LegacyDirWins(t *testing.T) {
+	for _, platform := range []string{"linux", "darwin"} {
+		t.Run(platform, func(t *testing.T) {
+			home := setPlatform(t, platform)
+			t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+			t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+			legacy := filepath.Join(home, ".jw")
+			require.NoError(t, os.Mkdir(legacy, 0o755))
+			assert.Equal(t, []string{legacy, legacy, legacy}, dirs(t))
+		})
+	}
+}
+
+func TestDirs_OverrideWins(t *testing.T) {
+	for _, platform := range []string{"linux", "darwin"} {
+		t.Run(platform, func(t *testing.T) {
+			home := setPlatform(t, platform)
+			t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+			require.NoError(t, os.Mkdir(filepath.Join(home, ".jw"), 0o755))
+			override := t.TempDir()
+			t.Setenv(overrideEnvVar, override)
+			assert.Equal(t, []string{override, override, override}, dirs(t))
+			assert.Equal(t, override, ConfigDirIn(home))
+		})
+	}
+}
+
+func TestSystemdUserDir(t *testing.T) {
+	home := setPlatform(t, "linux")
+	assert.Equal(t, filepath.Join(home, ".config", "systemd", "user"), SystemdUserDir(home))
+
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+	assert.Equal(t, filepath.Join(home, ".config", "systemd", "user"), SystemdUserDir(home))
+
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	assert.Equal(t, "/xdg/config/systemd/user", SystemdUserDir(home))
+}
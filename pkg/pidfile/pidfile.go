@@ -7,14 +7,59 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
+
+	"jenkins-monitor/pkg/paths"
+)
+
+const (
+	fileName       = "daemon.pid"
+	legacyFileName = ".jenkins_monitor.pid"
 )
 
+// GetPidFilePath returns the PID file path in the runtime directory, which is
+// the config directory when JW_CONFIG_DIR or ~/.jw is in use.
 func GetPidFilePath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := paths.RuntimeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".jw", ".jenkins_monitor.pid"), nil
+	return filepath.Join(dir, fileName), nil
+}
+
+// MigrateLegacy moves a PID file left under an older name, in the runtime
+// directory or directly in the home directory, to GetPidFilePath so a daemon
+// started by an earlier version is still found. An existing new PID file wins.
+func MigrateLegacy() error {
+	path, err := GetPidFilePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	for _, old := range []string{filepath.Join(filepath.Dir(path), legacyFileName), filepath.Join(home, legacyFileName)} {
+		data, err := os.ReadFile(old)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+		return os.Remove(old)
+	}
+	return nil
 }
 
 func IsDaemonRunning() (int, bool) {
@@ -54,13 +99,36 @@ func IsDaemonRunning() (int, bool) {
 	return 0, false
 }
 
+// IsStale reports whether a PID file exists but does not point at a live
+// process. Unlike IsDaemonRunning it never removes the file.
+func IsStale() (bool, error) {
+	path, err := GetPidFilePath()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return true, nil
+	}
+	return syscall.Kill(pid, 0) == syscall.ESRCH, nil
+}
+
 func Write() error {
 	path, err := GetPidFilePath()
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
 
@@ -76,6 +144,20 @@ func Remove() error {
 	return os.Remove(path)
 }
 
+// StartTime returns when the daemon started, taken from when it wrote the
+// PID file.
+func StartTime() (time.Time, error) {
+	path, err := GetPidFilePath()
+	if err != nil {
+		return time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
 // CheckAndRestore ensures the PID file exists and contains the current PID.
 // If the file is missing or contains a different PID, it is overwritten.
 func CheckAndRestore() error {
@@ -105,7 +187,8 @@ func CheckAndRestore() error {
 }
 
 // FindDaemonProcess attempts to find a running daemon process by inspecting
-// the process list for the signature argument "_start_jw_daemon".
+// the process list for the signature argument "_start_jw_daemon". Daemons
+// started with a different JW_CONFIG_DIR are skipped.
 func FindDaemonProcess() (int, bool) {
 	// Using pgrep to find the process with the specific argument
 	cmd := exec.Command("pgrep", "-f", "_start_jw_daemon")
@@ -120,10 +203,31 @@ func FindDaemonProcess() (int, bool) {
 		if err == nil {
 			// Ensure we don't accidentally match ourselves if we were somehow
 			// called with that argument (unlikely for the CLI tool, but good practice)
-			if pid != os.Getpid() {
+			if pid != os.Getpid() && sameConfigDir(pid) {
 				return pid, true
 			}
 		}
 	}
 	return 0, false
 }
+
+// sameConfigDir reports whether process pid runs with our JW_CONFIG_DIR. Where
+// another process's environment cannot be read, only the default instance
+// claims it.
+func sameConfigDir(pid int) bool {
+	ours := paths.OverrideDir()
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return ours == ""
+	}
+	var theirs string
+	for kv := range strings.SplitSeq(string(data), "\x00") {
+		if v, ok := strings.CutPrefix(kv, "JW_CONFIG_DIR="); ok && v != "" {
+			theirs = v
+			if abs, err := filepath.Abs(v); err == nil {
+				theirs = abs
+			}
+		}
+	}
+	return theirs == ours
+}
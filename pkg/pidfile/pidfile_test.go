@@ -0,0 +1,85 @@
+package pidfile
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setConfigDir(t *testing.T) (home, dir string) {
+	t.Helper()
+	home = t.TempDir()
+	dir = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("JW_CONFIG_DIR", dir)
+	return home, dir
+}
+
+func TestGetPidFilePath(t *testing.T) {
+	_, dir := setConfigDir(t)
+	path, err := GetPidFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "daemon.pid"), path)
+
+	require.NoError(t, Write())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid()), string(data))
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	for name, oldDir := range map[string]func(home, dir string) string{
+		"home":        func(home, _ string) string { return home },
+		"runtime dir": func(_, dir string) string { return dir },
+	} {
+		t.Run(name, func(t *testing.T) {
+			home, dir := setConfigDir(t)
+			old := filepath.Join(oldDir(home, dir), legacyFileName)
+			require.NoError(t, os.WriteFile(old, []byte("4242"), 0o644))
+
+			require.NoError(t, MigrateLegacy())
+			data, err := os.ReadFile(filepath.Join(dir, fileName))
+			require.NoError(t, err)
+			assert.Equal(t, "4242", string(data))
+			assert.NoFileExists(t, old)
+		})
+	}
+}
+
+func TestMigrateLegacy_KeepsCurrentFile(t *testing.T) {
+	home, dir := setConfigDir(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fileName), []byte("1"), 0o644))
+	old := filepath.Join(home, legacyFileName)
+	require.NoError(t, os.WriteFile(old, []byte("2"), 0o644))
+
+	require.NoError(t, MigrateLegacy())
+	data, err := os.ReadFile(filepath.Join(dir, fileName))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(data))
+	assert.FileExists(t, old)
+}
+
+func TestSameConfigDir(t *testing.T) {
+	if _, err := os.Stat("/proc/self/environ"); err != nil {
+		t.Skip("no /proc")
+	}
+	_, dir := setConfigDir(t)
+	child := exec.Command("sleep", "30")
+	child.Env = append(os.Environ(), "JW_CONFIG_DIR="+dir)
+	require.NoError(t, child.Start())
+	t.Cleanup(func() {
+		_ = child.Process.Kill()
+		_ = child.Wait()
+	})
+
+	assert.True(t, sameConfigDir(child.Process.Pid))
+	t.Setenv("JW_CONFIG_DIR", t.TempDir())
+	assert.False(t, sameConfigDir(child.Process.Pid))
+	t.Setenv("JW_CONFIG_DIR", "")
+	assert.False(t, sameConfigDir(child.Process.Pid))
+}
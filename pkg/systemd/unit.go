@@ -0,0 +1,52 @@
+// Package systemd generates the systemd user unit that runs the jw daemon.
+package systemd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnitName is the name jw's user unit is installed under.
+const UnitName = "jw.service"
+
+// GenerateUserUnit returns a systemd user unit running the daemon from
+// binaryPath. The daemon exits by itself once no jobs are left, so it is not
+// restarted. A non-empty configDir is passed to the daemon as JW_CONFIG_DIR.
+func GenerateUserUnit(binaryPath, configDir string) string {
+	var env string
+	if configDir != "" {
+		env = "Environment=" + quoteEnv("JW_CONFIG_DIR="+configDir) + "\n"
+	}
+	return fmt.Sprintf(`[Unit]
+Description=jw Jenkins build monitor daemon
+Documentation=https://github.com/baggiiiie/jw
+
+[Service]
+Type=simple
+%sExecStart=%s _start_jw_daemon
+Restart=no
+
+[Install]
+WantedBy=default.target
+`, env, quoteArg(binaryPath))
+}
+
+// quoteArg escapes s for use as an ExecStart argument: % and $ would
+// otherwise be expanded, and whitespace, quotes and backslashes need quoting.
+func quoteArg(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// quoteEnv escapes an Environment= assignment. Only specifiers are expanded
+// there, so unlike quoteArg it leaves $ alone.
+func quoteEnv(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,68 @@
+package systemd
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseUnit returns the key=value entries of a unit file by section.
+func parseUnit(t *testing.T, unit string) map[string]map[string]string {
+	t.Helper()
+	sections := map[string]map[string]string{}
+	var current string
+	scanner := bufio.NewScanner(strings.NewReader(unit))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			current = strings.Trim(line, "[]")
+			sections[current] = map[string]string{}
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			require.True(t, ok, "malformed line %q", line)
+			require.NotEmpty(t, current, "entry %q outside a section", line)
+			sections[current][key] = value
+		}
+	}
+	return sections
+}
+
+func TestGenerateUserUnit(t *testing.T) {
+	unit := parseUnit(t, GenerateUserUnit("/usr/local/bin/jw", ""))
+
+	require.Contains(t, unit, "Unit")
+	require.Contains(t, unit, "Service")
+	require.Contains(t, unit, "Install")
+	assert.NotEmpty(t, unit["Unit"]["Description"])
+	assert.Equal(t, "/usr/local/bin/jw _start_jw_daemon", unit["Service"]["ExecStart"])
+	assert.Equal(t, "simple", unit["Service"]["Type"])
+	assert.Equal(t, "default.target", unit["Install"]["WantedBy"])
+	assert.NotContains(t, unit["Service"], "Environment")
+}
+
+func TestGenerateUserUnit_ConfigDir(t *testing.T) {
+	unit := parseUnit(t, GenerateUserUnit("/usr/local/bin/jw", "/srv/jw"))
+	assert.Equal(t, "JW_CONFIG_DIR=/srv/jw", unit["Service"]["Environment"])
+
+	unit = parseUnit(t, GenerateUserUnit("/usr/local/bin/jw", "/srv/my jw/100%/$x"))
+	assert.Equal(t, `"JW_CONFIG_DIR=/srv/my jw/100%%/$x"`, unit["Service"]["Environment"])
+}
+
+func TestGenerateUserUnit_QuotesExecutable(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"/opt/my tools/jw", `"/opt/my tools/jw" _start_jw_daemon`},
+		{`/opt/a"b/jw`, `"/opt/a\"b/jw" _start_jw_daemon`},
+		{"/opt/100%/$HOME/jw", "/opt/100%%/$$HOME/jw _start_jw_daemon"},
+	}
+	for _, tt := range tests {
+		unit := parseUnit(t, GenerateUserUnit(tt.path, ""))
+		assert.Equal(t, tt.want, unit["Service"]["ExecStart"], tt.path)
+	}
+}
@@ -1,5 +1,7 @@
 package ui
 
+import "os"
+
 const (
 	Green  = "\033[92m"
 	Yellow = "\033[93m"
@@ -8,18 +10,42 @@ const (
 	End    = "\033[0m"
 )
 
+// Enabled reports whether the *Text helpers colour their output. It starts
+// out false when NO_COLOR is set to a non-empty value (https://no-color.org).
+var Enabled bool
+
+func init() {
+	Enabled = colorFromEnv()
+}
+
+func colorFromEnv() bool {
+	return os.Getenv("NO_COLOR") == ""
+}
+
+// SetEnabled turns colour output on or off.
+func SetEnabled(enabled bool) {
+	Enabled = enabled
+}
+
+func colorize(color, s string) string {
+	if !Enabled {
+		return s
+	}
+	return color + s + End
+}
+
 func GreenText(s string) string {
-	return Green + s + End
+	return colorize(Green, s)
 }
 
 func YellowText(s string) string {
-	return Yellow + s + End
+	return colorize(Yellow, s)
 }
 
 func RedText(s string) string {
-	return Red + s + End
+	return colorize(Red, s)
 }
 
 func MutedText(s string) string {
-	return Gray + s + End
+	return colorize(Gray, s)
 }
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withColor(t *testing.T, enabled bool) {
+	t.Helper()
+	orig := Enabled
+	SetEnabled(enabled)
+	t.Cleanup(func() { SetEnabled(orig) })
+}
+
+func TestTextHelpers_Colored(t *testing.T) {
+	withColor(t, true)
+
+	assert.Equal(t, Green+"ok"+End, GreenText("ok"))
+	assert.Equal(t, Yellow+"warn"+End, YellowText("warn"))
+	assert.Equal(t, Red+"fail"+End, RedText("fail"))
+	assert.Equal(t, Gray+"quiet"+End, MutedText("quiet"))
+}
+
+func TestTextHelpers_NoColorEnv(t *testing.T) {
+	orig := Enabled
+	t.Cleanup(func() { SetEnabled(orig) })
+	t.Setenv("NO_COLOR", "1")
+	SetEnabled(colorFromEnv())
+
+	assert.False(t, Enabled)
+	assert.Equal(t, "ok", GreenText("ok"))
+	assert.Equal(t, "warn", YellowText("warn"))
+	assert.Equal(t, "fail", RedText("fail"))
+	assert.Equal(t, "quiet", MutedText("quiet"))
+}
+
+func TestTextHelpers_EmptyNoColorKeepsColor(t *testing.T) {
+	orig := Enabled
+	t.Cleanup(func() { SetEnabled(orig) })
+	t.Setenv("NO_COLOR", "")
+	SetEnabled(colorFromEnv())
+
+	assert.True(t, Enabled)
+	assert.Equal(t, Green+"ok"+End, GreenText("ok"))
+}
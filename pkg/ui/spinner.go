@@ -44,13 +44,23 @@ func (s *Spinner) Start() {
 				fmt.Printf("Done!   \n")
 				return
 			case <-ticker.C:
-				fmt.Printf("\r%s %s... ", s.frames[i%len(s.frames)], s.text)
+				s.mu.Lock()
+				text := s.text
+				s.mu.Unlock()
+				fmt.Printf("\r\033[K%s %s... ", s.frames[i%len(s.frames)], text)
 				i++
 			}
 		}
 	}()
 }
 
+// SetText changes the message shown next to the spinner.
+func (s *Spinner) SetText(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.text = text
+}
+
 func (s *Spinner) Stop() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -0,0 +1,274 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// binaryName is the executable inside each release archive.
+const binaryName = "jw"
+
+// executablePath returns the binary SelfUpdate replaces, replaceable in
+// tests.
+var executablePath = os.Executable
+
+// UpdateResult describes what SelfUpdate did.
+type UpdateResult struct {
+	// Current is the version that was running, "dev" for a dev build.
+	Current string
+	Latest  string
+	// Path is the binary that was replaced.
+	Path string
+	// Updated is false if Current was already Latest or newer.
+	Updated bool
+}
+
+// ErrDevBuild is returned by SelfUpdate for a dev build without force.
+var ErrDevBuild = errors.New("this is a dev build, which an update would replace with a release; use --force to do so anyway")
+
+// SelfUpdate replaces the running binary with the newest release's build for
+// this OS and architecture, after checking it against the release's
+// checksums. With preRelease, pre-releases count as the newest release too.
+// A dev build is only replaced with force.
+func SelfUpdate(ctx context.Context, preRelease, force bool) (UpdateResult, error) {
+	result := UpdateResult{Current: "dev"}
+	if current := currentVersion(); current != "" {
+		result.Current = current
+	} else if !force {
+		return result, ErrDevBuild
+	}
+
+	release, err := fetchRelease(ctx, preRelease)
+	if err != nil {
+		return result, fmt.Errorf("looking up the latest release: %w", err)
+	}
+	result.Latest = release.TagName
+	if result.Current != "dev" && semver.Compare(result.Current, result.Latest) >= 0 {
+		return result, nil
+	}
+
+	name := assetName(runtime.GOOS, runtime.GOARCH)
+	archive, ok := findAsset(release.Assets, func(n string) bool { return n == name })
+	if !ok {
+		return result, fmt.Errorf("release %s has no build for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksums, ok := findAsset(release.Assets, func(n string) bool { return n == "checksums.txt" || strings.HasSuffix(n, "_checksums.txt") })
+	if !ok {
+		return result, fmt.Errorf("release %s has no checksums file", release.TagName)
+	}
+
+	exe, err := executablePath()
+	if err != nil {
+		return result, fmt.Errorf("locating the jw binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return result, fmt.Errorf("locating the jw binary: %w", err)
+	}
+	result.Path = exe
+
+	want, err := fetchChecksum(ctx, checksums.DownloadURL, name)
+	if err != nil {
+		return result, err
+	}
+	// Everything is staged next to the binary so the final rename stays on
+	// one filesystem and is atomic.
+	dir := filepath.Dir(exe)
+	archivePath, sum, err := download(ctx, archive.DownloadURL, dir)
+	if err != nil {
+		return result, fmt.Errorf("downloading %s: %w", name, err)
+	}
+	defer os.Remove(archivePath)
+	if !strings.EqualFold(sum, want) {
+		return result, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, sum, want)
+	}
+
+	if err := replaceBinary(archivePath, exe); err != nil {
+		return result, err
+	}
+	result.Updated = true
+	return result, nil
+}
+
+// assetName is the release archive built for goos and goarch, following the
+// name template in .goreleaser.yaml, e.g. "jw_Darwin_arm64.tar.gz".
+func assetName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	return fmt.Sprintf("%s_%s_%s.tar.gz", binaryName, strings.ToUpper(goos[:1])+goos[1:], arch)
+}
+
+func findAsset(assets []releaseAsset, match func(name string) bool) (releaseAsset, bool) {
+	for _, a := range assets {
+		if match(a.Name) {
+			return a, true
+		}
+	}
+	return releaseAsset{}, false
+}
+
+// fetchRelease returns the latest release or, with preRelease, the newest
+// release that is not a draft.
+func fetchRelease(ctx context.Context, preRelease bool) (releaseResponse, error) {
+	if !preRelease {
+		var release releaseResponse
+		err := getJSON(ctx, latestReleaseURL, &release)
+		return release, err
+	}
+
+	var releases []releaseResponse
+	if err := getJSON(ctx, releasesURL, &releases); err != nil {
+		return releaseResponse{}, err
+	}
+	for _, r := range releases {
+		if !r.Draft {
+			return r, nil
+		}
+	}
+	return releaseResponse{}, errors.New("no releases found")
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// fetchChecksum returns the SHA-256 listed for name in the checksums file at
+// url, in the "<hex>  <name>" format of sha256sum.
+func fetchChecksum(ctx context.Context, url, name string) (string, error) {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("downloading checksums: %w", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading checksums: %w", err)
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// download saves url to a temporary file in dir and returns its path and
+// SHA-256.
+func download(ctx context.Context, url, dir string) (path, sum string, err error) {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.CreateTemp(dir, ".jw-download-*")
+	if err != nil {
+		return "", "", err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", "", err
+	}
+	return f.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// replaceBinary extracts the jw binary from the tar.gz archive and renames it
+// over exe, keeping exe's permissions.
+func replaceBinary(archive, exe string) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("archive has no %s binary", binaryName)
+		}
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binaryName {
+			break
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".jw-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, tr); err != nil {
+		tmp.Close()
+		return fmt.Errorf("extracting %s: %w", binaryName, err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package upgrade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"jenkins-monitor/pkg/version"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func releaseArchive(t *testing.T, binary string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"extension/manifest.json": "{}", binaryName: binary} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// fakeGitHub serves a stable v1.1.0 and a newer v1.2.0-rc.1 pre-release, each
+// with this platform's archive and a checksums file.
+type fakeGitHub struct {
+	archives  map[string][]byte
+	checksums map[string]string
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+	gh := &fakeGitHub{archives: map[string][]byte{}, checksums: map[string]string{}}
+	for _, tag := range []string{"v1.1.0", "v1.2.0-rc.1"} {
+		archive := releaseArchive(t, "jw "+tag)
+		sum := sha256.Sum256(archive)
+		gh.archives[tag] = archive
+		gh.checksums[tag] = hex.EncodeToString(sum[:]) + "  " + assetName(runtime.GOOS, runtime.GOARCH) + "\n" +
+			"0000000000000000000000000000000000000000000000000000000000000000  jw_Plan9_mips.tar.gz\n"
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	release := func(tag string, pre bool) releaseResponse {
+		return releaseResponse{TagName: tag, Prerelease: pre, Assets: []releaseAsset{
+			{Name: "jw_" + tag[1:] + "_checksums.txt", DownloadURL: server.URL + "/download/" + tag + "/checksums.txt"},
+			{Name: assetName(runtime.GOOS, runtime.GOARCH), DownloadURL: server.URL + "/download/" + tag + "/archive"},
+		}}
+	}
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(release("v1.1.0", false))
+	})
+	mux.HandleFunc("/releases", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]releaseResponse{
+			{TagName: "v1.3.0", Draft: true},
+			release("v1.2.0-rc.1", true),
+			release("v1.1.0", false),
+		})
+	})
+	mux.HandleFunc("/download/{tag}/{file}", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.PathValue("tag")
+		if r.PathValue("file") == "checksums.txt" {
+			_, _ = w.Write([]byte(gh.checksums[tag]))
+			return
+		}
+		_, _ = w.Write(gh.archives[tag])
+	})
+
+	origLatest, origReleases, origVersion, origExe := latestReleaseURL, releasesURL, version.Version, executablePath
+	latestReleaseURL, releasesURL, version.Version = server.URL+"/releases/latest", server.URL+"/releases", "v1.0.0"
+	t.Cleanup(func() {
+		latestReleaseURL, releasesURL, version.Version, executablePath = origLatest, origReleases, origVersion, origExe
+	})
+	return gh
+}
+
+func fakeExecutable(t *testing.T) string {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "jw")
+	require.NoError(t, os.WriteFile(exe, []byte("jw v1.0.0"), 0o750))
+	executablePath = func() (string, error) { return exe, nil }
+	return exe
+}
+
+func TestSelfUpdate(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+
+	result, err := SelfUpdate(context.Background(), false, false)
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, "v1.0.0", result.Current)
+	assert.Equal(t, "v1.1.0", result.Latest)
+
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.1.0", string(data))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are cleaned up")
+}
+
+func TestSelfUpdate_PreRelease(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+
+	result, err := SelfUpdate(context.Background(), true, false)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0-rc.1", result.Latest)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.2.0-rc.1", string(data))
+}
+
+func TestSelfUpdate_PreReleaseToNewerPreRelease(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+	version.Version = "v1.2.0-rc.0"
+
+	result, err := SelfUpdate(context.Background(), true, false)
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, "v1.2.0-rc.0", result.Current)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.2.0-rc.1", string(data))
+
+	version.Version = "v1.2.0-rc.1"
+	result, err = SelfUpdate(context.Background(), true, false)
+	require.NoError(t, err)
+	assert.False(t, result.Updated, "the same pre-release is up to date")
+}
+
+func TestSelfUpdate_PreReleaseToFinal(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+	version.Version = "v1.1.0-rc.2"
+
+	result, err := SelfUpdate(context.Background(), false, false)
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, "v1.1.0", result.Latest)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.1.0", string(data))
+}
+
+func TestSelfUpdate_DevBuildNeedsForce(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+	version.Version = "dev"
+
+	_, err := SelfUpdate(context.Background(), false, false)
+	require.ErrorIs(t, err, ErrDevBuild)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.0.0", string(data))
+
+	result, err := SelfUpdate(context.Background(), false, true)
+	require.NoError(t, err)
+	assert.True(t, result.Updated)
+	assert.Equal(t, "dev", result.Current)
+}
+
+func TestSelfUpdate_UpToDate(t *testing.T) {
+	newFakeGitHub(t)
+	exe := fakeExecutable(t)
+	version.Version = "v1.1.0"
+
+	result, err := SelfUpdate(context.Background(), false, false)
+	require.NoError(t, err)
+	assert.False(t, result.Updated)
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.0.0", string(data))
+}
+
+func TestSelfUpdate_ChecksumMismatch(t *testing.T) {
+	gh := newFakeGitHub(t)
+	exe := fakeExecutable(t)
+	gh.archives["v1.1.0"] = releaseArchive(t, "tampered")
+
+	_, err := SelfUpdate(context.Background(), false, false)
+	assert.ErrorContains(t, err, "checksum mismatch")
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "jw v1.0.0", string(data))
+}
+
+func TestAssetName(t *testing.T) {
+	assert.Equal(t, "jw_Darwin_x86_64.tar.gz", assetName("darwin", "amd64"))
+	assert.Equal(t, "jw_Darwin_arm64.tar.gz", assetName("darwin", "arm64"))
+	assert.Equal(t, "jw_Linux_i386.tar.gz", assetName("linux", "386"))
+}
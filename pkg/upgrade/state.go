@@ -0,0 +1,143 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"jenkins-monitor/pkg/config"
+	"jenkins-monitor/pkg/paths"
+)
+
+// stateFileName is kept apart from the config file so the upgrade check never
+// rotates config backups or wakes the daemon's config watcher.
+const stateFileName = "upgrade_check.json"
+
+// State is what the upgrade check remembers between commands.
+type State struct {
+	LastChecked   time.Time `json:"last_checked"`
+	LatestVersion string    `json:"latest_version"`
+	// LastNotified is when a newer LatestVersion was last pointed out.
+	LastNotified time.Time `json:"last_notified,omitzero"`
+}
+
+type StateStore interface {
+	Load() (State, error)
+	Update(func(*State)) error
+}
+
+// FileStateStore keeps State in a JSON file.
+type FileStateStore struct {
+	mu   sync.Mutex
+	path string
+	// configPath is the config file, where older versions kept State under
+	// "upgrade_check". It is read while the state file does not exist yet.
+	configPath string
+}
+
+// NewFileStateStore returns a store for the state file in the config
+// directory.
+func NewFileStateStore() (*FileStateStore, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return &FileStateStore{path: filepath.Join(dir, stateFileName), configPath: configPath}, nil
+}
+
+// Load returns the saved state, or the zero State if there is none or it
+// cannot be parsed.
+func (s *FileStateStore) Load() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *FileStateStore) Update(fn func(*State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, err := s.load()
+	if err != nil {
+		return err
+	}
+	fn(&state)
+	return s.save(state)
+}
+
+func (s *FileStateStore) load() (State, error) {
+	var state State
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.loadFromConfig(), nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if json.Unmarshal(data, &state) != nil {
+		return State{}, nil
+	}
+	return state, nil
+}
+
+// loadFromConfig returns the state older versions kept in the config file,
+// or the zero State. The first Update then moves it to the state file; the
+// config drops it the next time jw writes it.
+func (s *FileStateStore) loadFromConfig() State {
+	var legacy struct {
+		UpgradeCheck State `json:"upgrade_check"`
+	}
+	data, err := os.ReadFile(s.configPath)
+	if err != nil || json.Unmarshal(data, &legacy) != nil {
+		return State{}
+	}
+	return legacy.UpgradeCheck
+}
+
+func (s *FileStateStore) save(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+stateFileName+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// MemoryStateStore is a StateStore that never touches disk, for tests.
+type MemoryStateStore struct {
+	mu    sync.Mutex
+	state State
+}
+
+func (s *MemoryStateStore) Load() (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state, nil
+}
+
+func (s *MemoryStateStore) Update(fn func(*State)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.state)
+	return nil
+}
@@ -1,59 +1,153 @@
 package upgrade
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 
-	"jenkins-monitor/pkg/config"
 	"jenkins-monitor/pkg/ui"
 	"jenkins-monitor/pkg/version"
 
 	"golang.org/x/mod/semver"
 )
 
+const (
+	// checkInterval is how often the latest release is looked up, and how
+	// often a newer one is pointed out.
+	checkInterval = 24 * time.Hour
+	// backgroundTimeout bounds a background check. It never delays the
+	// command, so it can be generous.
+	backgroundTimeout = 10 * time.Second
+)
+
+// latestReleaseURL and releasesURL are the GitHub API endpoints for the
+// latest release and for all releases, newest first, replaceable in tests.
+var (
+	latestReleaseURL = "https://api.github.com/repos/baggiiiie/jw/releases/latest"
+	releasesURL      = "https://api.github.com/repos/baggiiiie/jw/releases"
+)
+
 type releaseResponse struct {
-	TagName string `json:"tag_name"`
+	TagName    string         `json:"tag_name"`
+	Draft      bool           `json:"draft"`
+	Prerelease bool           `json:"prerelease"`
+	Assets     []releaseAsset `json:"assets"`
 }
 
-func RunCheck(store config.ConfigStore, cfg *config.Config) {
-	current := version.GetVersion()
-	fmt.Println("Current version:", current)
+type releaseAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// RunCheck prints the running version and, if a newer release is out, how
+// to upgrade.
+func RunCheck(w io.Writer, store StateStore) {
+	fmt.Fprintln(w, "Current version:", version.GetVersion())
+	current := currentVersion()
 	// Skip check for dev builds
-	if current == "dev" {
+	if current == "" {
 		return
 	}
-	if strings.Contains(current, "-") {
-		current = strings.SplitN(current, "-", 2)[0]
-	}
-	if !strings.HasPrefix(current, "v") {
-		current = "v" + current
-	}
-
-	latest := cfg.UpgradeState.LatestVersion
 
-	newLatest, err := fetchLatestVersion()
+	state, _ := store.Load()
+	latest := state.LatestVersion
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	newLatest, err := fetchLatestVersion(ctx, latestReleaseURL)
 	if err == nil {
 		latest = newLatest
-		cfg.UpgradeState.LatestVersion = latest
-		cfg.UpgradeState.LastChecked = time.Now()
 		// Ignore save error, not critical
-		_ = store.Save(cfg)
+		_ = store.Update(func(s *State) {
+			s.LatestVersion = latest
+			s.LastChecked = time.Now()
+		})
 	}
 
 	if latest != "" && semver.Compare(current, latest) < 0 {
-		promptUpgrade(current, latest)
+		promptUpgrade(w, current, latest)
 	}
 }
 
-func fetchLatestVersion() (string, error) {
-	client := http.Client{
-		Timeout: 500 * time.Millisecond,
+// CheckInBackground looks up the latest release in a goroutine, if the last
+// lookup is more than a day old, and saves it for NotifyIfOutdated. It
+// returns at once; the returned channel is closed when the lookup is done.
+// The attempt is only recorded once the lookup finishes, so one cut short by
+// the command exiting is retried by the next command.
+func CheckInBackground(store StateStore) <-chan struct{} {
+	done := make(chan struct{})
+	state, err := store.Load()
+	if err != nil || currentVersion() == "" || time.Since(state.LastChecked) <= checkInterval {
+		close(done)
+		return done
 	}
 
-	resp, err := client.Get("https://api.github.com/repos/baggiiiie/jw/releases/latest")
+	url := latestReleaseURL
+	go func() {
+		defer close(done)
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+		latest, err := fetchLatestVersion(ctx, url)
+		_ = store.Update(func(s *State) {
+			s.LastChecked = time.Now()
+			if err == nil {
+				s.LatestVersion = latest
+			}
+		})
+	}()
+	return done
+}
+
+// NotifyIfOutdated points out on w a newer release found by an earlier check,
+// at most once a day. It never makes a network call.
+func NotifyIfOutdated(w io.Writer, store StateStore) {
+	state, err := store.Load()
+	if err != nil {
+		return
+	}
+	current := currentVersion()
+	latest := state.LatestVersion
+	if current == "" || latest == "" || semver.Compare(current, latest) >= 0 {
+		return
+	}
+	if time.Since(state.LastNotified) <= checkInterval {
+		return
+	}
+	promptUpgrade(w, current, latest)
+	_ = store.Update(func(s *State) { s.LastNotified = time.Now() })
+}
+
+// describeSuffix matches what git describe appends to a tag for later
+// commits and uncommitted changes, e.g. "-3-gdeadbee" or "-dirty".
+var describeSuffix = regexp.MustCompile(`(-\d+-g[0-9a-f]+)?(-dirty)?$`)
+
+// currentVersion returns the running version as a semver string, keeping any
+// pre-release part, or "" for a dev build.
+func currentVersion() string {
+	current := version.GetVersion()
+	if current == "dev" {
+		return ""
+	}
+	current = describeSuffix.ReplaceAllString(current, "")
+	if !strings.HasPrefix(current, "v") {
+		current = "v" + current
+	}
+	if !semver.IsValid(current) {
+		return ""
+	}
+	return current
+}
+
+func fetchLatestVersion(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -71,7 +165,7 @@ func fetchLatestVersion() (string, error) {
 	return release.TagName, nil
 }
 
-func promptUpgrade(current, latest string) {
-	msg := fmt.Sprintf("\nNew version available: %s -> %s\nRun `brew install baggiiiie/tap/jw`\n", current, latest)
-	fmt.Println(ui.MutedText(msg))
+func promptUpgrade(w io.Writer, current, latest string) {
+	msg := fmt.Sprintf("\nNew version available: %s -> %s\nRun `jw update`, or `brew upgrade jw` if installed with Homebrew\n", current, latest)
+	fmt.Fprintln(w, ui.MutedText(msg))
 }
@@ -0,0 +1,178 @@
+package upgrade
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"jenkins-monitor/pkg/version"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withRelease(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	origURL, origVersion := latestReleaseURL, version.Version
+	latestReleaseURL, version.Version = server.URL, "v1.0.0"
+	t.Cleanup(func() { latestReleaseURL, version.Version = origURL, origVersion })
+}
+
+func TestCheckInBackground_DoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	withRelease(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"tag_name":"v1.2.0"}`))
+	})
+	store := &MemoryStateStore{}
+
+	start := time.Now()
+	done := CheckInBackground(store)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("check finished before the release endpoint answered")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background check did not finish")
+	}
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", state.LatestVersion)
+	assert.WithinDuration(t, time.Now(), state.LastChecked, time.Minute)
+}
+
+func TestCheckInBackground_RecordsAttemptAfterLookup(t *testing.T) {
+	release := make(chan struct{})
+	withRelease(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	store := &MemoryStateStore{}
+
+	done := CheckInBackground(store)
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, state.LastChecked.IsZero(), "attempt recorded before the lookup finished")
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background check did not finish")
+	}
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), state.LastChecked, time.Minute)
+	assert.Empty(t, state.LatestVersion)
+}
+
+func TestCheckInBackground_SkipsRecentCheck(t *testing.T) {
+	var requests atomic.Int32
+	withRelease(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	})
+	store := &MemoryStateStore{state: State{LastChecked: time.Now().Add(-time.Hour)}}
+
+	<-CheckInBackground(store)
+	assert.Zero(t, requests.Load())
+}
+
+func TestNotifyIfOutdated_OncePerDay(t *testing.T) {
+	var requests atomic.Int32
+	withRelease(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	})
+	store := &MemoryStateStore{state: State{LatestVersion: "v1.2.0"}}
+
+	var out bytes.Buffer
+	NotifyIfOutdated(&out, store)
+	assert.Contains(t, out.String(), "v1.0.0 -> v1.2.0")
+	state, err := store.Load()
+	require.NoError(t, err)
+	notified := state.LastNotified
+	assert.False(t, notified.IsZero())
+
+	out.Reset()
+	NotifyIfOutdated(&out, store)
+	assert.Empty(t, out.String())
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.True(t, notified.Equal(state.LastNotified))
+	assert.Zero(t, requests.Load())
+}
+
+func TestFileStateStore(t *testing.T) {
+	t.Setenv("JW_CONFIG_DIR", t.TempDir())
+	store, err := NewFileStateStore()
+	require.NoError(t, err)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Zero(t, state)
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, store.Update(func(s *State) {
+		s.LatestVersion = "v1.2.0"
+		s.LastChecked = now
+	}))
+	reopened, err := NewFileStateStore()
+	require.NoError(t, err)
+	state, err = reopened.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", state.LatestVersion)
+	assert.True(t, now.Equal(state.LastChecked))
+}
+
+func TestFileStateStore_MigratesConfigState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("JW_CONFIG_DIR", dir)
+	legacy := `{"jobs":{},"upgrade_check":{"last_checked":"2026-01-02T03:04:05Z","latest_version":"v1.1.0"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "monitored_jobs.json"), []byte(legacy), 0o644))
+	store, err := NewFileStateStore()
+	require.NoError(t, err)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", state.LatestVersion)
+	assert.True(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Equal(state.LastChecked))
+
+	require.NoError(t, store.Update(func(s *State) { s.LatestVersion = "v1.2.0" }))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "monitored_jobs.json"), []byte(`{"jobs":{}}`), 0o644))
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.0", state.LatestVersion, "the state file wins once written")
+	assert.True(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Equal(state.LastChecked))
+}
+
+func TestCurrentVersion(t *testing.T) {
+	orig := version.Version
+	t.Cleanup(func() { version.Version = orig })
+	tests := map[string]string{
+		"v1.3.0":                  "v1.3.0",
+		"1.3.0":                   "v1.3.0",
+		"v1.3.0-rc.1":             "v1.3.0-rc.1",
+		"v1.3.0-4-gdeadbee":       "v1.3.0",
+		"v1.3.0-rc.1-4-gdeadbee":  "v1.3.0-rc.1",
+		"v1.3.0-4-gdeadbee-dirty": "v1.3.0",
+		"v1.3.0-dirty":            "v1.3.0",
+		"dev":                     "",
+		"not-a-version":           "",
+	}
+	for in, want := range tests {
+		version.Version = in
+		assert.Equal(t, want, currentVersion(), in)
+	}
+}
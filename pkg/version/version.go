@@ -1,9 +1,26 @@
 package version
 
-import "runtime/debug"
+import (
+	"runtime"
+	"runtime/debug"
+)
 
-// Version is set via ldflags during build
-var Version string
+// Version, Commit and BuildDate are set via ldflags during build
+var (
+	Version   string
+	Commit    string
+	BuildDate string
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	BuildDate string `json:"build_date"`
+	Commit    string `json:"commit"`
+}
 
 func GetVersion() string {
 	// If version was set via ldflags, use it
@@ -23,3 +40,28 @@ func GetVersion() string {
 	}
 	return version
 }
+
+// Get returns the version and build details of the running binary. Commit
+// and BuildDate fall back to the VCS details Go embeds in local builds, and
+// are empty if neither is known.
+func Get() BuildInfo {
+	info := BuildInfo{
+		Version:   GetVersion(),
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		BuildDate: BuildDate,
+		Commit:    Commit,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range build.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = s.Value
+			}
+		}
+	}
+	return info
+}
@@ -0,0 +1,23 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_UsesLdflags(t *testing.T) {
+	orig := [3]string{Version, Commit, BuildDate}
+	t.Cleanup(func() { Version, Commit, BuildDate = orig[0], orig[1], orig[2] })
+	Version, Commit, BuildDate = "v1.2.0", "abc123", "2026-10-01T12:00:00Z"
+
+	assert.Equal(t, BuildInfo{
+		Version:   "v1.2.0",
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		BuildDate: "2026-10-01T12:00:00Z",
+		Commit:    "abc123",
+	}, Get())
+}